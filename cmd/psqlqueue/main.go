@@ -47,13 +47,16 @@ func main() {
 					messageRepository := repository.NewMessage(pool)
 					topicRepository := repository.NewTopic(pool)
 					subscriptionRepository := repository.NewSubscription(pool)
+					topicMessageRepository := repository.NewTopicMessage(pool)
+					topicStatsRepository := repository.NewTopicStats(pool)
 					healthCheckRepository := repository.NewHealthCheck(pool)
+					messageEventRepository := repository.NewMessageEvent(pool)
 
 					// services
 					queueService := service.NewQueue(queueRepository)
-					messageService := service.NewMessage(messageRepository, queueRepository)
-					topicService := service.NewTopic(topicRepository, subscriptionRepository, queueRepository, messageRepository)
-					subscriptionService := service.NewSubscription(subscriptionRepository)
+					messageService := service.NewMessage(messageRepository, queueRepository, messageEventRepository, cfg.MessageMaxConcurrentRequests)
+					topicService := service.NewTopic(topicRepository, subscriptionRepository, queueRepository, messageRepository, topicMessageRepository, topicStatsRepository, cfg.TopicMaxHops)
+					subscriptionService := service.NewSubscription(subscriptionRepository, topicRepository, queueRepository, topicMessageRepository, messageRepository, cfg.TopicMaxHops)
 					healthCheckService := service.NewHealthCheck(healthCheckRepository)
 
 					// http handlers
@@ -0,0 +1,3 @@
+ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ;
+UPDATE subscriptions SET updated_at = created_at WHERE updated_at IS NULL;
+ALTER TABLE subscriptions ALTER COLUMN updated_at SET NOT NULL;
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS subscriptions_queue_id_idx;
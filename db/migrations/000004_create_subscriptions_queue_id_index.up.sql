@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS subscriptions_queue_id_idx ON subscriptions (queue_id);
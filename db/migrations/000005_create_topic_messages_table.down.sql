@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS topic_messages;
+ALTER TABLE topics DROP COLUMN IF EXISTS message_retention_seconds;
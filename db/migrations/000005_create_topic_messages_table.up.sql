@@ -0,0 +1,14 @@
+ALTER TABLE topics ADD COLUMN IF NOT EXISTS message_retention_seconds INT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS topic_messages(
+    id VARCHAR PRIMARY KEY NOT NULL,
+    topic_id VARCHAR NOT NULL,
+    body VARCHAR NOT NULL,
+    label VARCHAR,
+    attributes JSONB,
+    expired_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    FOREIGN KEY (topic_id) REFERENCES topics (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS topic_messages_topic_id_created_at_idx ON topic_messages (topic_id, created_at);
+CREATE INDEX IF NOT EXISTS topic_messages_expired_at_idx ON topic_messages USING BRIN (expired_at);
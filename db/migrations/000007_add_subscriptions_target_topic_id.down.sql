@@ -0,0 +1,5 @@
+DELETE FROM subscriptions WHERE target_topic_id IS NOT NULL;
+DROP INDEX IF EXISTS subscriptions_topic_id_target_topic_id_idx;
+ALTER TABLE subscriptions DROP CONSTRAINT IF EXISTS subscriptions_target_check;
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS target_topic_id;
+ALTER TABLE subscriptions ALTER COLUMN queue_id SET NOT NULL;
@@ -0,0 +1,5 @@
+ALTER TABLE subscriptions ALTER COLUMN queue_id DROP NOT NULL;
+ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS target_topic_id VARCHAR;
+ALTER TABLE subscriptions ADD CONSTRAINT subscriptions_target_topic_id_fkey FOREIGN KEY (target_topic_id) REFERENCES topics (id) ON DELETE CASCADE;
+ALTER TABLE subscriptions ADD CONSTRAINT subscriptions_target_check CHECK ((queue_id IS NULL) <> (target_topic_id IS NULL));
+CREATE UNIQUE INDEX IF NOT EXISTS subscriptions_topic_id_target_topic_id_idx ON subscriptions (topic_id, target_topic_id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS subscription_stats;
+DROP TABLE IF EXISTS topic_stats;
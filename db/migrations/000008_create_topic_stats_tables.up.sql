@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS topic_stats(
+    topic_id VARCHAR NOT NULL,
+    bucket TIMESTAMPTZ NOT NULL,
+    num_published_messages INT NOT NULL,
+    PRIMARY KEY (topic_id, bucket),
+    FOREIGN KEY (topic_id) REFERENCES topics (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS subscription_stats(
+    subscription_id VARCHAR NOT NULL,
+    topic_id VARCHAR NOT NULL,
+    bucket TIMESTAMPTZ NOT NULL,
+    num_delivered_messages INT NOT NULL,
+    num_filtered_messages INT NOT NULL,
+    PRIMARY KEY (subscription_id, bucket),
+    FOREIGN KEY (subscription_id) REFERENCES subscriptions (id) ON DELETE CASCADE,
+    FOREIGN KEY (topic_id) REFERENCES topics (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS subscription_stats_topic_id_bucket_idx ON subscription_stats (topic_id, bucket);
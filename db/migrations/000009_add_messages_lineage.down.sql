@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS messages_publish_id_idx;
+ALTER TABLE messages DROP COLUMN IF EXISTS source_subscription_id;
+ALTER TABLE messages DROP COLUMN IF EXISTS source_topic_id;
+ALTER TABLE messages DROP COLUMN IF EXISTS publish_id;
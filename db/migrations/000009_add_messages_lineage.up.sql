@@ -0,0 +1,4 @@
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS publish_id VARCHAR;
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS source_topic_id VARCHAR;
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS source_subscription_id VARCHAR;
+CREATE INDEX IF NOT EXISTS messages_publish_id_idx ON messages (publish_id);
@@ -0,0 +1 @@
+ALTER TABLE topics ADD COLUMN IF NOT EXISTS ingestion JSONB;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS messages_queue_id_correlation_id_idx;
+ALTER TABLE messages DROP COLUMN IF EXISTS correlation_id;
+ALTER TABLE messages DROP COLUMN IF EXISTS reply_to;
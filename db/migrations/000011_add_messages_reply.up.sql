@@ -0,0 +1,3 @@
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS reply_to VARCHAR;
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS correlation_id VARCHAR;
+CREATE INDEX IF NOT EXISTS messages_queue_id_correlation_id_idx ON messages (queue_id, correlation_id);
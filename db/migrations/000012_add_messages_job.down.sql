@@ -0,0 +1,6 @@
+ALTER TABLE messages DROP COLUMN IF EXISTS result_expired_at;
+ALTER TABLE messages DROP COLUMN IF EXISTS result;
+ALTER TABLE messages DROP COLUMN IF EXISTS result_status;
+ALTER TABLE messages DROP COLUMN IF EXISTS progress_note;
+ALTER TABLE messages DROP COLUMN IF EXISTS progress_percent;
+ALTER TABLE queues DROP COLUMN IF EXISTS result_retention_seconds;
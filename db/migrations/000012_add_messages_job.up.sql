@@ -0,0 +1,6 @@
+ALTER TABLE queues ADD COLUMN IF NOT EXISTS result_retention_seconds INT NOT NULL DEFAULT 0;
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS progress_percent INT;
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS progress_note VARCHAR;
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS result_status VARCHAR;
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS result VARCHAR;
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS result_expired_at TIMESTAMPTZ;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS messages_queue_id_unique_key_idx;
+ALTER TABLE messages DROP COLUMN IF EXISTS unique_key;
+ALTER TABLE queues DROP COLUMN IF EXISTS unique_key_policy;
@@ -0,0 +1,3 @@
+ALTER TABLE queues ADD COLUMN IF NOT EXISTS unique_key_policy VARCHAR NOT NULL DEFAULT 'reject';
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS unique_key VARCHAR;
+CREATE UNIQUE INDEX IF NOT EXISTS messages_queue_id_unique_key_idx ON messages (queue_id, unique_key) WHERE unique_key IS NOT NULL;
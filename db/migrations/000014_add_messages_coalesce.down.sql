@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS queue_stats;
+DROP INDEX IF EXISTS messages_queue_id_coalesce_key_idx;
+ALTER TABLE messages DROP COLUMN IF EXISTS coalesce_key;
+ALTER TABLE queues DROP COLUMN IF EXISTS coalesce;
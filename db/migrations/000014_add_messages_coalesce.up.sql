@@ -0,0 +1,9 @@
+ALTER TABLE queues ADD COLUMN IF NOT EXISTS coalesce BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS coalesce_key VARCHAR;
+CREATE INDEX IF NOT EXISTS messages_queue_id_coalesce_key_idx ON messages (queue_id, coalesce_key) WHERE coalesce_key IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS queue_stats(
+    queue_id VARCHAR PRIMARY KEY NOT NULL,
+    num_coalesced_messages BIGINT NOT NULL DEFAULT 0,
+    FOREIGN KEY (queue_id) REFERENCES queues (id) ON DELETE CASCADE
+);
@@ -0,0 +1,4 @@
+ALTER TABLE messages DROP COLUMN IF EXISTS leased_at;
+ALTER TABLE queues DROP COLUMN IF EXISTS max_in_flight_per_key;
+ALTER TABLE queues DROP COLUMN IF EXISTS delivery_key_attribute;
+ALTER TABLE queues DROP COLUMN IF EXISTS delivery_key_source;
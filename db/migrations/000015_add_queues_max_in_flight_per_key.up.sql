@@ -0,0 +1,4 @@
+ALTER TABLE queues ADD COLUMN IF NOT EXISTS delivery_key_source VARCHAR NOT NULL DEFAULT 'label';
+ALTER TABLE queues ADD COLUMN IF NOT EXISTS delivery_key_attribute VARCHAR;
+ALTER TABLE queues ADD COLUMN IF NOT EXISTS max_in_flight_per_key INT NOT NULL DEFAULT 0;
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS leased_at TIMESTAMPTZ;
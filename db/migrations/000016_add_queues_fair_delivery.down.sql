@@ -0,0 +1,2 @@
+ALTER TABLE queues DROP COLUMN IF EXISTS fair_delivery_weights;
+ALTER TABLE queues DROP COLUMN IF EXISTS fair_delivery;
@@ -0,0 +1,2 @@
+ALTER TABLE queues ADD COLUMN IF NOT EXISTS fair_delivery BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE queues ADD COLUMN IF NOT EXISTS fair_delivery_weights JSONB;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS queue_rate_limits;
+ALTER TABLE queues DROP COLUMN IF EXISTS delivery_burst;
+ALTER TABLE queues DROP COLUMN IF EXISTS max_deliveries_per_second;
@@ -0,0 +1,9 @@
+ALTER TABLE queues ADD COLUMN IF NOT EXISTS max_deliveries_per_second INT NOT NULL DEFAULT 0;
+ALTER TABLE queues ADD COLUMN IF NOT EXISTS delivery_burst INT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS queue_rate_limits(
+    queue_id VARCHAR PRIMARY KEY NOT NULL,
+    tokens DOUBLE PRECISION NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    FOREIGN KEY (queue_id) REFERENCES queues (id) ON DELETE CASCADE
+);
@@ -0,0 +1 @@
+ALTER TABLE queues DROP COLUMN IF EXISTS max_visibility_timeout_seconds;
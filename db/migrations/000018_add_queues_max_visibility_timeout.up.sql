@@ -0,0 +1 @@
+ALTER TABLE queues ADD COLUMN IF NOT EXISTS max_visibility_timeout_seconds INT NOT NULL DEFAULT 0;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS message_events;
+ALTER TABLE queues DROP COLUMN IF EXISTS delivery_log_retention_seconds;
+ALTER TABLE queues DROP COLUMN IF EXISTS delivery_log;
@@ -0,0 +1,15 @@
+ALTER TABLE queues ADD COLUMN IF NOT EXISTS delivery_log BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE queues ADD COLUMN IF NOT EXISTS delivery_log_retention_seconds INT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS message_events(
+    id BIGSERIAL PRIMARY KEY,
+    message_id VARCHAR NOT NULL,
+    queue_id VARCHAR NOT NULL,
+    type VARCHAR NOT NULL,
+    consumer_id VARCHAR,
+    created_at TIMESTAMPTZ NOT NULL,
+    expired_at TIMESTAMPTZ NOT NULL,
+    FOREIGN KEY (queue_id) REFERENCES queues (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS message_events_message_id_idx ON message_events (message_id, id);
+CREATE INDEX IF NOT EXISTS message_events_queue_id_expired_at_idx ON message_events (queue_id, expired_at);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS messages_queue_id_state_idx;
+UPDATE messages SET expired_at = acked_at, updated_at = acked_at WHERE state = 'acked';
+ALTER TABLE queue_stats DROP COLUMN IF EXISTS num_expired_messages;
+ALTER TABLE messages DROP COLUMN IF EXISTS acked_at;
+ALTER TABLE messages DROP COLUMN IF EXISTS state;
+ALTER TABLE queues DROP COLUMN IF EXISTS acked_retention_seconds;
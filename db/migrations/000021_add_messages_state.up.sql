@@ -0,0 +1,11 @@
+ALTER TABLE queues ADD COLUMN IF NOT EXISTS acked_retention_seconds INT NOT NULL DEFAULT 0;
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS state VARCHAR NOT NULL DEFAULT 'ready';
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS acked_at TIMESTAMPTZ;
+ALTER TABLE queue_stats ADD COLUMN IF NOT EXISTS num_expired_messages BIGINT NOT NULL DEFAULT 0;
+
+UPDATE messages SET state = 'acked', acked_at = expired_at WHERE expired_at = updated_at;
+-- the messages delivered before the leased_at column have only the delivery attempts, a message nacked before the upgrade is in flight until its visibility timeout.
+UPDATE messages SET state = 'in_flight', leased_at = COALESCE(leased_at, updated_at)
+WHERE state = 'ready' AND (leased_at IS NOT NULL OR delivery_attempts > 0) AND scheduled_at > NOW();
+
+CREATE INDEX IF NOT EXISTS messages_queue_id_state_idx ON messages (queue_id, state);
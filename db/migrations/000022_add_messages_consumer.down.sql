@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS messages_queue_id_consumer_id_idx;
+ALTER TABLE messages DROP COLUMN IF EXISTS consumer_id;
@@ -0,0 +1,2 @@
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS consumer_id VARCHAR;
+CREATE INDEX IF NOT EXISTS messages_queue_id_consumer_id_idx ON messages (queue_id, consumer_id) WHERE consumer_id IS NOT NULL;
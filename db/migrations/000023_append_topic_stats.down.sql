@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS topic_stats_topic_id_bucket_idx;
+WITH deleted AS (DELETE FROM topic_stats RETURNING *)
+INSERT INTO topic_stats (topic_id, bucket, num_published_messages)
+SELECT topic_id, bucket, SUM(num_published_messages) FROM deleted GROUP BY topic_id, bucket;
+WITH deleted AS (DELETE FROM subscription_stats RETURNING *)
+INSERT INTO subscription_stats (subscription_id, topic_id, bucket, num_delivered_messages, num_filtered_messages)
+SELECT subscription_id, MIN(topic_id), bucket, SUM(num_delivered_messages), SUM(num_filtered_messages) FROM deleted GROUP BY subscription_id, bucket;
+ALTER TABLE topic_stats ADD PRIMARY KEY (topic_id, bucket);
+ALTER TABLE subscription_stats ADD PRIMARY KEY (subscription_id, bucket);
@@ -0,0 +1,3 @@
+ALTER TABLE topic_stats DROP CONSTRAINT IF EXISTS topic_stats_pkey;
+ALTER TABLE subscription_stats DROP CONSTRAINT IF EXISTS subscription_stats_pkey;
+CREATE INDEX IF NOT EXISTS topic_stats_topic_id_bucket_idx ON topic_stats (topic_id, bucket);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS queues_expired_at_idx;
+ALTER TABLE queues DROP COLUMN IF EXISTS expired_at;
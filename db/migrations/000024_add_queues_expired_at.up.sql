@@ -0,0 +1,2 @@
+ALTER TABLE queues ADD COLUMN IF NOT EXISTS expired_at TIMESTAMPTZ;
+CREATE INDEX IF NOT EXISTS queues_expired_at_idx ON queues (expired_at) WHERE expired_at IS NOT NULL;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS messages_queue_id_unique_key_idx;
+UPDATE messages SET unique_key = NULL WHERE unique_key IS NOT NULL AND state NOT IN ('ready', 'in_flight');
+CREATE UNIQUE INDEX IF NOT EXISTS messages_queue_id_unique_key_idx ON messages (queue_id, unique_key) WHERE unique_key IS NOT NULL;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS messages_queue_id_unique_key_idx;
+CREATE UNIQUE INDEX IF NOT EXISTS messages_queue_id_unique_key_idx ON messages (queue_id, unique_key) WHERE unique_key IS NOT NULL AND state IN ('ready', 'in_flight');
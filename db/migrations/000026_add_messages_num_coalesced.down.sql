@@ -0,0 +1,4 @@
+INSERT INTO queue_stats (queue_id, num_coalesced_messages)
+SELECT queue_id, SUM(num_coalesced) FROM messages WHERE num_coalesced > 0 GROUP BY queue_id
+ON CONFLICT (queue_id) DO UPDATE SET num_coalesced_messages = queue_stats.num_coalesced_messages + EXCLUDED.num_coalesced_messages;
+ALTER TABLE messages DROP COLUMN IF EXISTS num_coalesced;
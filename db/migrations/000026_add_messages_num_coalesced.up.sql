@@ -0,0 +1 @@
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS num_coalesced INT NOT NULL DEFAULT 0;
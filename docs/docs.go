@@ -43,10 +43,113 @@ const docTemplate = `{
                 }
             }
         },
+        "/messages": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "messages"
+                ],
+                "summary": "List messages from multiple queues",
+                "parameters": [
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Queue ids, polled in the given order",
+                        "name": "queue_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by label",
+                        "name": "label",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "The limit indicates the maximum number of items to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "cloudevents"
+                        ],
+                        "type": "string",
+                        "description": "Render the messages as CloudEvents",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "The lease of the messages, bounded by the max visibility timeout of each queue (default ack_deadline_seconds)",
+                        "name": "visibility_timeout_seconds",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "manual",
+                            "auto"
+                        ],
+                        "type": "string",
+                        "description": "Use auto to ack the messages on the delivery, the messages are delivered at most once (default manual)",
+                        "name": "ack_mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Identify the consumer that holds the lease of the messages",
+                        "name": "Consumer-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/MessageListResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds until the queue delivery rate limit allows new deliveries"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/queue/{queue_id}/messages": {
             "post": {
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "application/cloudevents+json"
                 ],
                 "produces": [
                     "application/json"
@@ -83,6 +186,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -354,8 +463,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/queues/{queue_id}/messages": {
-            "get": {
+        "/queues/{queue_id}/consumers/{consumer_id}/release": {
+            "put": {
                 "consumes": [
                     "application/json"
                 ],
@@ -365,7 +474,7 @@ const docTemplate = `{
                 "tags": [
                     "messages"
                 ],
-                "summary": "List messages",
+                "summary": "Release the leases held by a consumer, the messages are available for delivery right away",
                 "parameters": [
                     {
                         "type": "string",
@@ -376,23 +485,15 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Filter by label",
-                        "name": "label",
-                        "in": "path"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "The limit indicates the maximum number of items to return",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "Consumer id",
+                        "name": "consumer_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/MessageListResponse"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "404": {
                         "description": "Not Found",
@@ -409,8 +510,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/queues/{queue_id}/messages/{message_id}/ack": {
-            "put": {
+        "/queues/{queue_id}/in-flight": {
+            "get": {
                 "consumes": [
                     "application/json"
                 ],
@@ -420,7 +521,7 @@ const docTemplate = `{
                 "tags": [
                     "messages"
                 ],
-                "summary": "Ack a message",
+                "summary": "List the leased messages of a queue",
                 "parameters": [
                     {
                         "type": "string",
@@ -430,16 +531,24 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Message id",
-                        "name": "message_id",
-                        "in": "path",
-                        "required": true
+                        "type": "integer",
+                        "description": "The limit indicates the maximum number of items to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "The offset indicates the starting position of the query in relation to the complete set of unpaginated items",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/MessageLeaseListResponse"
+                        }
                     },
                     "404": {
                         "description": "Not Found",
@@ -456,8 +565,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/queues/{queue_id}/messages/{message_id}/nack": {
-            "put": {
+        "/queues/{queue_id}/messages": {
+            "get": {
                 "consumes": [
                     "application/json"
                 ],
@@ -467,7 +576,7 @@ const docTemplate = `{
                 "tags": [
                     "messages"
                 ],
-                "summary": "Nack a message",
+                "summary": "List messages",
                 "parameters": [
                     {
                         "type": "string",
@@ -478,24 +587,66 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Message id",
-                        "name": "message_id",
-                        "in": "path",
-                        "required": true
+                        "description": "Filter by label",
+                        "name": "label",
+                        "in": "path"
                     },
                     {
-                        "description": "Nack a message",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/MessageNackRequest"
-                        }
+                        "type": "integer",
+                        "description": "The limit indicates the maximum number of items to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "cloudevents"
+                        ],
+                        "type": "string",
+                        "description": "Render the messages as CloudEvents",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "The lease of the messages, bounded by the max visibility timeout of the queue (default ack_deadline_seconds)",
+                        "name": "visibility_timeout_seconds",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "manual",
+                            "auto"
+                        ],
+                        "type": "string",
+                        "description": "Use auto to ack the messages on the delivery, the messages are delivered at most once (default manual)",
+                        "name": "ack_mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Identify the consumer that holds the lease of the messages",
+                        "name": "Consumer-ID",
+                        "in": "header"
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/MessageListResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds until the queue delivery rate limit allows new deliveries"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
                     },
                     "404": {
                         "description": "Not Found",
@@ -512,8 +663,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/queues/{queue_id}/purge": {
-            "put": {
+        "/queues/{queue_id}/messages/{message_id}": {
+            "get": {
                 "consumes": [
                     "application/json"
                 ],
@@ -521,9 +672,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "queues"
+                    "messages"
                 ],
-                "summary": "Purge a queue",
+                "summary": "Show the job status, progress and result of a message",
                 "parameters": [
                     {
                         "type": "string",
@@ -531,11 +682,21 @@ const docTemplate = `{
                         "name": "queue_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Message id",
+                        "name": "message_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/MessageJobResponse"
+                        }
                     },
                     "404": {
                         "description": "Not Found",
@@ -552,8 +713,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/queues/{queue_id}/stats": {
-            "get": {
+        "/queues/{queue_id}/messages/{message_id}/ack": {
+            "put": {
                 "consumes": [
                     "application/json"
                 ],
@@ -561,9 +722,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "queues"
+                    "messages"
                 ],
-                "summary": "Get the queue stats",
+                "summary": "Ack a message",
                 "parameters": [
                     {
                         "type": "string",
@@ -571,13 +732,32 @@ const docTemplate = `{
                         "name": "queue_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Message id",
+                        "name": "message_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Store the job result",
+                        "name": "request",
+                        "in": "body",
+                        "required": false,
+                        "schema": {
+                            "$ref": "#/definitions/MessageAckRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/QueueStatsResponse"
+                            "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "404": {
@@ -595,7 +775,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/subscriptions": {
+        "/queues/{queue_id}/messages/{message_id}/events": {
             "get": {
                 "consumes": [
                     "application/json"
@@ -604,10 +784,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "subscriptions"
+                    "messages"
                 ],
-                "summary": "List subscriptions",
+                "summary": "List the delivery log events of a message",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Queue id",
+                        "name": "queue_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Message id",
+                        "name": "message_id",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "type": "integer",
                         "description": "The limit indicates the maximum number of items to return",
@@ -625,7 +819,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/SubscriptionListResponse"
+                            "$ref": "#/definitions/MessageEventListResponse"
                         }
                     },
                     "500": {
@@ -635,8 +829,10 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/queues/{queue_id}/messages/{message_id}/nack": {
+            "put": {
                 "consumes": [
                     "application/json"
                 ],
@@ -644,26 +840,37 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "subscriptions"
+                    "messages"
                 ],
-                "summary": "Add a subscription",
+                "summary": "Nack a message",
                 "parameters": [
                     {
-                        "description": "Add a subscription",
+                        "type": "string",
+                        "description": "Queue id",
+                        "name": "queue_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Message id",
+                        "name": "message_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Nack a message, optionally reporting the error",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/SubscriptionRequest"
+                            "$ref": "#/definitions/MessageNackRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/TopicResponse"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -671,6 +878,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -680,8 +899,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/subscriptions/{subscription_id}": {
-            "get": {
+        "/queues/{queue_id}/messages/{message_id}/progress": {
+            "put": {
                 "consumes": [
                     "application/json"
                 ],
@@ -689,23 +908,42 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "subscriptions"
+                    "messages"
                 ],
-                "summary": "Show a subscription",
+                "summary": "Update the job progress of an in flight message",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Subscription id",
-                        "name": "subscription_id",
+                        "description": "Queue id",
+                        "name": "queue_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Message id",
+                        "name": "message_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Update the progress",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/MessageProgressRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/SubscriptionResponse"
+                            "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "404": {
@@ -714,6 +952,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -721,8 +965,10 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/queues/{queue_id}/purge": {
+            "put": {
                 "consumes": [
                     "application/json"
                 ],
@@ -730,14 +976,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "subscriptions"
+                    "queues"
                 ],
-                "summary": "Delete a subscription",
+                "summary": "Purge a queue",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Subscription id",
-                        "name": "subscription_id",
+                        "description": "Queue id",
+                        "name": "queue_id",
                         "in": "path",
                         "required": true
                     }
@@ -761,37 +1007,66 @@ const docTemplate = `{
                 }
             }
         },
-        "/topics": {
-            "get": {
+        "/queues/{queue_id}/request": {
+            "post": {
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "application/cloudevents+json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "topics"
+                    "messages"
                 ],
-                "summary": "List topics",
+                "summary": "Add a message and wait for the reply with the same correlation id",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "The limit indicates the maximum number of items to return",
-                        "name": "limit",
-                        "in": "query"
+                        "type": "string",
+                        "description": "Queue id",
+                        "name": "queue_id",
+                        "in": "path",
+                        "required": true
                     },
                     {
                         "type": "integer",
-                        "description": "The offset indicates the starting position of the query in relation to the complete set of unpaginated items",
-                        "name": "offset",
+                        "description": "The maximum time to wait for the reply",
+                        "name": "timeout_seconds",
                         "in": "query"
+                    },
+                    {
+                        "description": "Add a message",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/MessageRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/QueueListResponse"
+                            "$ref": "#/definitions/MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "500": {
@@ -799,10 +1074,18 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/queues/{queue_id}/seek": {
+            "put": {
                 "consumes": [
                     "application/json"
                 ],
@@ -810,26 +1093,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "topics"
+                    "queues"
                 ],
-                "summary": "Add a topic",
+                "summary": "Deliver again the acked messages after a timestamp",
                 "parameters": [
                     {
-                        "description": "Add a topic",
+                        "type": "string",
+                        "description": "Queue id",
+                        "name": "queue_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Seek the queue",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/TopicRequest"
+                            "$ref": "#/definitions/QueueSeekRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/TopicResponse"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -837,6 +1124,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -846,7 +1139,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/topics/{topic_id}": {
+        "/queues/{queue_id}/stats": {
             "get": {
                 "consumes": [
                     "application/json"
@@ -855,14 +1148,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "topics"
+                    "queues"
                 ],
-                "summary": "Show a topic",
+                "summary": "Get the queue stats",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Topic id",
-                        "name": "topic_id",
+                        "description": "Queue id",
+                        "name": "queue_id",
                         "in": "path",
                         "required": true
                     }
@@ -871,7 +1164,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/TopicResponse"
+                            "$ref": "#/definitions/QueueStatsResponse"
                         }
                     },
                     "404": {
@@ -887,8 +1180,10 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/queues/{queue_id}/subscriptions": {
+            "get": {
                 "consumes": [
                     "application/json"
                 ],
@@ -896,21 +1191,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "topics"
+                    "subscriptions"
                 ],
-                "summary": "Delete a topic",
+                "summary": "List the subscriptions that deliver messages to a queue",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Topic id",
-                        "name": "topic_id",
+                        "description": "Queue id",
+                        "name": "queue_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "The limit indicates the maximum number of items to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "The offset indicates the starting position of the query in relation to the complete set of unpaginated items",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/SubscriptionListResponse"
+                        }
                     },
                     "404": {
                         "description": "Not Found",
@@ -927,7 +1237,47 @@ const docTemplate = `{
                 }
             }
         },
-        "/topics/{topic_id}/messages": {
+        "/subscriptions": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "List subscriptions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "The limit indicates the maximum number of items to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "The offset indicates the starting position of the query in relation to the complete set of unpaginated items",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/SubscriptionListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
             "post": {
                 "consumes": [
                     "application/json"
@@ -936,17 +1286,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "topics"
+                    "subscriptions"
                 ],
-                "summary": "Add a message",
+                "summary": "Add a subscription",
                 "parameters": [
                     {
-                        "description": "Add a message",
+                        "description": "Add a subscription",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/MessageRequest"
+                            "$ref": "#/definitions/SubscriptionRequest"
                         }
                     }
                 ],
@@ -971,205 +1321,1878 @@ const docTemplate = `{
                     }
                 }
             }
-        }
+        },
+        "/subscriptions/{subscription_id}": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Show a subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription id",
+                        "name": "subscription_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/SubscriptionResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Update a subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription id",
+                        "name": "subscription_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update a subscription",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/SubscriptionUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/SubscriptionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Delete a subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription id",
+                        "name": "subscription_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/{subscription_id}/replay": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Replay the messages published to the topic in a time range into the subscription queue",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription id",
+                        "name": "subscription_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Replay a time range",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/SubscriptionReplayRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/SubscriptionReplayResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/topics": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "topics"
+                ],
+                "summary": "List topics",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "The limit indicates the maximum number of items to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "The offset indicates the starting position of the query in relation to the complete set of unpaginated items",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/QueueListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "topics"
+                ],
+                "summary": "Add a topic",
+                "parameters": [
+                    {
+                        "description": "Add a topic",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/TopicRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/TopicResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/topics/{topic_id}": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "topics"
+                ],
+                "summary": "Show a topic",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Topic id",
+                        "name": "topic_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/TopicResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "topics"
+                ],
+                "summary": "Delete a topic",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Topic id",
+                        "name": "topic_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/topics/{topic_id}/cleanup": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "topics"
+                ],
+                "summary": "Cleanup a topic removing expired messages from the message log",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Topic id",
+                        "name": "topic_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/topics/{topic_id}/ingest": {
+            "post": {
+                "consumes": [
+                    "*/*"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "topics"
+                ],
+                "summary": "Add a message from a raw payload, mapping the configured headers to the message attributes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Topic id",
+                        "name": "topic_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Fail when no subscription matches the message",
+                        "name": "require_match",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Raw payload",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/TopicPublishResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/topics/{topic_id}/messages": {
+            "post": {
+                "consumes": [
+                    "application/json",
+                    "application/cloudevents+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "topics"
+                ],
+                "summary": "Add a message",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Topic id",
+                        "name": "topic_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Fail when no subscription matches the message",
+                        "name": "require_match",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Add a message",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/MessageRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/TopicPublishResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/topics/{topic_id}/messages/{publish_id}": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "topics"
+                ],
+                "summary": "Show the queue messages created by a topic publish with their delivery status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Topic id",
+                        "name": "topic_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Publish id",
+                        "name": "publish_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/TopicPublishLineageResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/topics/{topic_id}/simulate": {
+            "post": {
+                "consumes": [
+                    "application/json",
+                    "application/cloudevents+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "topics"
+                ],
+                "summary": "Simulate the routing of a message without enqueueing it",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Topic id",
+                        "name": "topic_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Simulate a message",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/MessageRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/TopicSimulationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/topics/{topic_id}/stats": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "topics"
+                ],
+                "summary": "Get the topic stats",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Topic id",
+                        "name": "topic_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/TopicStatsResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/topics/{topic_id}/subscriptions": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "List the subscriptions of a topic",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Topic id",
+                        "name": "topic_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "The limit indicates the maximum number of items to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "The offset indicates the starting position of the query in relation to the complete set of unpaginated items",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/SubscriptionListResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
     },
     "definitions": {
         "ErrorResponse": {
             "type": "object",
             "properties": {
-                "code": {
-                    "$ref": "#/definitions/ErrorResponseCode"
+                "code": {
+                    "$ref": "#/definitions/ErrorResponseCode"
+                },
+                "details": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "ErrorResponseCode": {
+            "type": "integer",
+            "enum": [
+                1,
+                2,
+                3,
+                4,
+                5,
+                6,
+                7,
+                8,
+                9,
+                10,
+                11,
+                12,
+                13,
+                14,
+                15,
+                16,
+                17,
+                18,
+                19,
+                20
+            ],
+            "x-enum-varnames": [
+                "internalServerErrorCode",
+                "malformedRequest",
+                "requestValidationFailedCode",
+                "queueAlreadyExists",
+                "queueNotFound",
+                "messageNotFound",
+                "topicAlreadyExists",
+                "topicNotFound",
+                "subscriptionAlreadyExists",
+                "subscriptionNotFound",
+                "topicNoMatchingSubscription",
+                "topicMaxHopsExceeded",
+                "subscriptionCycle",
+                "topicPublishNotFound",
+                "topicIngestionInvalidSignature",
+                "messageReplyTimeout",
+                "messageNotInFlight",
+                "messageDuplicated",
+                "messageTooManyRequests",
+                "subscriptionReplayTooLarge"
+            ]
+        },
+        "HealthCheckResponse": {
+            "type": "object",
+            "properties": {
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "MessageAckRequest": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "body": {
+                    "type": "string",
+                    "example": "{\"rows\": 10}"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "succeeded",
+                        "failed"
+                    ],
+                    "example": "succeeded"
+                }
+            }
+        },
+        "MessageErrorResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-08-17T00:00:00Z"
+                },
+                "delivery_attempt": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "error": {
+                    "type": "string",
+                    "example": "connection refused"
+                },
+                "error_code": {
+                    "type": "string",
+                    "example": "ECONNREFUSED"
+                }
+            }
+        },
+        "MessageEventListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/MessageEventResponse"
+                    }
+                },
+                "limit": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "offset": {
+                    "type": "integer",
+                    "example": 0
+                }
+            }
+        },
+        "MessageEventResponse": {
+            "type": "object",
+            "properties": {
+                "consumer_id": {
+                    "type": "string",
+                    "example": "worker-1"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-08-17T00:00:00Z"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "message_id": {
+                    "type": "string",
+                    "example": "01HK651Q52EZMPKBYZGVK0ZX8S"
+                },
+                "queue_id": {
+                    "type": "string",
+                    "example": "my-new-queue"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "enqueue",
+                        "lease",
+                        "ack",
+                        "nack",
+                        "expire",
+                        "release",
+                        "seek"
+                    ],
+                    "example": "lease"
+                }
+            }
+        },
+        "MessageJobResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-08-17T00:00:00Z"
+                },
+                "delivery_attempts": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/MessageErrorResponse"
+                    }
+                },
+                "id": {
+                    "type": "string",
+                    "example": "01HK651Q52EZMPKBYZGVK0ZX8T"
+                },
+                "progress_note": {
+                    "type": "string",
+                    "example": "processing page 10 of 10"
+                },
+                "progress_percent": {
+                    "type": "integer",
+                    "example": 100
+                },
+                "queue_id": {
+                    "type": "string",
+                    "example": "my-new-queue"
+                },
+                "result": {
+                    "type": "string",
+                    "example": "{\"rows\": 10}"
+                },
+                "result_status": {
+                    "type": "string",
+                    "enum": [
+                        "succeeded",
+                        "failed"
+                    ],
+                    "example": "succeeded"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "scheduled",
+                        "available",
+                        "in_flight",
+                        "acked",
+                        "expired"
+                    ],
+                    "example": "acked"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2023-08-17T00:00:00Z"
+                }
+            }
+        },
+        "MessageLeaseListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/MessageLeaseResponse"
+                    }
+                },
+                "limit": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "offset": {
+                    "type": "integer",
+                    "example": 0
+                }
+            }
+        },
+        "MessageLeaseResponse": {
+            "type": "object",
+            "properties": {
+                "consumer_id": {
+                    "type": "string",
+                    "example": "worker-1"
+                },
+                "deadline": {
+                    "type": "string",
+                    "example": "2023-08-17T00:00:30Z"
+                },
+                "delivery_attempts": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "id": {
+                    "type": "string",
+                    "example": "01HK651Q52EZMPKBYZGVK0ZX8S"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "leased_at": {
+                    "type": "string",
+                    "example": "2023-08-17T00:00:00Z"
+                },
+                "queue_id": {
+                    "type": "string",
+                    "example": "my-new-queue"
+                }
+            }
+        },
+        "MessageListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/MessageResponse"
+                    }
+                },
+                "limit": {
+                    "type": "integer",
+                    "example": 10
+                }
+            }
+        },
+        "MessageNackRequest": {
+            "type": "object",
+            "required": [
+                "visibility_timeout_seconds"
+            ],
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "connection refused"
+                },
+                "error_code": {
+                    "type": "string",
+                    "example": "ECONNREFUSED"
+                },
+                "visibility_timeout_seconds": {
+                    "type": "integer"
+                }
+            }
+        },
+        "MessageProgressRequest": {
+            "type": "object",
+            "required": [
+                "percent"
+            ],
+            "properties": {
+                "note": {
+                    "type": "string",
+                    "example": "processing page 5 of 10"
+                },
+                "percent": {
+                    "type": "integer",
+                    "example": 50
+                }
+            }
+        },
+        "MessageRequest": {
+            "type": "object",
+            "required": [
+                "body"
+            ],
+            "properties": {
+                "attributes": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "body": {
+                    "type": "string"
+                },
+                "coalesce_key": {
+                    "type": "string",
+                    "example": "customer-42"
+                },
+                "correlation_id": {
+                    "type": "string",
+                    "example": "01HK651Q52EZMPKBYZGVK0ZX8S"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "reply_to": {
+                    "type": "string",
+                    "example": "my-reply-queue"
+                },
+                "unique_key": {
+                    "type": "string",
+                    "example": "reindex-customer-42"
+                }
+            }
+        },
+        "MessageResponse": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "body": {
+                    "type": "string"
+                },
+                "coalesce_key": {
+                    "type": "string",
+                    "example": "customer-42"
+                },
+                "correlation_id": {
+                    "type": "string",
+                    "example": "01HK651Q52EZMPKBYZGVK0ZX8S"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-08-17T00:00:00Z"
+                },
+                "delivery_attempts": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/MessageErrorResponse"
+                    }
+                },
+                "id": {
+                    "type": "string",
+                    "example": "7b98fe50-affd-4685-bd7d-3ae5e41493af"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "publish_id": {
+                    "type": "string",
+                    "example": "01HK651Q52EZMPKBYZGVK0ZX8S"
+                },
+                "queue_id": {
+                    "type": "string",
+                    "example": "my-new-queue"
+                },
+                "reply_to": {
+                    "type": "string",
+                    "example": "my-reply-queue"
+                },
+                "source_subscription_id": {
+                    "type": "string",
+                    "example": "my-new-subscription"
+                },
+                "source_topic_id": {
+                    "type": "string",
+                    "example": "my-new-topic"
+                },
+                "unique_key": {
+                    "type": "string",
+                    "example": "reindex-customer-42"
+                }
+            }
+        },
+        "QueueListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/TopicResponse"
+                    }
+                },
+                "limit": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "offset": {
+                    "type": "integer",
+                    "example": 0
+                }
+            }
+        },
+        "QueueRequest": {
+            "type": "object",
+            "required": [
+                "ack_deadline_seconds",
+                "delivery_delay_seconds",
+                "id",
+                "message_retention_seconds"
+            ],
+            "properties": {
+                "ack_deadline_seconds": {
+                    "type": "integer",
+                    "example": 30
+                },
+                "acked_retention_seconds": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "coalesce": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "delivery_burst": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "delivery_delay_seconds": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "delivery_key_attribute": {
+                    "type": "string",
+                    "example": "tenant"
+                },
+                "delivery_key_source": {
+                    "type": "string",
+                    "enum": [
+                        "label",
+                        "attribute"
+                    ],
+                    "example": "attribute"
+                },
+                "delivery_log": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "delivery_log_retention_seconds": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "fair_delivery": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "fair_delivery_weights": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "id": {
+                    "type": "string",
+                    "example": "my-new-queue"
+                },
+                "max_deliveries_per_second": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "max_in_flight_per_key": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "max_visibility_timeout_seconds": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "message_retention_seconds": {
+                    "type": "integer",
+                    "example": 604800
+                },
+                "result_retention_seconds": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "unique_key_policy": {
+                    "type": "string",
+                    "enum": [
+                        "reject",
+                        "ignore"
+                    ],
+                    "example": "reject"
+                }
+            }
+        },
+        "QueueResponse": {
+            "type": "object",
+            "properties": {
+                "ack_deadline_seconds": {
+                    "type": "integer",
+                    "example": 30
+                },
+                "acked_retention_seconds": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "coalesce": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-08-17T00:00:00Z"
+                },
+                "delivery_burst": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "delivery_delay_seconds": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "delivery_key_attribute": {
+                    "type": "string",
+                    "example": "tenant"
+                },
+                "delivery_key_source": {
+                    "type": "string",
+                    "enum": [
+                        "label",
+                        "attribute"
+                    ],
+                    "example": "attribute"
+                },
+                "delivery_log": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "delivery_log_retention_seconds": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "fair_delivery": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "fair_delivery_weights": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "id": {
+                    "type": "string",
+                    "example": "my-new-queue"
+                },
+                "max_deliveries_per_second": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "max_in_flight_per_key": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "max_visibility_timeout_seconds": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "message_retention_seconds": {
+                    "type": "integer",
+                    "example": 604800
+                },
+                "result_retention_seconds": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "unique_key_policy": {
+                    "type": "string",
+                    "enum": [
+                        "reject",
+                        "ignore"
+                    ],
+                    "example": "reject"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2023-08-17T00:00:00Z"
+                }
+            }
+        },
+        "QueueSeekRequest": {
+            "type": "object",
+            "required": [
+                "timestamp"
+            ],
+            "properties": {
+                "timestamp": {
+                    "type": "string",
+                    "example": "2023-08-17T00:00:00Z"
+                }
+            }
+        },
+        "QueueStatsResponse": {
+            "type": "object",
+            "properties": {
+                "num_acked_messages": {
+                    "type": "integer",
+                    "example": 0
                 },
-                "details": {
-                    "type": "string"
+                "num_coalesced_messages": {
+                    "type": "integer",
+                    "example": 0
                 },
-                "message": {
-                    "type": "string"
+                "num_expired_messages": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "num_undelivered_messages": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "oldest_unacked_message_age_seconds": {
+                    "type": "integer",
+                    "example": 1
                 }
             }
         },
-        "ErrorResponseCode": {
-            "type": "integer",
-            "enum": [
-                1,
-                2,
-                3,
-                4,
-                5,
-                6,
-                7,
-                8,
-                9,
-                10
+        "QueueUpdateRequest": {
+            "type": "object",
+            "required": [
+                "ack_deadline_seconds",
+                "delivery_delay_seconds",
+                "message_retention_seconds"
             ],
-            "x-enum-varnames": [
-                "internalServerErrorCode",
-                "malformedRequest",
-                "requestValidationFailedCode",
-                "queueAlreadyExists",
-                "queueNotFound",
-                "messageNotFound",
-                "topicAlreadyExists",
-                "topicNotFound",
-                "subscriptionAlreadyExists",
-                "subscriptionNotFound"
-            ]
+            "properties": {
+                "ack_deadline_seconds": {
+                    "type": "integer",
+                    "example": 30
+                },
+                "acked_retention_seconds": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "coalesce": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "delivery_burst": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "delivery_delay_seconds": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "delivery_key_attribute": {
+                    "type": "string",
+                    "example": "tenant"
+                },
+                "delivery_key_source": {
+                    "type": "string",
+                    "enum": [
+                        "label",
+                        "attribute"
+                    ],
+                    "example": "attribute"
+                },
+                "delivery_log": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "delivery_log_retention_seconds": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "fair_delivery": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "fair_delivery_weights": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "max_deliveries_per_second": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "max_in_flight_per_key": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "max_visibility_timeout_seconds": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "message_retention_seconds": {
+                    "type": "integer",
+                    "example": 604800
+                },
+                "result_retention_seconds": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "unique_key_policy": {
+                    "type": "string",
+                    "enum": [
+                        "reject",
+                        "ignore"
+                    ],
+                    "example": "reject"
+                }
+            }
         },
-        "HealthCheckResponse": {
+        "SubscriptionListResponse": {
             "type": "object",
             "properties": {
-                "success": {
-                    "type": "boolean"
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/TopicResponse"
+                    }
+                },
+                "limit": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "offset": {
+                    "type": "integer",
+                    "example": 0
+                }
+            }
+        },
+        "SubscriptionMatchResponse": {
+            "type": "object",
+            "properties": {
+                "matched": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "queue_id": {
+                    "type": "string",
+                    "example": "my-new-queue"
+                },
+                "rejected_filter_key": {
+                    "type": "string",
+                    "example": "status"
+                },
+                "subscription_id": {
+                    "type": "string",
+                    "example": "my-new-subscription"
+                },
+                "target_topic_id": {
+                    "type": "string",
+                    "example": "my-other-topic"
+                }
+            }
+        },
+        "SubscriptionReplayRequest": {
+            "type": "object",
+            "required": [
+                "from"
+            ],
+            "properties": {
+                "from": {
+                    "type": "string",
+                    "example": "2023-08-17T00:00:00Z"
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2023-08-18T00:00:00Z"
+                }
+            }
+        },
+        "SubscriptionReplayResponse": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string",
+                    "example": "2023-08-17T00:00:00Z"
+                },
+                "num_messages": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "publish_id": {
+                    "type": "string",
+                    "example": "01HK651Q52EZMPKBYZGVK0ZX8S"
+                },
+                "queue_id": {
+                    "type": "string",
+                    "example": "my-new-queue"
+                },
+                "subscription_id": {
+                    "type": "string",
+                    "example": "my-new-subscription"
+                },
+                "target_topic_id": {
+                    "type": "string",
+                    "example": "my-other-topic"
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2023-08-18T00:00:00Z"
+                }
+            }
+        },
+        "SubscriptionRequest": {
+            "type": "object",
+            "required": [
+                "id",
+                "topic_id"
+            ],
+            "properties": {
+                "id": {
+                    "type": "string",
+                    "example": "my-new-subscription"
+                },
+                "message_filters": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "queue_id": {
+                    "type": "string",
+                    "example": "my-new-queue"
+                },
+                "target_topic_id": {
+                    "type": "string",
+                    "example": "my-other-topic"
+                },
+                "topic_id": {
+                    "type": "string",
+                    "example": "my-new-topic"
+                },
+                "transformation": {
+                    "$ref": "#/definitions/SubscriptionTransformation"
+                }
+            }
+        },
+        "SubscriptionResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-08-17T00:00:00Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "my-new-subscription"
+                },
+                "message_filters": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "queue_id": {
+                    "type": "string",
+                    "example": "my-new-queue"
+                },
+                "target_topic_id": {
+                    "type": "string",
+                    "example": "my-other-topic"
+                },
+                "topic_id": {
+                    "type": "string",
+                    "example": "my-new-topic"
+                },
+                "transformation": {
+                    "$ref": "#/definitions/SubscriptionTransformation"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2023-08-17T00:00:00Z"
+                }
+            }
+        },
+        "SubscriptionStatsResponse": {
+            "type": "object",
+            "properties": {
+                "num_delivered_messages": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "num_filtered_messages": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "subscription_id": {
+                    "type": "string",
+                    "example": "my-new-subscription"
                 }
             }
         },
-        "MessageListResponse": {
+        "SubscriptionTransformation": {
             "type": "object",
             "properties": {
-                "data": {
+                "envelope": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "remove_attributes": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/MessageResponse"
+                        "type": "string"
+                    },
+                    "example": [
+                        "internal_id"
+                    ]
+                },
+                "set_attributes": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
                     }
                 },
-                "limit": {
-                    "type": "integer",
-                    "example": 10
+                "set_label": {
+                    "type": "string",
+                    "example": "orders"
                 }
             }
         },
-        "MessageNackRequest": {
+        "SubscriptionUpdateRequest": {
             "type": "object",
-            "required": [
-                "visibility_timeout_seconds"
-            ],
             "properties": {
-                "visibility_timeout_seconds": {
-                    "type": "integer"
+                "message_filters": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "transformation": {
+                    "$ref": "#/definitions/SubscriptionTransformation"
                 }
             }
         },
-        "MessageRequest": {
+        "TopicIngestionRequest": {
             "type": "object",
-            "required": [
-                "body"
-            ],
             "properties": {
-                "attributes": {
+                "header_attributes": {
                     "type": "object",
                     "additionalProperties": {
                         "type": "string"
                     }
                 },
-                "body": {
-                    "type": "string"
+                "label_header": {
+                    "type": "string",
+                    "example": "X-GitHub-Event"
                 },
-                "label": {
-                    "type": "string"
+                "signature_header": {
+                    "type": "string",
+                    "example": "X-Hub-Signature-256"
+                },
+                "signature_prefix": {
+                    "type": "string",
+                    "example": "sha256="
+                },
+                "signature_secret": {
+                    "type": "string",
+                    "example": "my-secret"
                 }
             }
         },
-        "MessageResponse": {
+        "TopicIngestionResponse": {
             "type": "object",
             "properties": {
-                "attributes": {
+                "header_attributes": {
                     "type": "object",
                     "additionalProperties": {
                         "type": "string"
                     }
                 },
-                "body": {
-                    "type": "string"
-                },
-                "created_at": {
+                "label_header": {
                     "type": "string",
-                    "example": "2023-08-17T00:00:00Z"
-                },
-                "delivery_attempts": {
-                    "type": "integer",
-                    "example": 1
+                    "example": "X-GitHub-Event"
                 },
-                "id": {
+                "signature_header": {
                     "type": "string",
-                    "example": "7b98fe50-affd-4685-bd7d-3ae5e41493af"
+                    "example": "X-Hub-Signature-256"
                 },
-                "label": {
-                    "type": "string"
+                "signature_prefix": {
+                    "type": "string",
+                    "example": "sha256="
                 },
-                "queue_id": {
+                "signature_secret": {
                     "type": "string",
-                    "example": "my-new-queue"
+                    "example": "********"
                 }
             }
         },
-        "QueueListResponse": {
+        "TopicPublishLineageResponse": {
             "type": "object",
             "properties": {
-                "data": {
+                "id": {
+                    "type": "string",
+                    "example": "01HK651Q52EZMPKBYZGVK0ZX8S"
+                },
+                "messages": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/TopicResponse"
+                        "$ref": "#/definitions/TopicPublishMessageResponse"
                     }
                 },
-                "limit": {
-                    "type": "integer",
-                    "example": 10
-                },
-                "offset": {
-                    "type": "integer",
-                    "example": 0
-                }
-            }
-        },
-        "QueueRequest": {
-            "type": "object",
-            "required": [
-                "ack_deadline_seconds",
-                "delivery_delay_seconds",
-                "id",
-                "message_retention_seconds"
-            ],
-            "properties": {
-                "ack_deadline_seconds": {
-                    "type": "integer",
-                    "example": 30
-                },
-                "delivery_delay_seconds": {
-                    "type": "integer",
-                    "example": 0
-                },
-                "id": {
+                "topic_id": {
                     "type": "string",
-                    "example": "my-new-queue"
-                },
-                "message_retention_seconds": {
-                    "type": "integer",
-                    "example": 604800
+                    "example": "my-new-topic"
                 }
             }
         },
-        "QueueResponse": {
+        "TopicPublishMessageResponse": {
             "type": "object",
             "properties": {
-                "ack_deadline_seconds": {
-                    "type": "integer",
-                    "example": 30
-                },
                 "created_at": {
                     "type": "string",
                     "example": "2023-08-17T00:00:00Z"
                 },
-                "delivery_delay_seconds": {
+                "delivery_attempts": {
                     "type": "integer",
-                    "example": 0
+                    "example": 1
                 },
                 "id": {
+                    "type": "string",
+                    "example": "01HK651Q52EZMPKBYZGVK0ZX8T"
+                },
+                "queue_id": {
                     "type": "string",
                     "example": "my-new-queue"
                 },
-                "message_retention_seconds": {
-                    "type": "integer",
-                    "example": 604800
+                "source_subscription_id": {
+                    "type": "string",
+                    "example": "my-new-subscription"
+                },
+                "source_topic_id": {
+                    "type": "string",
+                    "example": "my-new-topic"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "scheduled",
+                        "available",
+                        "in_flight",
+                        "acked",
+                        "expired"
+                    ],
+                    "example": "in_flight"
                 },
                 "updated_at": {
                     "type": "string",
@@ -1177,34 +3200,44 @@ const docTemplate = `{
                 }
             }
         },
-        "QueueStatsResponse": {
+        "TopicPublishResponse": {
             "type": "object",
             "properties": {
-                "num_undelivered_messages": {
-                    "type": "integer",
-                    "example": 1
+                "id": {
+                    "type": "string",
+                    "example": "01HK651Q52EZMPKBYZGVK0ZX8S"
                 },
-                "oldest_unacked_message_age_seconds": {
+                "message_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "01HK651Q52EZMPKBYZGVK0ZX8T"
+                    ]
+                },
+                "num_matched_subscriptions": {
                     "type": "integer",
                     "example": 1
+                },
+                "topic_id": {
+                    "type": "string",
+                    "example": "my-new-topic"
                 }
             }
         },
-        "QueueUpdateRequest": {
+        "TopicRequest": {
             "type": "object",
             "required": [
-                "ack_deadline_seconds",
-                "delivery_delay_seconds",
-                "message_retention_seconds"
+                "id"
             ],
             "properties": {
-                "ack_deadline_seconds": {
-                    "type": "integer",
-                    "example": 30
+                "id": {
+                    "type": "string",
+                    "example": "my-new-topic"
                 },
-                "delivery_delay_seconds": {
-                    "type": "integer",
-                    "example": 0
+                "ingestion": {
+                    "$ref": "#/definitions/TopicIngestionRequest"
                 },
                 "message_retention_seconds": {
                     "type": "integer",
@@ -1212,108 +3245,76 @@ const docTemplate = `{
                 }
             }
         },
-        "SubscriptionListResponse": {
+        "TopicResponse": {
             "type": "object",
             "properties": {
-                "data": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/TopicResponse"
-                    }
-                },
-                "limit": {
-                    "type": "integer",
-                    "example": 10
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-08-17T00:00:00Z"
                 },
-                "offset": {
-                    "type": "integer",
-                    "example": 0
-                }
-            }
-        },
-        "SubscriptionRequest": {
-            "type": "object",
-            "required": [
-                "id",
-                "queue_id",
-                "topic_id"
-            ],
-            "properties": {
                 "id": {
                     "type": "string",
-                    "example": "my-new-subscription"
-                },
-                "message_filters": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "array",
-                        "items": {
-                            "type": "string"
-                        }
-                    }
+                    "example": "my-new-topic"
                 },
-                "queue_id": {
-                    "type": "string",
-                    "example": "my-new-queue"
+                "ingestion": {
+                    "$ref": "#/definitions/TopicIngestionResponse"
                 },
-                "topic_id": {
-                    "type": "string",
-                    "example": "my-new-topic"
+                "message_retention_seconds": {
+                    "type": "integer",
+                    "example": 604800
                 }
             }
         },
-        "SubscriptionResponse": {
+        "TopicSimulationResponse": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "type": "string",
-                    "example": "2023-08-17T00:00:00Z"
-                },
-                "id": {
-                    "type": "string",
-                    "example": "my-new-subscription"
+                "num_matched_subscriptions": {
+                    "type": "integer",
+                    "example": 0
                 },
-                "message_filters": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "array",
-                        "items": {
-                            "type": "string"
-                        }
+                "subscriptions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/SubscriptionMatchResponse"
                     }
                 },
-                "queue_id": {
-                    "type": "string",
-                    "example": "my-new-queue"
-                },
                 "topic_id": {
                     "type": "string",
                     "example": "my-new-topic"
                 }
             }
         },
-        "TopicRequest": {
+        "TopicStatsResponse": {
             "type": "object",
-            "required": [
-                "id"
-            ],
             "properties": {
-                "id": {
+                "topic_id": {
                     "type": "string",
                     "example": "my-new-topic"
+                },
+                "windows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/TopicStatsWindowResponse"
+                    }
                 }
             }
         },
-        "TopicResponse": {
+        "TopicStatsWindowResponse": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "type": "string",
-                    "example": "2023-08-17T00:00:00Z"
+                "num_published_messages": {
+                    "type": "integer",
+                    "example": 2
                 },
-                "id": {
-                    "type": "string",
-                    "example": "my-new-topic"
+                "subscriptions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/SubscriptionStatsResponse"
+                    }
+                },
+                "window_seconds": {
+                    "type": "integer",
+                    "example": 300
                 }
             }
         }
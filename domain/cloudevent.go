@@ -0,0 +1,252 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jellydator/validation"
+	"golang.org/x/exp/maps"
+)
+
+const (
+	// CloudEventSpecVersion is the supported version of the CloudEvents specification.
+	CloudEventSpecVersion = "1.0"
+	// CloudEventContentType is the content type of a CloudEvent in structured mode.
+	CloudEventContentType = "application/cloudevents+json"
+	// CloudEventDefaultType is the type of a CloudEvent rendered from a message without type and label.
+	CloudEventDefaultType = "psqlqueue.message"
+	// CloudEventMessageIDExtension is the extension that carries the message id of a rendered CloudEvent.
+	CloudEventMessageIDExtension = "messageid"
+	// CloudEventDeliveryAttemptsExtension is the extension that carries the delivery attempts of a rendered CloudEvent.
+	CloudEventDeliveryAttemptsExtension = "deliveryattempts"
+)
+
+// Message attributes used to keep the CloudEvent context attributes.
+const (
+	cloudEventIDAttribute              = "ce_id"
+	cloudEventSourceAttribute          = "ce_source"
+	cloudEventTypeAttribute            = "ce_type"
+	cloudEventSpecVersionAttribute     = "ce_specversion"
+	cloudEventSubjectAttribute         = "ce_subject"
+	cloudEventTimeAttribute            = "ce_time"
+	cloudEventDataContentTypeAttribute = "ce_datacontenttype"
+	cloudEventDataSchemaAttribute      = "ce_dataschema"
+	cloudEventDataBase64Attribute      = "ce_data_base64"
+	cloudEventNoDataAttribute          = "ce_nodata"
+)
+
+// cloudEventNoDataBody is the body of a message mapped from an event without data, the message body cannot be empty.
+const cloudEventNoDataBody = "null"
+
+var (
+	cloudEventExtensionRegex = regexp.MustCompile(`^[a-z0-9]+$`)
+	cloudEventContextFields  = []string{"specversion", "id", "source", "type", "subject", "time", "datacontenttype", "dataschema", "data", "data_base64"}
+)
+
+// CloudEvent entity.
+type CloudEvent struct {
+	SpecVersion     string            `json:"specversion"`
+	ID              string            `json:"id"`
+	Source          string            `json:"source"`
+	Type            string            `json:"type"`
+	Subject         string            `json:"subject,omitempty"`
+	Time            string            `json:"time,omitempty"`
+	DataContentType string            `json:"datacontenttype,omitempty"`
+	DataSchema      string            `json:"dataschema,omitempty"`
+	Data            json.RawMessage   `json:"data,omitempty"`
+	DataBase64      string            `json:"data_base64,omitempty"`
+	Extensions      map[string]string `json:"extensions"`
+}
+
+func (e CloudEvent) Validate() error {
+	return validation.ValidateStruct(&e,
+		validation.Field(&e.SpecVersion, validation.Required, validation.In(CloudEventSpecVersion)),
+		validation.Field(&e.ID, validation.Required),
+		validation.Field(&e.Source, validation.Required),
+		validation.Field(&e.Type, validation.Required),
+		validation.Field(&e.Time, validation.Date(time.RFC3339)),
+		validation.Field(&e.DataBase64, validation.When(len(e.Data) > 0, validation.Empty.Error("cannot be used with data"))),
+		validation.Field(&e.Extensions, validation.By(func(value interface{}) error {
+			for key := range e.Extensions {
+				if !cloudEventExtensionRegex.MatchString(key) {
+					return validation.NewError("validation_invalid_extension", fmt.Sprintf("invalid extension name %q", key))
+				}
+			}
+			return nil
+		})),
+	)
+}
+
+// cloudEventJSON hides the extensions field, the extensions are encoded as top-level attributes.
+type cloudEventJSON struct {
+	cloudEvent
+	Extensions json.RawMessage `json:"extensions,omitempty"`
+}
+
+type cloudEvent CloudEvent
+
+func (e CloudEvent) MarshalJSON() ([]byte, error) {
+	payload, err := json.Marshal(cloudEventJSON{cloudEvent: cloudEvent(e)})
+	if err != nil || len(e.Extensions) == 0 {
+		return payload, err
+	}
+
+	fields := make(map[string]json.RawMessage, len(e.Extensions))
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		return nil, err
+	}
+	for key, value := range e.Extensions {
+		if _, ok := fields[key]; ok {
+			continue
+		}
+		fields[key], _ = json.Marshal(value)
+	}
+
+	return json.Marshal(fields)
+}
+
+func (e *CloudEvent) UnmarshalJSON(data []byte) error {
+	payload := cloudEventJSON{}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return err
+	}
+	event := CloudEvent(payload.cloudEvent)
+
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	for _, key := range cloudEventContextFields {
+		delete(fields, key)
+	}
+	for key, value := range fields {
+		value = bytes.TrimSpace(value)
+		if len(value) == 0 || value[0] == '{' || value[0] == '[' {
+			return fmt.Errorf("cloudevent extension %q must be a scalar value", key)
+		}
+		if event.Extensions == nil {
+			event.Extensions = make(map[string]string, len(fields))
+		}
+		var s string
+		if err := json.Unmarshal(value, &s); err != nil {
+			s = string(value)
+		}
+		event.Extensions[key] = s
+	}
+
+	*e = event
+	return nil
+}
+
+// SetData sets the event data from a payload received in binary mode.
+func (e *CloudEvent) SetData(payload []byte) {
+	if len(payload) == 0 {
+		return
+	}
+	if json.Valid(payload) && isJSONContentType(e.DataContentType) {
+		e.Data = payload
+		return
+	}
+	e.Data, _ = json.Marshal(string(payload))
+}
+
+// ToMessage maps the event to a message keeping the context attributes and the extensions on the message attributes.
+func (e *CloudEvent) ToMessage() *Message {
+	label := e.Type
+	attributes := maps.Clone(e.Extensions)
+	if attributes == nil {
+		attributes = make(map[string]string)
+	}
+	setAttribute := func(key, value string) {
+		if value != "" {
+			attributes[key] = value
+		}
+	}
+	setAttribute(cloudEventIDAttribute, e.ID)
+	setAttribute(cloudEventSourceAttribute, e.Source)
+	setAttribute(cloudEventTypeAttribute, e.Type)
+	setAttribute(cloudEventSpecVersionAttribute, e.SpecVersion)
+	setAttribute(cloudEventSubjectAttribute, e.Subject)
+	setAttribute(cloudEventTimeAttribute, e.Time)
+	setAttribute(cloudEventDataContentTypeAttribute, e.DataContentType)
+	setAttribute(cloudEventDataSchemaAttribute, e.DataSchema)
+
+	message := &Message{Label: &label, Attributes: attributes}
+	switch {
+	case e.DataBase64 != "":
+		message.Body = e.DataBase64
+		attributes[cloudEventDataBase64Attribute] = "true"
+	case len(e.Data) > 0:
+		var s string
+		if err := json.Unmarshal(e.Data, &s); err == nil {
+			message.Body = s
+		} else {
+			message.Body = string(e.Data)
+		}
+	}
+	if message.Body == "" {
+		message.Body = cloudEventNoDataBody
+		attributes[cloudEventNoDataAttribute] = "true"
+	}
+
+	return message
+}
+
+// NewCloudEvent renders a message as a CloudEvent, restoring the context attributes kept by CloudEvent.ToMessage.
+func NewCloudEvent(message *Message) *CloudEvent {
+	attribute := func(key, defaultValue string) string {
+		if value, ok := message.Attributes[key]; ok && value != "" {
+			return value
+		}
+		return defaultValue
+	}
+
+	eventType := CloudEventDefaultType
+	if message.Label != nil && *message.Label != "" {
+		eventType = *message.Label
+	}
+
+	event := &CloudEvent{
+		SpecVersion:     attribute(cloudEventSpecVersionAttribute, CloudEventSpecVersion),
+		ID:              attribute(cloudEventIDAttribute, message.ID),
+		Source:          attribute(cloudEventSourceAttribute, fmt.Sprintf("/queues/%s", message.QueueID)),
+		Type:            attribute(cloudEventTypeAttribute, eventType),
+		Subject:         attribute(cloudEventSubjectAttribute, ""),
+		Time:            attribute(cloudEventTimeAttribute, message.CreatedAt.Format(time.RFC3339Nano)),
+		DataContentType: attribute(cloudEventDataContentTypeAttribute, ""),
+		DataSchema:      attribute(cloudEventDataSchemaAttribute, ""),
+		Extensions:      make(map[string]string, len(message.Attributes)+2),
+	}
+
+	switch {
+	case attribute(cloudEventNoDataAttribute, "") == "true":
+		// the placeholder body of an event without data is not rendered.
+	case attribute(cloudEventDataBase64Attribute, "") == "true":
+		event.DataBase64 = message.Body
+	default:
+		event.SetData([]byte(message.Body))
+	}
+
+	for key, value := range message.Attributes {
+		if cloudEventExtensionRegex.MatchString(key) {
+			event.Extensions[key] = value
+		}
+	}
+	event.Extensions[CloudEventMessageIDExtension] = message.ID
+	event.Extensions[CloudEventDeliveryAttemptsExtension] = fmt.Sprintf("%d", message.DeliveryAttempts)
+
+	return event
+}
+
+func isJSONContentType(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
@@ -0,0 +1,139 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCloudEvent(t *testing.T) {
+	t.Run("Validation fail", func(t *testing.T) {
+		expectedErrorPayload := `{"extensions":"invalid extension name \"Tenant\"","id":"cannot be blank","source":"cannot be blank","specversion":"must be a valid value","time":"must be a valid date","type":"cannot be blank"}`
+		e := CloudEvent{SpecVersion: "0.3", Time: "yesterday", Extensions: map[string]string{"Tenant": "acme"}}
+		err := e.Validate()
+		assert.NotNil(t, err)
+		errorPayload, err := json.Marshal(err)
+		assert.Nil(t, err)
+		assert.Equal(t, expectedErrorPayload, string(errorPayload))
+	})
+
+	t.Run("Validation ok", func(t *testing.T) {
+		e := CloudEvent{SpecVersion: "1.0", ID: "1", Source: "/orders", Type: "order.created", Time: "2024-01-02T19:35:00Z"}
+		err := e.Validate()
+		assert.Nil(t, err)
+	})
+
+	t.Run("UnmarshalJSON", func(t *testing.T) {
+		payload := `{"specversion":"1.0","id":"1","source":"/orders","type":"order.created","data":{"id":1},"tenant":"acme","priority":10}`
+		e := CloudEvent{}
+
+		err := json.Unmarshal([]byte(payload), &e)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "1", e.ID)
+		assert.Equal(t, `{"id":1}`, string(e.Data))
+		assert.Equal(t, map[string]string{"tenant": "acme", "priority": "10"}, e.Extensions)
+	})
+
+	t.Run("UnmarshalJSON with non scalar extension", func(t *testing.T) {
+		payload := `{"specversion":"1.0","id":"1","source":"/orders","type":"order.created","tenant":{"id":1}}`
+		e := CloudEvent{}
+
+		err := json.Unmarshal([]byte(payload), &e)
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("MarshalJSON", func(t *testing.T) {
+		expectedPayload := `{"data":{"id":1},"id":"1","source":"/orders","specversion":"1.0","tenant":"acme","type":"order.created"}`
+		e := CloudEvent{SpecVersion: "1.0", ID: "1", Source: "/orders", Type: "order.created", Data: []byte(`{"id":1}`), Extensions: map[string]string{"tenant": "acme"}}
+
+		payload, err := json.Marshal(&e)
+
+		assert.Nil(t, err)
+		assert.Equal(t, expectedPayload, string(payload))
+	})
+
+	t.Run("SetData", func(t *testing.T) {
+		tests := []struct {
+			dataContentType string
+			payload         string
+			expectedData    string
+		}{
+			{dataContentType: "", payload: `{"id":1}`, expectedData: `{"id":1}`},
+			{dataContentType: "application/json; charset=utf-8", payload: `{"id":1}`, expectedData: `{"id":1}`},
+			{dataContentType: "text/plain", payload: `{"id":1}`, expectedData: `"{\"id\":1}"`},
+			{dataContentType: "", payload: `hello`, expectedData: `"hello"`},
+		}
+
+		for _, tt := range tests {
+			e := CloudEvent{DataContentType: tt.dataContentType}
+			e.SetData([]byte(tt.payload))
+			assert.Equal(t, tt.expectedData, string(e.Data))
+		}
+	})
+
+	t.Run("ToMessage", func(t *testing.T) {
+		e := CloudEvent{SpecVersion: "1.0", ID: "1", Source: "/orders", Type: "order.created", Subject: "123", Data: []byte(`"hello"`), Extensions: map[string]string{"tenant": "acme"}}
+
+		message := e.ToMessage()
+
+		assert.Equal(t, "hello", message.Body)
+		assert.Equal(t, "order.created", *message.Label)
+		assert.Equal(t, map[string]string{
+			"tenant":         "acme",
+			"ce_id":          "1",
+			"ce_source":      "/orders",
+			"ce_type":        "order.created",
+			"ce_specversion": "1.0",
+			"ce_subject":     "123",
+		}, message.Attributes)
+	})
+
+	t.Run("ToMessage without data", func(t *testing.T) {
+		e := CloudEvent{SpecVersion: "1.0", ID: "1", Source: "/orders", Type: "order.deleted"}
+
+		message := e.ToMessage()
+
+		assert.Nil(t, message.Validate())
+		assert.Equal(t, "null", message.Body)
+		assert.Equal(t, "true", message.Attributes["ce_nodata"])
+
+		event := NewCloudEvent(message)
+
+		assert.Empty(t, event.Data)
+		assert.Empty(t, event.DataBase64)
+	})
+
+	t.Run("NewCloudEvent", func(t *testing.T) {
+		e := CloudEvent{SpecVersion: "1.0", ID: "1", Source: "/orders", Type: "order.created", Data: []byte(`{"id":1}`), Extensions: map[string]string{"tenant": "acme"}}
+		message := e.ToMessage()
+		message.ID = "01HK651Q52EZMPKBYZGVK0ZX8T"
+		message.DeliveryAttempts = 1
+
+		event := NewCloudEvent(message)
+
+		assert.Equal(t, "1", event.ID)
+		assert.Equal(t, "/orders", event.Source)
+		assert.Equal(t, "order.created", event.Type)
+		assert.Equal(t, "1.0", event.SpecVersion)
+		assert.Equal(t, `{"id":1}`, string(event.Data))
+		assert.Equal(t, map[string]string{"tenant": "acme", "messageid": message.ID, "deliveryattempts": "1"}, event.Extensions)
+	})
+
+	t.Run("NewCloudEvent with plain message", func(t *testing.T) {
+		now := time.Date(2024, 1, 2, 19, 35, 0, 0, time.UTC)
+		message := Message{ID: "01HK651Q52EZMPKBYZGVK0ZX8T", QueueID: "my-queue", Body: "hello", Attributes: map[string]string{"tenant": "acme", "order_id": "1"}, CreatedAt: now}
+
+		event := NewCloudEvent(&message)
+
+		assert.Equal(t, message.ID, event.ID)
+		assert.Equal(t, "/queues/my-queue", event.Source)
+		assert.Equal(t, CloudEventDefaultType, event.Type)
+		assert.Equal(t, "2024-01-02T19:35:00Z", event.Time)
+		assert.Equal(t, `"hello"`, string(event.Data))
+		assert.Equal(t, map[string]string{"tenant": "acme", "messageid": message.ID, "deliveryattempts": "0"}, event.Extensions)
+	})
+}
@@ -46,18 +46,21 @@ func loadDotEnv() bool {
 
 // Config holds all application configuration data.
 type Config struct {
-	Testing                        bool
-	LogLevel                       string
-	ServerHost                     string
-	ServerPort                     uint
-	ServerReadHeaderTimeoutSeconds uint
-	MetricsHost                    string
-	MetricsPort                    uint
-	DatabaseURL                    string
-	TestDatabaseURL                string
-	DatabaseMinConns               uint
-	DatabaseMaxConns               uint
-	QueueMaxNumberOfMessages       uint
+	Testing                         bool
+	LogLevel                        string
+	ServerHost                      string
+	ServerPort                      uint
+	ServerReadHeaderTimeoutSeconds  uint
+	MetricsHost                     string
+	MetricsPort                     uint
+	DatabaseURL                     string
+	TestDatabaseURL                 string
+	DatabaseMinConns                uint
+	DatabaseMaxConns                uint
+	QueueMaxNumberOfMessages        uint
+	MessageMaxRequestTimeoutSeconds uint
+	MessageMaxConcurrentRequests    uint
+	TopicMaxHops                    uint
 }
 
 // NewConfig returns a Config with values loaded from environment variables.
@@ -65,17 +68,20 @@ func NewConfig() *Config {
 	loadDotEnv()
 
 	return &Config{
-		Testing:                        env.GetBool("PSQLQUEUE_TESTING", false),
-		LogLevel:                       env.GetString("PSQLQUEUE_LOG_LEVEL", "info"),
-		ServerHost:                     env.GetString("PSQLQUEUE_SERVER_HOST", "0.0.0.0"),
-		ServerPort:                     env.GetUint("PSQLQUEUE_SERVER_PORT", 8000),
-		ServerReadHeaderTimeoutSeconds: env.GetUint("PSQLQUEUE_SERVER_READ_HEADER_TIMEOUT_SECONDS", 60),
-		MetricsHost:                    env.GetString("PSQLQUEUE_METRICS_HOST", "0.0.0.0"),
-		MetricsPort:                    env.GetUint("PSQLQUEUE_METRICS_PORT", 9090),
-		DatabaseURL:                    env.GetString("PSQLQUEUE_DATABASE_URL", ""),
-		TestDatabaseURL:                env.GetString("PSQLQUEUE_TEST_DATABASE_URL", ""),
-		DatabaseMinConns:               env.GetUint("PSQLQUEUE_DATABASE_MIN_CONNS", 0),
-		DatabaseMaxConns:               env.GetUint("PSQLQUEUE_DATABASE_MAX_CONNS", 2),
-		QueueMaxNumberOfMessages:       env.GetUint("PSQLQUEUE_QUEUE_MAX_NUMBER_OF_MESSAGES", 10),
+		Testing:                         env.GetBool("PSQLQUEUE_TESTING", false),
+		LogLevel:                        env.GetString("PSQLQUEUE_LOG_LEVEL", "info"),
+		ServerHost:                      env.GetString("PSQLQUEUE_SERVER_HOST", "0.0.0.0"),
+		ServerPort:                      env.GetUint("PSQLQUEUE_SERVER_PORT", 8000),
+		ServerReadHeaderTimeoutSeconds:  env.GetUint("PSQLQUEUE_SERVER_READ_HEADER_TIMEOUT_SECONDS", 60),
+		MetricsHost:                     env.GetString("PSQLQUEUE_METRICS_HOST", "0.0.0.0"),
+		MetricsPort:                     env.GetUint("PSQLQUEUE_METRICS_PORT", 9090),
+		DatabaseURL:                     env.GetString("PSQLQUEUE_DATABASE_URL", ""),
+		TestDatabaseURL:                 env.GetString("PSQLQUEUE_TEST_DATABASE_URL", ""),
+		DatabaseMinConns:                env.GetUint("PSQLQUEUE_DATABASE_MIN_CONNS", 0),
+		DatabaseMaxConns:                env.GetUint("PSQLQUEUE_DATABASE_MAX_CONNS", 2),
+		QueueMaxNumberOfMessages:        env.GetUint("PSQLQUEUE_QUEUE_MAX_NUMBER_OF_MESSAGES", 10),
+		MessageMaxRequestTimeoutSeconds: env.GetUint("PSQLQUEUE_MESSAGE_MAX_REQUEST_TIMEOUT_SECONDS", 30),
+		MessageMaxConcurrentRequests:    env.GetUint("PSQLQUEUE_MESSAGE_MAX_CONCURRENT_REQUESTS", 100),
+		TopicMaxHops:                    env.GetUint("PSQLQUEUE_TOPIC_MAX_HOPS", 5),
 	}
 }
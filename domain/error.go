@@ -11,12 +11,36 @@ var (
 	ErrMessageAlreadyExists = errors.New("message already exists")
 	// ErrMessageNotFound is returned when the message is not found.
 	ErrMessageNotFound = errors.New("message not found")
+	// ErrMessageDuplicated is returned when a message is published with the unique key of a pending message.
+	ErrMessageDuplicated = errors.New("message with the same unique key is pending")
+	// ErrMessageNotInFlight is returned when the progress or the nack of a message that is not in flight is stored.
+	ErrMessageNotInFlight = errors.New("message is not in flight")
+	// ErrMessageReplyTimeout is returned when the reply of a request is not received before the timeout.
+	ErrMessageReplyTimeout = errors.New("message reply timeout")
+	// ErrMessageTooManyRequests is returned when the number of requests waiting for a reply reaches the limit.
+	ErrMessageTooManyRequests = errors.New("too many requests waiting for a reply")
 	// ErrTopicAlreadyExists is returned when the topic already exists.
 	ErrTopicAlreadyExists = errors.New("topic already exists")
 	// ErrTopicNotFound is returned when the topic is not found.
 	ErrTopicNotFound = errors.New("topic not found")
+	// ErrTopicNoMatchingSubscription is returned when a message published with require match does not match any subscription.
+	ErrTopicNoMatchingSubscription = errors.New("no subscription matched the message")
+	// ErrTopicPublishNotFound is returned when no message of the topic publish is found.
+	ErrTopicPublishNotFound = errors.New("topic publish not found")
+	// ErrTopicMaxHopsExceeded is returned when the fan-out of a message goes through more topics than allowed.
+	ErrTopicMaxHopsExceeded = errors.New("topic max hops exceeded")
+	// ErrTopicIngestionInvalidSignature is returned when the signature of an ingested payload is missing or does not match.
+	ErrTopicIngestionInvalidSignature = errors.New("invalid ingestion signature")
+	// ErrTopicMessageAlreadyExists is returned when the topic message already exists.
+	ErrTopicMessageAlreadyExists = errors.New("topic message already exists")
+	// ErrTopicMessageNotFound is returned when the topic message is not found.
+	ErrTopicMessageNotFound = errors.New("topic message not found")
 	// ErrSubscriptionAlreadyExists is returned when the subscription already exists.
 	ErrSubscriptionAlreadyExists = errors.New("subscription already exists")
 	// ErrSubscriptionNotFound is returned when the subscription is not found.
 	ErrSubscriptionNotFound = errors.New("subscription not found")
+	// ErrSubscriptionCycle is returned when the subscription target topic routes messages back to the subscription topic.
+	ErrSubscriptionCycle = errors.New("subscription creates a topic cycle")
+	// ErrSubscriptionReplayTooLarge is returned when the replay of a subscription enqueues more messages than allowed.
+	ErrSubscriptionReplayTooLarge = errors.New("subscription replay too large")
 )
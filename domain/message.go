@@ -2,32 +2,110 @@ package domain
 
 import (
 	"context"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/jellydator/validation"
 	"github.com/oklog/ulid/v2"
 )
 
+const (
+	// MessageStatusScheduled is the status of a message waiting for the delivery delay.
+	MessageStatusScheduled = "scheduled"
+	// MessageStatusAvailable is the status of a message ready to be delivered.
+	MessageStatusAvailable = "available"
+	// MessageStatusInFlight is the status of a delivered message waiting for the ack or the visibility timeout.
+	MessageStatusInFlight = "in_flight"
+	// MessageStatusAcked is the status of an acked message.
+	MessageStatusAcked = "acked"
+	// MessageStatusExpired is the status of a message that reached the retention period.
+	MessageStatusExpired = "expired"
+	// MessageStatusDeadLettered is the status of a message moved out of the delivery.
+	MessageStatusDeadLettered = "dead_lettered"
+)
+
+const (
+	// MessageStateReady is the state of a message waiting for the delivery.
+	MessageStateReady = "ready"
+	// MessageStateInFlight is the state of a delivered message, it's delivered again after the visibility timeout.
+	MessageStateInFlight = "in_flight"
+	// MessageStateAcked is the state of a message processed by the consumer.
+	MessageStateAcked = "acked"
+	// MessageStateExpired is the state of a message that reached the retention period without being acked.
+	MessageStateExpired = "expired"
+	// MessageStateDeadLettered is the state of a message moved out of the delivery.
+	MessageStateDeadLettered = "dead_lettered"
+)
+
+const (
+	// MessageResultStatusSucceeded is the result status of a job that succeeded.
+	MessageResultStatusSucceeded = "succeeded"
+	// MessageResultStatusFailed is the result status of a job that failed.
+	MessageResultStatusFailed = "failed"
+)
+
+const (
+	// MessageAckModeManual keeps the delivered messages in flight until the ack, the nack or the visibility timeout.
+	MessageAckModeManual = "manual"
+	// MessageAckModeAuto acks the messages on the delivery, the messages are delivered at most once.
+	MessageAckModeAuto = "auto"
+)
+
+// MessageMaxErrors is the number of nack errors kept on a message, the oldest errors are discarded.
+const MessageMaxErrors = 10
+
 // Message entity.
 type Message struct {
-	ID               string            `json:"id" db:"id"`
-	QueueID          string            `json:"queue_id" db:"queue_id"`
-	Label            *string           `json:"label" db:"label" form:"label"`
-	Body             string            `json:"body" db:"body" form:"body"`
-	Attributes       map[string]string `json:"attributes" db:"attributes" form:"attributes"`
-	DeliveryAttempts uint              `json:"delivery_attempts" db:"delivery_attempts"`
-	ExpiredAt        time.Time         `json:"-" db:"expired_at"`
-	ScheduledAt      time.Time         `json:"-" db:"scheduled_at"`
-	CreatedAt        time.Time         `json:"created_at" db:"created_at"`
-	UpdatedAt        time.Time         `json:"-" db:"updated_at"`
+	ID                   string            `json:"id" db:"id"`
+	QueueID              string            `json:"queue_id" db:"queue_id"`
+	Label                *string           `json:"label" db:"label" form:"label"`
+	Body                 string            `json:"body" db:"body" form:"body"`
+	Attributes           map[string]string `json:"attributes" db:"attributes" form:"attributes"`
+	ReplyTo              *string           `json:"reply_to" db:"reply_to" form:"reply_to"`
+	CorrelationID        *string           `json:"correlation_id" db:"correlation_id" form:"correlation_id"`
+	UniqueKey            *string           `json:"unique_key" db:"unique_key" form:"unique_key"`
+	CoalesceKey          *string           `json:"coalesce_key" db:"coalesce_key" form:"coalesce_key"`
+	NumCoalesced         uint              `json:"-" db:"num_coalesced"`
+	State                string            `json:"-" db:"state"`
+	ConsumerID           *string           `json:"-" db:"consumer_id"`
+	LeasedAt             *time.Time        `json:"-" db:"leased_at"`
+	DeliveryAttempts     uint              `json:"delivery_attempts" db:"delivery_attempts"`
+	Errors               []MessageError    `json:"errors" db:"errors"`
+	PublishID            *string           `json:"publish_id" db:"publish_id"`
+	SourceTopicID        *string           `json:"source_topic_id" db:"source_topic_id"`
+	SourceSubscriptionID *string           `json:"source_subscription_id" db:"source_subscription_id"`
+	ProgressPercent      *uint             `json:"-" db:"progress_percent"`
+	ProgressNote         *string           `json:"-" db:"progress_note"`
+	ResultStatus         *string           `json:"-" db:"result_status"`
+	Result               *string           `json:"-" db:"result"`
+	ResultExpiredAt      *time.Time        `json:"-" db:"result_expired_at"`
+	AckedAt              *time.Time        `json:"-" db:"acked_at"`
+	ExpiredAt            time.Time         `json:"-" db:"expired_at"`
+	ScheduledAt          time.Time         `json:"-" db:"scheduled_at"`
+	CreatedAt            time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt            time.Time         `json:"-" db:"updated_at"`
 }
 
 func (m Message) Validate() error {
 	return validation.ValidateStruct(&m,
-		validation.Field(&m.Body, validation.Required),
+		validation.Field(&m.Body, validation.Required, validation.By(validateText)),
+		validation.Field(&m.ReplyTo, validation.NilOrNotEmpty, validation.Match(idRegex)),
+		validation.Field(&m.CorrelationID, validation.NilOrNotEmpty),
+		validation.Field(&m.UniqueKey, validation.NilOrNotEmpty, validation.Length(1, 255)),
+		validation.Field(&m.CoalesceKey, validation.NilOrNotEmpty, validation.Length(1, 255), validation.When(m.UniqueKey != nil, validation.Nil.Error("cannot be used with unique_key"))),
 	)
 }
 
+// validateText rejects the strings that cannot be stored in a text column.
+func validateText(value interface{}) error {
+	s, _ := value.(string)
+	if !utf8.ValidString(s) || strings.ContainsRune(s, 0) {
+		return validation.NewError("validation_invalid_text", "must be valid UTF-8 without null characters")
+	}
+	return nil
+}
+
 func (m *Message) Enqueue(queue *Queue, now time.Time) {
 	scheduledAt := now
 	if queue.DeliveryDelaySeconds > 0 {
@@ -36,6 +114,7 @@ func (m *Message) Enqueue(queue *Queue, now time.Time) {
 
 	m.ID = ulid.Make().String()
 	m.QueueID = queue.ID
+	m.State = MessageStateReady
 	m.DeliveryAttempts = 0
 	m.ExpiredAt = now.Add(time.Duration(queue.MessageRetentionSeconds) * time.Second)
 	m.ScheduledAt = scheduledAt
@@ -43,36 +122,235 @@ func (m *Message) Enqueue(queue *Queue, now time.Time) {
 	m.UpdatedAt = now
 }
 
-func (m *Message) DeliverySetup(queue *Queue, now time.Time) {
+// SetReply sets the reply queue and the correlation id of a request, generating the correlation id when it's not set.
+func (m *Message) SetReply(replyTo string) {
+	m.ReplyTo = &replyTo
+	if m.CorrelationID == nil {
+		correlationID := ulid.Make().String()
+		m.CorrelationID = &correlationID
+	}
+}
+
+func (m *Message) SetSource(publishID, topicID string, subscription *Subscription) {
+	subscriptionID := subscription.ID
+
+	m.PublishID = &publishID
+	m.SourceTopicID = &topicID
+	m.SourceSubscriptionID = &subscriptionID
+}
+
+// Status returns the delivery state of the message.
+func (m *Message) Status(now time.Time) string {
+	switch {
+	case m.State == MessageStateAcked:
+		return MessageStatusAcked
+	case m.State == MessageStateDeadLettered:
+		return MessageStatusDeadLettered
+	case m.State == MessageStateExpired || !m.ExpiredAt.After(now):
+		return MessageStatusExpired
+	case m.State == MessageStateInFlight && m.ScheduledAt.After(now):
+		return MessageStatusInFlight
+	case m.ScheduledAt.After(now):
+		return MessageStatusScheduled
+	default:
+		return MessageStatusAvailable
+	}
+}
+
+func (m *Message) DeliverySetup(queue *Queue, visibilityTimeoutSeconds uint, now time.Time) {
+	m.State = MessageStateInFlight
 	m.DeliveryAttempts = m.DeliveryAttempts + 1
-	m.ScheduledAt = now.Add(time.Duration(queue.AckDeadlineSeconds) * time.Second)
+	m.LeasedAt = &now
+	m.ScheduledAt = now.Add(time.Duration(queue.VisibilityTimeoutSeconds(visibilityTimeoutSeconds)) * time.Second)
 	m.UpdatedAt = now
 }
 
+// SetConsumer keeps the consumer that holds the lease of the message, nil for an anonymous consumer.
+func (m *Message) SetConsumer(consumerID *string) {
+	m.ConsumerID = consumerID
+}
+
+// Ack marks the message as processed, the message is kept for the queue acked retention.
 func (m *Message) Ack(now time.Time) {
-	m.ExpiredAt = now
+	m.State = MessageStateAcked
+	m.AckedAt = &now
 	m.UpdatedAt = now
 }
 
 func (m *Message) Nack(now time.Time, visibilityTimeoutSeconds uint) {
+	m.State = MessageStateReady
 	m.ScheduledAt = now.Add(time.Duration(visibilityTimeoutSeconds) * time.Second)
+	m.LeasedAt = nil
+	m.UpdatedAt = now
+}
+
+// NackWithError nacks the message keeping the error reported by the consumer on the history of errors.
+func (m *Message) NackWithError(messageError *MessageError, visibilityTimeoutSeconds uint, now time.Time) {
+	messageError.DeliveryAttempt = m.DeliveryAttempts
+	messageError.CreatedAt = now
+	m.Errors = append(m.Errors, *messageError)
+	if len(m.Errors) > MessageMaxErrors {
+		m.Errors = m.Errors[len(m.Errors)-MessageMaxErrors:]
+	}
+	m.Nack(now, visibilityTimeoutSeconds)
+}
+
+// SetProgress stores the progress reported by the consumer of an in flight message.
+func (m *Message) SetProgress(progress *MessageProgress, now time.Time) error {
+	if m.Status(now) != MessageStatusInFlight {
+		return ErrMessageNotInFlight
+	}
+
+	percent := progress.Percent
+	m.ProgressPercent = &percent
+	m.ProgressNote = progress.Note
 	m.UpdatedAt = now
+
+	return nil
+}
+
+// AckWithResult acks the message storing the job result, the result is kept for the queue result retention.
+func (m *Message) AckWithResult(result *MessageResult, queue *Queue, now time.Time) {
+	m.Ack(now)
+
+	status := result.Status
+	m.ResultStatus = &status
+	m.Result = result.Body
+	m.ResultExpiredAt = nil
+	if queue.ResultRetentionSeconds > 0 {
+		resultExpiredAt := now.Add(time.Duration(queue.ResultRetentionSeconds) * time.Second)
+		m.ResultExpiredAt = &resultExpiredAt
+	}
+}
+
+// Job returns the job view of the message.
+func (m *Message) Job(now time.Time) *MessageJob {
+	return &MessageJob{
+		ID:               m.ID,
+		QueueID:          m.QueueID,
+		Status:           m.Status(now),
+		DeliveryAttempts: m.DeliveryAttempts,
+		Errors:           m.Errors,
+		ProgressPercent:  m.ProgressPercent,
+		ProgressNote:     m.ProgressNote,
+		ResultStatus:     m.ResultStatus,
+		Result:           m.Result,
+		CreatedAt:        m.CreatedAt,
+		UpdatedAt:        m.UpdatedAt,
+	}
+}
+
+// Lease returns the lease view of an in flight message.
+func (m *Message) Lease() *MessageLease {
+	return &MessageLease{
+		ID:               m.ID,
+		QueueID:          m.QueueID,
+		Label:            m.Label,
+		ConsumerID:       m.ConsumerID,
+		DeliveryAttempts: m.DeliveryAttempts,
+		LeasedAt:         m.LeasedAt,
+		Deadline:         m.ScheduledAt,
+	}
+}
+
+// MessageProgress entity.
+type MessageProgress struct {
+	Percent uint    `json:"percent" form:"percent"`
+	Note    *string `json:"note" form:"note"`
+}
+
+func (m MessageProgress) Validate() error {
+	return validation.ValidateStruct(&m,
+		validation.Field(&m.Percent, validation.Max(uint(100))),
+		validation.Field(&m.Note, validation.NilOrNotEmpty),
+	)
+}
+
+// MessageResult entity.
+type MessageResult struct {
+	Status string  `json:"status" form:"status"`
+	Body   *string `json:"body" form:"body"`
+}
+
+func (m MessageResult) Validate() error {
+	return validation.ValidateStruct(&m,
+		validation.Field(&m.Status, validation.Required, validation.In(MessageResultStatusSucceeded, MessageResultStatusFailed)),
+	)
+}
+
+// MessageError entity, the failure reported by the consumer on the nack.
+type MessageError struct {
+	Message         string    `json:"error" form:"error"`
+	Code            *string   `json:"error_code" form:"error_code"`
+	DeliveryAttempt uint      `json:"delivery_attempt"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+func (m MessageError) Validate() error {
+	return validation.ValidateStruct(&m,
+		validation.Field(&m.Message, validation.Required, validation.Length(1, 4096)),
+		validation.Field(&m.Code, validation.NilOrNotEmpty, validation.Length(1, 255)),
+	)
+}
+
+// MessageJob entity.
+type MessageJob struct {
+	ID               string         `json:"id"`
+	QueueID          string         `json:"queue_id"`
+	Status           string         `json:"status"`
+	DeliveryAttempts uint           `json:"delivery_attempts"`
+	Errors           []MessageError `json:"errors"`
+	ProgressPercent  *uint          `json:"progress_percent"`
+	ProgressNote     *string        `json:"progress_note"`
+	ResultStatus     *string        `json:"result_status"`
+	Result           *string        `json:"result"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+}
+
+// MessageLease entity, the lease of an in flight message.
+type MessageLease struct {
+	ID               string     `json:"id"`
+	QueueID          string     `json:"queue_id"`
+	Label            *string    `json:"label"`
+	ConsumerID       *string    `json:"consumer_id"`
+	DeliveryAttempts uint       `json:"delivery_attempts"`
+	LeasedAt         *time.Time `json:"leased_at"`
+	Deadline         time.Time  `json:"deadline"`
 }
 
 // MessageRepository is the repository interface for the Message entity.
 type MessageRepository interface {
 	CreateMany(ctx context.Context, messages []*Message) error
 	Create(ctx context.Context, message *Message) error
+	CreateOrCoalesce(ctx context.Context, message *Message) error
 	Get(ctx context.Context, id string) (*Message, error)
-	List(ctx context.Context, queue *Queue, label *string, limit uint) ([]*Message, error)
+	List(ctx context.Context, queue *Queue, label *string, limit, visibilityTimeoutSeconds uint, ackMode string, consumerID *string) ([]*Message, error)
+	ListInFlight(ctx context.Context, queueID string, offset, limit uint) ([]*Message, error)
+	ListByPublish(ctx context.Context, topicID, publishID string, offset, limit uint) ([]*Message, error)
+	ReceiveReply(ctx context.Context, queueID, correlationID string) (*Message, error)
+	// UpdateProgress stores the progress of the message while it's in flight, ErrMessageNotInFlight is returned otherwise.
+	UpdateProgress(ctx context.Context, message *Message) error
+	// UpdateWithEvent stores the changes of the message with its delivery log event when the queue has the delivery log enabled.
+	UpdateWithEvent(ctx context.Context, message *Message, eventType string) error
 	Ack(ctx context.Context, id string) error
-	Nack(ctx context.Context, id string, visibilityTimeoutSeconds uint) error
+	// Nack makes an in flight message ready again keeping the error reported by the consumer, when it's not nil.
+	// ErrMessageNotInFlight is returned when the message is not in flight.
+	Nack(ctx context.Context, id string, visibilityTimeoutSeconds uint, messageError *MessageError) error
+	ReleaseConsumer(ctx context.Context, queueID, consumerID string) ([]*Message, error)
 }
 
 // MessageService is the service interface for the Message entity.
 type MessageService interface {
 	Create(ctx context.Context, message *Message) error
-	List(ctx context.Context, queueID string, label *string, limit uint) ([]*Message, error)
-	Ack(ctx context.Context, id string) error
-	Nack(ctx context.Context, id string, visibilityTimeoutSeconds uint) error
+	List(ctx context.Context, queueID string, label *string, limit, visibilityTimeoutSeconds uint, ackMode string, consumerID *string) ([]*Message, time.Duration, error)
+	ListFromQueues(ctx context.Context, queueIDs []string, label *string, limit, visibilityTimeoutSeconds uint, ackMode string, consumerID *string) ([]*Message, time.Duration, error)
+	Request(ctx context.Context, message *Message, timeout time.Duration) (*Message, error)
+	GetJob(ctx context.Context, queueID, id string) (*MessageJob, error)
+	Progress(ctx context.Context, queueID, id string, progress *MessageProgress) error
+	Ack(ctx context.Context, id string, result *MessageResult) error
+	Nack(ctx context.Context, id string, visibilityTimeoutSeconds uint, messageError *MessageError) error
+	ListEvents(ctx context.Context, queueID, id string, offset, limit uint) ([]*MessageEvent, error)
+	ListInFlight(ctx context.Context, queueID string, offset, limit uint) ([]*MessageLease, error)
+	ReleaseConsumer(ctx context.Context, queueID, consumerID string) error
 }
@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// MessageEventTypeEnqueue is the event of a message added to the queue.
+	MessageEventTypeEnqueue = "enqueue"
+	// MessageEventTypeLease is the event of a message delivered to a consumer.
+	MessageEventTypeLease = "lease"
+	// MessageEventTypeAck is the event of a message acked by the consumer.
+	MessageEventTypeAck = "ack"
+	// MessageEventTypeNack is the event of a message nacked by the consumer.
+	MessageEventTypeNack = "nack"
+	// MessageEventTypeRelease is the event of a lease released by an admin, the message is delivered again.
+	MessageEventTypeRelease = "release"
+	// MessageEventTypeSeek is the event of an acked message delivered again by the seek of the queue.
+	MessageEventTypeSeek = "seek"
+	// MessageEventTypeExpire is the event of a message removed by the cleanup after the retention period without being acked.
+	MessageEventTypeExpire = "expire"
+)
+
+// MessageEvent entity, an entry of the delivery log of a queue.
+type MessageEvent struct {
+	ID         uint64    `json:"id" db:"id"`
+	MessageID  string    `json:"message_id" db:"message_id"`
+	QueueID    string    `json:"queue_id" db:"queue_id"`
+	Type       string    `json:"type" db:"type"`
+	ConsumerID *string   `json:"consumer_id" db:"consumer_id"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	ExpiredAt  time.Time `json:"-" db:"expired_at"`
+}
+
+// MessageEventRepository is the repository interface for the MessageEvent entity, the events are stored by the
+// MessageRepository in the same statement as the state change of the message.
+type MessageEventRepository interface {
+	ListByMessage(ctx context.Context, queueID, messageID string, offset, limit uint) ([]*MessageEvent, error)
+}
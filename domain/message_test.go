@@ -2,6 +2,7 @@ package domain
 
 import (
 	"encoding/json"
+	"fmt"
 	"testing"
 	"time"
 
@@ -19,6 +20,18 @@ func TestMessage(t *testing.T) {
 		assert.Equal(t, expectedErrorPayload, string(errorPayload))
 	})
 
+	t.Run("Validation fail with invalid text", func(t *testing.T) {
+		expectedErrorPayload := `{"body":"must be valid UTF-8 without null characters"}`
+		for _, body := range []string{"\xff\xfe", "my\x00body"} {
+			m := Message{Body: body}
+			err := m.Validate()
+			assert.NotNil(t, err)
+			errorPayload, err := json.Marshal(err)
+			assert.Nil(t, err)
+			assert.Equal(t, expectedErrorPayload, string(errorPayload))
+		}
+	})
+
 	t.Run("Validation ok", func(t *testing.T) {
 		m := Message{Body: `{"type": "message"}`}
 		err := m.Validate()
@@ -39,6 +52,7 @@ func TestMessage(t *testing.T) {
 
 		assert.NotEmpty(t, m.ID)
 		assert.Equal(t, queue.ID, m.QueueID)
+		assert.Equal(t, MessageStateReady, m.State)
 		assert.Equal(t, uint(0), m.DeliveryAttempts)
 		assert.Equal(t, now.Add(time.Duration(queue.MessageRetentionSeconds)*time.Second), m.ExpiredAt)
 		assert.Equal(t, now.Add(time.Duration(queue.DeliveryDelaySeconds)*time.Second), m.ScheduledAt)
@@ -57,13 +71,32 @@ func TestMessage(t *testing.T) {
 
 		m.Enqueue(&queue, time.Now().UTC())
 		now := time.Now().UTC()
-		m.DeliverySetup(&queue, now)
+		m.DeliverySetup(&queue, 0, now)
 
+		assert.Equal(t, MessageStateInFlight, m.State)
 		assert.Equal(t, uint(1), m.DeliveryAttempts)
 		assert.Equal(t, now.Add(time.Duration(queue.AckDeadlineSeconds)*time.Second), m.ScheduledAt)
+		assert.Equal(t, now, *m.LeasedAt)
 		assert.Equal(t, now, m.UpdatedAt)
 	})
 
+	t.Run("DeliverySetup with visibility timeout", func(t *testing.T) {
+		queue := Queue{
+			ID:                          "my-queue",
+			AckDeadlineSeconds:          60,
+			MessageRetentionSeconds:     3600,
+			MaxVisibilityTimeoutSeconds: 600,
+		}
+		m := Message{Body: `{"type": "message"}`}
+
+		m.Enqueue(&queue, time.Now().UTC())
+		now := time.Now().UTC()
+		m.DeliverySetup(&queue, 300, now)
+
+		assert.Equal(t, uint(1), m.DeliveryAttempts)
+		assert.Equal(t, now.Add(300*time.Second), m.ScheduledAt)
+	})
+
 	t.Run("Ack", func(t *testing.T) {
 		queue := Queue{
 			ID:                      "my-queue",
@@ -74,11 +107,12 @@ func TestMessage(t *testing.T) {
 		m := Message{Body: `{"type": "message"}`}
 
 		m.Enqueue(&queue, time.Now().UTC())
-		m.DeliverySetup(&queue, time.Now().UTC())
+		m.DeliverySetup(&queue, 0, time.Now().UTC())
 		now := time.Now().UTC()
 		m.Ack(now)
 
-		assert.Equal(t, now, m.ExpiredAt)
+		assert.Equal(t, MessageStateAcked, m.State)
+		assert.Equal(t, now, *m.AckedAt)
 		assert.Equal(t, now, m.UpdatedAt)
 	})
 
@@ -92,11 +126,232 @@ func TestMessage(t *testing.T) {
 		m := Message{Body: `{"type": "message"}`}
 
 		m.Enqueue(&queue, time.Now().UTC())
-		m.DeliverySetup(&queue, time.Now().UTC())
+		m.DeliverySetup(&queue, 0, time.Now().UTC())
 		now := time.Now().UTC()
 		m.Nack(now, 100)
 
+		assert.Equal(t, MessageStateReady, m.State)
 		assert.Equal(t, now.Add(time.Duration(100)*time.Second), m.ScheduledAt)
+		assert.Nil(t, m.LeasedAt)
+		assert.Equal(t, now, m.UpdatedAt)
+	})
+
+	t.Run("NackWithError", func(t *testing.T) {
+		queue := Queue{ID: "my-queue", AckDeadlineSeconds: 60, MessageRetentionSeconds: 3600}
+		m := Message{Body: `{"type": "message"}`}
+
+		m.Enqueue(&queue, time.Now().UTC())
+		for i := 0; i < MessageMaxErrors+2; i++ {
+			m.DeliverySetup(&queue, 0, time.Now().UTC())
+			m.NackWithError(&MessageError{Message: fmt.Sprintf("error %d", i+1)}, 100, time.Now().UTC())
+		}
+		now := time.Now().UTC()
+		m.NackWithError(&MessageError{Message: "timeout", Code: &queue.ID}, 100, now)
+
+		assert.Len(t, m.Errors, MessageMaxErrors)
+		assert.Equal(t, "error 4", m.Errors[0].Message)
+		assert.Equal(t, "timeout", m.Errors[MessageMaxErrors-1].Message)
+		assert.Equal(t, uint(MessageMaxErrors+2), m.Errors[MessageMaxErrors-1].DeliveryAttempt)
+		assert.Equal(t, now, m.Errors[MessageMaxErrors-1].CreatedAt)
+		assert.Equal(t, now.Add(time.Duration(100)*time.Second), m.ScheduledAt)
+	})
+
+	t.Run("SetProgress", func(t *testing.T) {
+		queue := Queue{ID: "my-queue", AckDeadlineSeconds: 60, MessageRetentionSeconds: 3600}
+		m := Message{Body: `{"type": "message"}`}
+
+		m.Enqueue(&queue, time.Now().UTC())
+		err := m.SetProgress(&MessageProgress{Percent: 50}, time.Now().UTC())
+		assert.ErrorIs(t, err, ErrMessageNotInFlight)
+
+		m.DeliverySetup(&queue, 0, time.Now().UTC())
+		now := time.Now().UTC()
+		err = m.SetProgress(&MessageProgress{Percent: 50, Note: pointString("half way")}, now)
+		assert.Nil(t, err)
+		assert.Equal(t, uint(50), *m.ProgressPercent)
+		assert.Equal(t, "half way", *m.ProgressNote)
 		assert.Equal(t, now, m.UpdatedAt)
 	})
+
+	t.Run("AckWithResult", func(t *testing.T) {
+		queue := Queue{ID: "my-queue", AckDeadlineSeconds: 60, MessageRetentionSeconds: 3600, ResultRetentionSeconds: 600}
+		m := Message{Body: `{"type": "message"}`}
+
+		m.Enqueue(&queue, time.Now().UTC())
+		m.DeliverySetup(&queue, 0, time.Now().UTC())
+		now := time.Now().UTC()
+		m.AckWithResult(&MessageResult{Status: MessageResultStatusFailed, Body: pointString("boom")}, &queue, now)
+
+		assert.Equal(t, now, *m.AckedAt)
+		assert.Equal(t, MessageResultStatusFailed, *m.ResultStatus)
+		assert.Equal(t, "boom", *m.Result)
+		assert.Equal(t, now.Add(600*time.Second), *m.ResultExpiredAt)
+		assert.Equal(t, MessageStatusAcked, m.Job(now).Status)
+
+		queue.ResultRetentionSeconds = 0
+		m.AckWithResult(&MessageResult{Status: MessageResultStatusSucceeded}, &queue, now)
+		assert.Nil(t, m.ResultExpiredAt)
+	})
+
+	t.Run("MessageProgress validation fail", func(t *testing.T) {
+		expectedErrorPayload := `{"note":"cannot be blank","percent":"must be no greater than 100"}`
+		progress := MessageProgress{Percent: 101, Note: pointString("")}
+		err := progress.Validate()
+		assert.NotNil(t, err)
+		errorPayload, err := json.Marshal(err)
+		assert.Nil(t, err)
+		assert.Equal(t, expectedErrorPayload, string(errorPayload))
+	})
+
+	t.Run("MessageResult validation fail", func(t *testing.T) {
+		expectedErrorPayload := `{"status":"must be a valid value"}`
+		result := MessageResult{Status: "done"}
+		err := result.Validate()
+		assert.NotNil(t, err)
+		errorPayload, err := json.Marshal(err)
+		assert.Nil(t, err)
+		assert.Equal(t, expectedErrorPayload, string(errorPayload))
+	})
+
+	t.Run("MessageError validation fail", func(t *testing.T) {
+		expectedErrorPayload := `{"error":"cannot be blank","error_code":"cannot be blank"}`
+		messageError := MessageError{Code: new(string)}
+		err := messageError.Validate()
+		assert.NotNil(t, err)
+		errorPayload, err := json.Marshal(err)
+		assert.Nil(t, err)
+		assert.Equal(t, expectedErrorPayload, string(errorPayload))
+	})
+
+	t.Run("SetSource", func(t *testing.T) {
+		queueID := "my-queue"
+		subscription := Subscription{ID: "my-subscription", TopicID: "my-topic", QueueID: &queueID}
+		m := Message{Body: `{"type": "message"}`}
+
+		m.SetSource("my-publish", "my-root-topic", &subscription)
+
+		assert.Equal(t, "my-publish", *m.PublishID)
+		assert.Equal(t, "my-root-topic", *m.SourceTopicID)
+		assert.Equal(t, subscription.ID, *m.SourceSubscriptionID)
+	})
+
+	t.Run("Validation fail with invalid reply", func(t *testing.T) {
+		expectedErrorPayload := `{"correlation_id":"cannot be blank","reply_to":"must be in a valid format","unique_key":"cannot be blank"}`
+		m := Message{Body: `{"type": "message"}`, ReplyTo: pointString("my@queue"), CorrelationID: pointString(""), UniqueKey: pointString("")}
+		err := m.Validate()
+		assert.NotNil(t, err)
+		errorPayload, err := json.Marshal(err)
+		assert.Nil(t, err)
+		assert.Equal(t, expectedErrorPayload, string(errorPayload))
+	})
+
+	t.Run("Validation fail with unique and coalesce keys", func(t *testing.T) {
+		expectedErrorPayload := `{"coalesce_key":"cannot be used with unique_key"}`
+		m := Message{Body: `{"type": "message"}`, UniqueKey: pointString("my-key"), CoalesceKey: pointString("my-key")}
+		err := m.Validate()
+		assert.NotNil(t, err)
+		errorPayload, err := json.Marshal(err)
+		assert.Nil(t, err)
+		assert.Equal(t, expectedErrorPayload, string(errorPayload))
+	})
+
+	t.Run("SetReply", func(t *testing.T) {
+		m := Message{Body: `{"type": "message"}`}
+
+		m.SetReply("my-reply-queue")
+
+		assert.Equal(t, "my-reply-queue", *m.ReplyTo)
+		assert.NotEmpty(t, *m.CorrelationID)
+	})
+
+	t.Run("SetReply with correlation id", func(t *testing.T) {
+		m := Message{Body: `{"type": "message"}`, CorrelationID: pointString("my-correlation-id")}
+
+		m.SetReply("my-reply-queue")
+
+		assert.Equal(t, "my-reply-queue", *m.ReplyTo)
+		assert.Equal(t, "my-correlation-id", *m.CorrelationID)
+	})
+
+	t.Run("Status", func(t *testing.T) {
+		queue := Queue{
+			ID:                      "my-queue",
+			AckDeadlineSeconds:      60,
+			MessageRetentionSeconds: 3600,
+			DeliveryDelaySeconds:    10,
+		}
+		now := time.Now().UTC()
+
+		scheduled := Message{Body: `{"type": "message"}`}
+		scheduled.Enqueue(&queue, now)
+
+		available := Message{Body: `{"type": "message"}`}
+		available.Enqueue(&queue, now.Add(-time.Minute))
+
+		inFlight := Message{Body: `{"type": "message"}`}
+		inFlight.Enqueue(&queue, now.Add(-time.Minute))
+		inFlight.DeliverySetup(&queue, 0, now)
+
+		acked := Message{Body: `{"type": "message"}`}
+		acked.Enqueue(&queue, now.Add(-time.Minute))
+		acked.DeliverySetup(&queue, 0, now.Add(-time.Minute))
+		acked.Ack(now.Add(-time.Second))
+
+		expired := Message{Body: `{"type": "message"}`}
+		expired.Enqueue(&queue, now.Add(-2*time.Hour))
+
+		nacked := Message{Body: `{"type": "message"}`}
+		nacked.Enqueue(&queue, now.Add(-time.Minute))
+		nacked.DeliverySetup(&queue, 0, now.Add(-time.Minute))
+		nacked.Nack(now, 30)
+
+		deadLettered := Message{Body: `{"type": "message"}`}
+		deadLettered.Enqueue(&queue, now.Add(-time.Minute))
+		deadLettered.State = MessageStateDeadLettered
+
+		tests := []struct {
+			name    string
+			message Message
+			status  string
+		}{
+			{"scheduled", scheduled, MessageStatusScheduled},
+			{"available", available, MessageStatusAvailable},
+			{"in flight", inFlight, MessageStatusInFlight},
+			{"acked", acked, MessageStatusAcked},
+			{"expired", expired, MessageStatusExpired},
+			{"nacked", nacked, MessageStatusScheduled},
+			{"dead lettered", deadLettered, MessageStatusDeadLettered},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				assert.Equal(t, tt.status, tt.message.Status(now))
+			})
+		}
+	})
+
+	t.Run("Lease", func(t *testing.T) {
+		queue := Queue{
+			ID:                      "my-queue",
+			AckDeadlineSeconds:      60,
+			MessageRetentionSeconds: 3600,
+		}
+		label := "my-label"
+		consumerID := "worker-1"
+		m := Message{Body: `{"type": "message"}`, Label: &label}
+
+		m.Enqueue(&queue, time.Now().UTC())
+		now := time.Now().UTC()
+		m.DeliverySetup(&queue, 0, now)
+		m.SetConsumer(&consumerID)
+
+		lease := m.Lease()
+		assert.Equal(t, m.ID, lease.ID)
+		assert.Equal(t, queue.ID, lease.QueueID)
+		assert.Equal(t, &label, lease.Label)
+		assert.Equal(t, &consumerID, lease.ConsumerID)
+		assert.Equal(t, uint(1), lease.DeliveryAttempts)
+		assert.Equal(t, now, *lease.LeasedAt)
+		assert.Equal(t, now.Add(time.Duration(queue.AckDeadlineSeconds)*time.Second), lease.Deadline)
+	})
 }
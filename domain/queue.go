@@ -3,9 +3,22 @@ package domain
 import (
 	"context"
 	"regexp"
+	"strings"
 	"time"
 
 	"github.com/jellydator/validation"
+	"github.com/oklog/ulid/v2"
+)
+
+const (
+	// QueueUniqueKeyPolicyReject rejects the publish of a message with the unique key of a pending message.
+	QueueUniqueKeyPolicyReject = "reject"
+	// QueueUniqueKeyPolicyIgnore ignores the publish of a message with the unique key of a pending message.
+	QueueUniqueKeyPolicyIgnore = "ignore"
+	// QueueDeliveryKeySourceLabel uses the message label as the delivery key.
+	QueueDeliveryKeySourceLabel = "label"
+	// QueueDeliveryKeySourceAttribute uses a message attribute as the delivery key.
+	QueueDeliveryKeySourceAttribute = "attribute"
 )
 
 var (
@@ -14,12 +27,27 @@ var (
 
 // Queue entity.
 type Queue struct {
-	ID                      string    `json:"id" db:"id" form:"id"`
-	AckDeadlineSeconds      uint      `json:"ack_deadline_seconds" db:"ack_deadline_seconds" form:"ack_deadline_seconds"`
-	MessageRetentionSeconds uint      `json:"message_retention_seconds" db:"message_retention_seconds" form:"message_retention_seconds"`
-	DeliveryDelaySeconds    uint      `json:"delivery_delay_seconds" db:"delivery_delay_seconds" form:"delivery_delay_seconds"`
-	CreatedAt               time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt               time.Time `json:"updated_at" db:"updated_at"`
+	ID                          string          `json:"id" db:"id" form:"id"`
+	AckDeadlineSeconds          uint            `json:"ack_deadline_seconds" db:"ack_deadline_seconds" form:"ack_deadline_seconds"`
+	MessageRetentionSeconds     uint            `json:"message_retention_seconds" db:"message_retention_seconds" form:"message_retention_seconds"`
+	DeliveryDelaySeconds        uint            `json:"delivery_delay_seconds" db:"delivery_delay_seconds" form:"delivery_delay_seconds"`
+	ResultRetentionSeconds      uint            `json:"result_retention_seconds" db:"result_retention_seconds" form:"result_retention_seconds"`
+	UniqueKeyPolicy             string          `json:"unique_key_policy" db:"unique_key_policy" form:"unique_key_policy"`
+	Coalesce                    bool            `json:"coalesce" db:"coalesce" form:"coalesce"`
+	DeliveryKeySource           string          `json:"delivery_key_source" db:"delivery_key_source" form:"delivery_key_source"`
+	DeliveryKeyAttribute        *string         `json:"delivery_key_attribute" db:"delivery_key_attribute" form:"delivery_key_attribute"`
+	MaxInFlightPerKey           uint            `json:"max_in_flight_per_key" db:"max_in_flight_per_key" form:"max_in_flight_per_key"`
+	FairDelivery                bool            `json:"fair_delivery" db:"fair_delivery" form:"fair_delivery"`
+	FairDeliveryWeights         map[string]uint `json:"fair_delivery_weights" db:"fair_delivery_weights" form:"fair_delivery_weights"`
+	MaxDeliveriesPerSecond      uint            `json:"max_deliveries_per_second" db:"max_deliveries_per_second" form:"max_deliveries_per_second"`
+	DeliveryBurst               uint            `json:"delivery_burst" db:"delivery_burst" form:"delivery_burst"`
+	MaxVisibilityTimeoutSeconds uint            `json:"max_visibility_timeout_seconds" db:"max_visibility_timeout_seconds" form:"max_visibility_timeout_seconds"`
+	DeliveryLog                 bool            `json:"delivery_log" db:"delivery_log" form:"delivery_log"`
+	DeliveryLogRetentionSeconds uint            `json:"delivery_log_retention_seconds" db:"delivery_log_retention_seconds" form:"delivery_log_retention_seconds"`
+	AckedRetentionSeconds       uint            `json:"acked_retention_seconds" db:"acked_retention_seconds" form:"acked_retention_seconds"`
+	ExpiredAt                   *time.Time      `json:"-" db:"expired_at"`
+	CreatedAt                   time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt                   time.Time       `json:"updated_at" db:"updated_at"`
 }
 
 func (q Queue) Validate() error {
@@ -27,13 +55,73 @@ func (q Queue) Validate() error {
 		validation.Field(&q.ID, validation.Required, validation.Match(idRegex)),
 		validation.Field(&q.AckDeadlineSeconds, validation.Required),
 		validation.Field(&q.MessageRetentionSeconds, validation.Required),
+		validation.Field(&q.UniqueKeyPolicy, validation.In(QueueUniqueKeyPolicyReject, QueueUniqueKeyPolicyIgnore)),
+		validation.Field(&q.DeliveryKeySource, validation.In(QueueDeliveryKeySourceLabel, QueueDeliveryKeySourceAttribute)),
+		validation.Field(&q.DeliveryKeyAttribute, validation.When(q.DeliveryKeySource == QueueDeliveryKeySourceAttribute, validation.Required).Else(validation.Nil)),
+		validation.Field(&q.FairDeliveryWeights, validation.Each(validation.Required)),
+		validation.Field(&q.MaxVisibilityTimeoutSeconds, validation.When(q.MaxVisibilityTimeoutSeconds > 0, validation.Min(q.AckDeadlineSeconds))),
 	)
 }
 
+// SetDefaults fills the optional fields that were not informed.
+func (q *Queue) SetDefaults() {
+	if q.UniqueKeyPolicy == "" {
+		q.UniqueKeyPolicy = QueueUniqueKeyPolicyReject
+	}
+	if q.DeliveryKeySource == "" {
+		q.DeliveryKeySource = QueueDeliveryKeySourceLabel
+	}
+	if q.DeliveryBurst == 0 {
+		q.DeliveryBurst = q.MaxDeliveriesPerSecond
+	}
+	if q.DeliveryLog && q.DeliveryLogRetentionSeconds == 0 {
+		q.DeliveryLogRetentionSeconds = q.MessageRetentionSeconds
+	}
+}
+
+// VisibilityTimeoutSeconds returns the visibility timeout of a delivery, the ack deadline when no timeout is requested,
+// otherwise the requested timeout bounded by the max visibility timeout of the queue.
+func (q *Queue) VisibilityTimeoutSeconds(requested uint) uint {
+	if requested == 0 {
+		return q.AckDeadlineSeconds
+	}
+	return min(requested, max(q.MaxVisibilityTimeoutSeconds, q.AckDeadlineSeconds))
+}
+
+// NewReplyQueue returns a temporary queue that receives the reply of a request, it expires with the request so the next request removes it when the request could not delete it.
+func NewReplyQueue(timeout time.Duration, now time.Time) *Queue {
+	retentionSeconds := uint(timeout.Seconds()) + 1
+	expiredAt := now.Add(time.Duration(retentionSeconds) * time.Second)
+	return &Queue{
+		ID:                      "reply-" + strings.ToLower(ulid.Make().String()),
+		AckDeadlineSeconds:      retentionSeconds,
+		MessageRetentionSeconds: retentionSeconds,
+		UniqueKeyPolicy:         QueueUniqueKeyPolicyReject,
+		DeliveryKeySource:       QueueDeliveryKeySourceLabel,
+		ExpiredAt:               &expiredAt,
+		CreatedAt:               now,
+		UpdatedAt:               now,
+	}
+}
+
 // QueueStats entity.
 type QueueStats struct {
 	NumUndeliveredMessages         uint `json:"num_undelivered_messages"`
 	OldestUnackedMessageAgeSeconds uint `json:"oldest_unacked_message_age_seconds"`
+	NumCoalescedMessages           uint `json:"num_coalesced_messages"`
+	NumAckedMessages               uint `json:"num_acked_messages"`
+	NumExpiredMessages             uint `json:"num_expired_messages"`
+}
+
+// QueueSeek entity, the acked messages after the timestamp are delivered again.
+type QueueSeek struct {
+	Timestamp time.Time `json:"timestamp" form:"timestamp"`
+}
+
+func (q QueueSeek) Validate() error {
+	return validation.ValidateStruct(&q,
+		validation.Field(&q.Timestamp, validation.Required),
+	)
 }
 
 // QueueRepository is the repository interface for the Queue entity.
@@ -46,6 +134,11 @@ type QueueRepository interface {
 	Stats(ctx context.Context, id string) (*QueueStats, error)
 	Purge(ctx context.Context, id string) error
 	Cleanup(ctx context.Context, id string) error
+	// DeleteExpired removes the temporary reply queues left behind by the requests that could not delete them.
+	DeleteExpired(ctx context.Context) error
+	Seek(ctx context.Context, queue *Queue, timestamp time.Time) error
+	TakeDeliveries(ctx context.Context, queue *Queue, n uint) (uint, time.Duration, error)
+	ReturnDeliveries(ctx context.Context, queue *Queue, n uint) error
 }
 
 // QueueService is the service interface for the Queue entity.
@@ -58,4 +151,5 @@ type QueueService interface {
 	Stats(ctx context.Context, id string) (*QueueStats, error)
 	Purge(ctx context.Context, id string) error
 	Cleanup(ctx context.Context, id string) error
+	Seek(ctx context.Context, id string, seek *QueueSeek) error
 }
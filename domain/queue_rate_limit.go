@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// QueueRateLimit entity, a token bucket that limits the deliveries of a queue.
+type QueueRateLimit struct {
+	QueueID   string    `db:"queue_id"`
+	Tokens    float64   `db:"tokens"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// Refill adds the tokens accumulated since the last update, up to the queue delivery burst.
+func (q *QueueRateLimit) Refill(queue *Queue, now time.Time) {
+	if now.After(q.UpdatedAt) {
+		elapsed := now.Sub(q.UpdatedAt).Seconds()
+		q.Tokens = math.Min(float64(queue.DeliveryBurst), q.Tokens+elapsed*float64(queue.MaxDeliveriesPerSecond))
+	}
+	q.UpdatedAt = now
+}
+
+// Take refills the bucket and takes up to n tokens, returning the number of tokens taken.
+func (q *QueueRateLimit) Take(queue *Queue, n uint, now time.Time) uint {
+	q.Refill(queue, now)
+	taken := uint(math.Min(float64(n), math.Floor(q.Tokens)))
+	q.Tokens -= float64(taken)
+	return taken
+}
+
+// RetryAfter returns the time until the next token is available.
+func (q *QueueRateLimit) RetryAfter(queue *Queue) time.Duration {
+	if q.Tokens >= 1 || queue.MaxDeliveriesPerSecond == 0 {
+		return 0
+	}
+	return time.Duration((1 - q.Tokens) / float64(queue.MaxDeliveriesPerSecond) * float64(time.Second))
+}
+
+// NewQueueRateLimit returns a full token bucket for the queue.
+func NewQueueRateLimit(queue *Queue, now time.Time) *QueueRateLimit {
+	return &QueueRateLimit{QueueID: queue.ID, Tokens: float64(queue.DeliveryBurst), UpdatedAt: now}
+}
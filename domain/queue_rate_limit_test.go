@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueueRateLimit(t *testing.T) {
+	queue := Queue{ID: "my-queue", AckDeadlineSeconds: 60, MessageRetentionSeconds: 3600, MaxDeliveriesPerSecond: 2, DeliveryBurst: 4}
+
+	t.Run("Take", func(t *testing.T) {
+		now := time.Now().UTC()
+		rateLimit := NewQueueRateLimit(&queue, now)
+
+		assert.Equal(t, uint(3), rateLimit.Take(&queue, 3, now))
+		assert.Equal(t, uint(1), rateLimit.Take(&queue, 3, now))
+		assert.Equal(t, uint(0), rateLimit.Take(&queue, 3, now))
+		assert.Equal(t, 500*time.Millisecond, rateLimit.RetryAfter(&queue))
+	})
+
+	t.Run("Refill", func(t *testing.T) {
+		now := time.Now().UTC()
+		rateLimit := QueueRateLimit{QueueID: queue.ID, Tokens: 0, UpdatedAt: now}
+
+		rateLimit.Refill(&queue, now.Add(time.Second))
+		assert.Equal(t, float64(2), rateLimit.Tokens)
+		assert.Equal(t, time.Duration(0), rateLimit.RetryAfter(&queue))
+
+		rateLimit.Refill(&queue, now.Add(10*time.Second))
+		assert.Equal(t, float64(4), rateLimit.Tokens)
+	})
+}
@@ -2,19 +2,22 @@ package domain
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
 
 func TestQueue(t *testing.T) {
 	t.Run("Validation fail", func(t *testing.T) {
-		expectedErrorPayload := `{"ack_deadline_seconds":"cannot be blank","id":"must be in a valid format","message_retention_seconds":"cannot be blank"}`
+		expectedErrorPayload := `{"ack_deadline_seconds":"cannot be blank","id":"must be in a valid format","message_retention_seconds":"cannot be blank","unique_key_policy":"must be a valid value"}`
 		queue := Queue{
 			ID:                      "my@invalid@id",
 			AckDeadlineSeconds:      0,
 			MessageRetentionSeconds: 0,
 			DeliveryDelaySeconds:    0,
+			UniqueKeyPolicy:         "replace",
 		}
 		err := queue.Validate()
 		assert.NotNil(t, err)
@@ -33,4 +36,83 @@ func TestQueue(t *testing.T) {
 		err := queue.Validate()
 		assert.Nil(t, err)
 	})
+
+	t.Run("Validation fail with delivery key", func(t *testing.T) {
+		expectedErrorPayload := `{"delivery_key_attribute":"cannot be blank"}`
+		queue := Queue{ID: "my-queue", AckDeadlineSeconds: 60, MessageRetentionSeconds: 3600, DeliveryKeySource: QueueDeliveryKeySourceAttribute}
+		err := queue.Validate()
+		assert.NotNil(t, err)
+		errorPayload, err := json.Marshal(err)
+		assert.Nil(t, err)
+		assert.Equal(t, expectedErrorPayload, string(errorPayload))
+	})
+
+	t.Run("Validation fail with fair delivery weights", func(t *testing.T) {
+		expectedErrorPayload := `{"fair_delivery_weights":{"tenant-1":"cannot be blank"}}`
+		queue := Queue{ID: "my-queue", AckDeadlineSeconds: 60, MessageRetentionSeconds: 3600, FairDelivery: true, FairDeliveryWeights: map[string]uint{"tenant-1": 0, "tenant-2": 2}}
+		err := queue.Validate()
+		assert.NotNil(t, err)
+		errorPayload, err := json.Marshal(err)
+		assert.Nil(t, err)
+		assert.Equal(t, expectedErrorPayload, string(errorPayload))
+	})
+
+	t.Run("Validation fail with max visibility timeout", func(t *testing.T) {
+		expectedErrorPayload := `{"max_visibility_timeout_seconds":"must be no less than 60"}`
+		queue := Queue{ID: "my-queue", AckDeadlineSeconds: 60, MessageRetentionSeconds: 3600, MaxVisibilityTimeoutSeconds: 30}
+		err := queue.Validate()
+		assert.NotNil(t, err)
+		errorPayload, err := json.Marshal(err)
+		assert.Nil(t, err)
+		assert.Equal(t, expectedErrorPayload, string(errorPayload))
+	})
+
+	t.Run("VisibilityTimeoutSeconds", func(t *testing.T) {
+		queue := Queue{ID: "my-queue", AckDeadlineSeconds: 60, MessageRetentionSeconds: 3600}
+
+		assert.Equal(t, uint(60), queue.VisibilityTimeoutSeconds(0))
+		assert.Equal(t, uint(10), queue.VisibilityTimeoutSeconds(10))
+		assert.Equal(t, uint(60), queue.VisibilityTimeoutSeconds(600))
+
+		queue.MaxVisibilityTimeoutSeconds = 300
+		assert.Equal(t, uint(300), queue.VisibilityTimeoutSeconds(600))
+		assert.Equal(t, uint(120), queue.VisibilityTimeoutSeconds(120))
+	})
+
+	t.Run("SetDefaults", func(t *testing.T) {
+		queue := Queue{ID: "my-queue"}
+
+		queue.SetDefaults()
+		assert.Equal(t, QueueUniqueKeyPolicyReject, queue.UniqueKeyPolicy)
+		assert.Equal(t, QueueDeliveryKeySourceLabel, queue.DeliveryKeySource)
+
+		queue.UniqueKeyPolicy = QueueUniqueKeyPolicyIgnore
+		queue.SetDefaults()
+		assert.Equal(t, QueueUniqueKeyPolicyIgnore, queue.UniqueKeyPolicy)
+
+		queue.MaxDeliveriesPerSecond = 10
+		queue.SetDefaults()
+		assert.Equal(t, uint(10), queue.DeliveryBurst)
+
+		queue.MessageRetentionSeconds = 3600
+		queue.SetDefaults()
+		assert.Equal(t, uint(0), queue.DeliveryLogRetentionSeconds)
+
+		queue.DeliveryLog = true
+		queue.SetDefaults()
+		assert.Equal(t, uint(3600), queue.DeliveryLogRetentionSeconds)
+	})
+
+	t.Run("NewReplyQueue", func(t *testing.T) {
+		now := time.Now().UTC()
+
+		queue := NewReplyQueue(10*time.Second, now)
+
+		assert.Nil(t, queue.Validate())
+		assert.True(t, strings.HasPrefix(queue.ID, "reply-"))
+		assert.Equal(t, uint(11), queue.AckDeadlineSeconds)
+		assert.Equal(t, uint(11), queue.MessageRetentionSeconds)
+		assert.Equal(t, now.Add(11*time.Second), *queue.ExpiredAt)
+		assert.Equal(t, now, queue.CreatedAt)
+	})
 }
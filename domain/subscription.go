@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"encoding/json"
 	"slices"
 	"time"
 
@@ -11,50 +12,171 @@ import (
 
 // Subscription entity.
 type Subscription struct {
-	ID             string              `json:"id" db:"id" form:"id"`
-	TopicID        string              `json:"topic_id" db:"topic_id" form:"topic_id"`
-	QueueID        string              `json:"queue_id" db:"queue_id" form:"queue_id"`
-	MessageFilters map[string][]string `json:"message_filters" db:"message_filters" form:"message_filters"`
-	CreatedAt      time.Time           `json:"created_at" db:"created_at"`
+	ID             string                      `json:"id" db:"id" form:"id"`
+	TopicID        string                      `json:"topic_id" db:"topic_id" form:"topic_id"`
+	QueueID        *string                     `json:"queue_id" db:"queue_id" form:"queue_id"`
+	TargetTopicID  *string                     `json:"target_topic_id" db:"target_topic_id" form:"target_topic_id"`
+	MessageFilters map[string][]string         `json:"message_filters" db:"message_filters" form:"message_filters"`
+	Transformation *SubscriptionTransformation `json:"transformation" db:"transformation" form:"transformation"`
+	CreatedAt      time.Time                   `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time                   `json:"updated_at" db:"updated_at"`
 }
 
 func (s Subscription) Validate() error {
 	return validation.ValidateStruct(&s,
 		validation.Field(&s.ID, validation.Required, validation.Match(idRegex)),
 		validation.Field(&s.TopicID, validation.Required, validation.Match(idRegex)),
-		validation.Field(&s.QueueID, validation.Required, validation.Match(idRegex)),
+		validation.Field(&s.QueueID, validation.When(s.TargetTopicID == nil, validation.Required).Else(validation.Nil), validation.Match(idRegex)),
+		validation.Field(&s.TargetTopicID, validation.NilOrNotEmpty, validation.Match(idRegex), validation.NotIn(s.TopicID).Error("must be different from topic_id")),
+		validation.Field(&s.MessageFilters, validation.Each(validation.Required)),
+		validation.Field(&s.Transformation),
 	)
 }
 
-func (s *Subscription) ShouldCreateMessage(message *Message) bool {
-	if len(s.MessageFilters) == 0 {
-		return true
-	}
+func (s *Subscription) Match(message *Message) *SubscriptionMatch {
+	match := &SubscriptionMatch{SubscriptionID: s.ID, QueueID: s.QueueID, TargetTopicID: s.TargetTopicID, Matched: true}
 
-	messageAttributesKeys := maps.Keys(message.Attributes)
 	messageFiltersKeys := maps.Keys(s.MessageFilters)
+	slices.Sort(messageFiltersKeys)
 	for _, key := range messageFiltersKeys {
-		if !slices.Contains(messageAttributesKeys, key) || !slices.Contains(s.MessageFilters[key], message.Attributes[key]) {
-			return false
+		value, ok := message.Attributes[key]
+		if !ok || !slices.Contains(s.MessageFilters[key], value) {
+			match.Matched = false
+			match.RejectedFilterKey = &key
+			break
 		}
 	}
 
-	return true
+	return match
+}
+
+func (s *Subscription) ShouldCreateMessage(message *Message) bool {
+	return s.Match(message).Matched
+}
+
+func (s *Subscription) Transform(message *Message, publishedAt time.Time) (*Message, error) {
+	newMessage := &Message{
+		Label:         message.Label,
+		Body:          message.Body,
+		Attributes:    maps.Clone(message.Attributes),
+		ReplyTo:       message.ReplyTo,
+		CorrelationID: message.CorrelationID,
+	}
+
+	if s.Transformation == nil {
+		return newMessage, nil
+	}
+
+	for _, key := range s.Transformation.RemoveAttributes {
+		delete(newMessage.Attributes, key)
+	}
+
+	if len(s.Transformation.SetAttributes) > 0 && newMessage.Attributes == nil {
+		newMessage.Attributes = make(map[string]string, len(s.Transformation.SetAttributes))
+	}
+	for key, value := range s.Transformation.SetAttributes {
+		newMessage.Attributes[key] = value
+	}
+
+	if s.Transformation.SetLabel != nil {
+		label := *s.Transformation.SetLabel
+		newMessage.Label = &label
+	}
+
+	if s.Transformation.Envelope {
+		envelope := MessageEnvelope{
+			TopicID:        s.TopicID,
+			SubscriptionID: s.ID,
+			PublishedAt:    publishedAt,
+			Body:           message.Body,
+		}
+		body, err := json.Marshal(&envelope)
+		if err != nil {
+			return nil, err
+		}
+		newMessage.Body = string(body)
+	}
+
+	return newMessage, nil
+}
+
+// SubscriptionTransformation entity.
+type SubscriptionTransformation struct {
+	SetAttributes    map[string]string `json:"set_attributes"`
+	RemoveAttributes []string          `json:"remove_attributes"`
+	SetLabel         *string           `json:"set_label"`
+	Envelope         bool              `json:"envelope"`
+}
+
+func (s SubscriptionTransformation) Validate() error {
+	return validation.ValidateStruct(&s,
+		validation.Field(&s.SetAttributes, validation.By(func(value interface{}) error {
+			for _, key := range s.RemoveAttributes {
+				if _, ok := s.SetAttributes[key]; ok {
+					return validation.NewError("validation_conflicting_attribute", "cannot set and remove the same attribute")
+				}
+			}
+			return nil
+		})),
+		validation.Field(&s.RemoveAttributes, validation.Each(validation.Required)),
+		validation.Field(&s.SetLabel, validation.NilOrNotEmpty),
+	)
+}
+
+// MessageEnvelope is the body of a message wrapped by a subscription transformation.
+type MessageEnvelope struct {
+	TopicID        string    `json:"topic_id"`
+	SubscriptionID string    `json:"subscription_id"`
+	PublishedAt    time.Time `json:"published_at"`
+	Body           string    `json:"body"`
+}
+
+// SubscriptionMatch entity.
+type SubscriptionMatch struct {
+	SubscriptionID    string  `json:"subscription_id"`
+	QueueID           *string `json:"queue_id"`
+	TargetTopicID     *string `json:"target_topic_id"`
+	Matched           bool    `json:"matched"`
+	RejectedFilterKey *string `json:"rejected_filter_key"`
+}
+
+// SubscriptionReplay entity.
+type SubscriptionReplay struct {
+	SubscriptionID string    `json:"subscription_id"`
+	QueueID        *string   `json:"queue_id"`
+	TargetTopicID  *string   `json:"target_topic_id"`
+	From           time.Time `json:"from" form:"from"`
+	To             time.Time `json:"to" form:"to"`
+	PublishID      string    `json:"publish_id"`
+	NumMessages    uint      `json:"num_messages"`
+}
+
+func (s SubscriptionReplay) Validate() error {
+	return validation.ValidateStruct(&s,
+		validation.Field(&s.From, validation.Required),
+		validation.Field(&s.To, validation.Required, validation.Min(s.From)),
+	)
 }
 
 // SubscriptionRepository is the repository interface for the Subscription entity.
 type SubscriptionRepository interface {
 	Create(ctx context.Context, subscription *Subscription) error
+	Update(ctx context.Context, subscription *Subscription) error
 	Get(ctx context.Context, id string) (*Subscription, error)
 	List(ctx context.Context, offset, limit uint) ([]*Subscription, error)
 	ListByTopic(ctx context.Context, topicID string, offset, limit uint) ([]*Subscription, error)
+	ListByQueue(ctx context.Context, queueID string, offset, limit uint) ([]*Subscription, error)
 	Delete(ctx context.Context, id string) error
 }
 
 // SubscriptionService is the service interface for the Subscription entity.
 type SubscriptionService interface {
 	Create(ctx context.Context, subscription *Subscription) error
+	Update(ctx context.Context, subscription *Subscription) error
 	Get(ctx context.Context, id string) (*Subscription, error)
 	List(ctx context.Context, offset, limit uint) ([]*Subscription, error)
+	ListByTopic(ctx context.Context, topicID string, offset, limit uint) ([]*Subscription, error)
+	ListByQueue(ctx context.Context, queueID string, offset, limit uint) ([]*Subscription, error)
 	Delete(ctx context.Context, id string) error
+	Replay(ctx context.Context, replay *SubscriptionReplay) error
 }
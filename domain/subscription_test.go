@@ -3,14 +3,19 @@ package domain
 import (
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
 
+func pointString(x string) *string {
+	return &x
+}
+
 func TestSubscription(t *testing.T) {
 	t.Run("Validation fail", func(t *testing.T) {
 		expectedErrorPayload := `{"id":"must be in a valid format","queue_id":"must be in a valid format","topic_id":"must be in a valid format"}`
-		subs := Subscription{ID: "my@invalid@id", TopicID: "my@invalid@id", QueueID: "my@invalid@id"}
+		subs := Subscription{ID: "my@invalid@id", TopicID: "my@invalid@id", QueueID: pointString("my@invalid@id")}
 		err := subs.Validate()
 		assert.NotNil(t, err)
 		errorPayload, err := json.Marshal(err)
@@ -18,8 +23,85 @@ func TestSubscription(t *testing.T) {
 		assert.Equal(t, expectedErrorPayload, string(errorPayload))
 	})
 
+	t.Run("Validation fail with empty message filter", func(t *testing.T) {
+		expectedErrorPayload := `{"message_filters":{"type":"cannot be blank"}}`
+		subs := Subscription{ID: "my-subscription", TopicID: "my-topic", QueueID: pointString("my-queue"), MessageFilters: map[string][]string{"type": {}}}
+		err := subs.Validate()
+		assert.NotNil(t, err)
+		errorPayload, err := json.Marshal(err)
+		assert.Nil(t, err)
+		assert.Equal(t, expectedErrorPayload, string(errorPayload))
+	})
+
+	t.Run("Validation fail with invalid target", func(t *testing.T) {
+		tests := []struct {
+			subscription         Subscription
+			expectedErrorPayload string
+		}{
+			{
+				subscription:         Subscription{ID: "my-subscription", TopicID: "my-topic"},
+				expectedErrorPayload: `{"queue_id":"cannot be blank"}`,
+			},
+			{
+				subscription:         Subscription{ID: "my-subscription", TopicID: "my-topic", QueueID: pointString("my-queue"), TargetTopicID: pointString("my-target-topic")},
+				expectedErrorPayload: `{"queue_id":"must be blank"}`,
+			},
+			{
+				subscription:         Subscription{ID: "my-subscription", TopicID: "my-topic", TargetTopicID: pointString("my-topic")},
+				expectedErrorPayload: `{"target_topic_id":"must be different from topic_id"}`,
+			},
+		}
+
+		for i := range tests {
+			t.Run("", func(t *testing.T) {
+				err := tests[i].subscription.Validate()
+				assert.NotNil(t, err)
+				errorPayload, err := json.Marshal(err)
+				assert.Nil(t, err)
+				assert.Equal(t, tests[i].expectedErrorPayload, string(errorPayload))
+			})
+		}
+	})
+
+	t.Run("Validation fail with invalid transformation", func(t *testing.T) {
+		tests := []struct {
+			transformation       SubscriptionTransformation
+			expectedErrorPayload string
+		}{
+			{
+				transformation:       SubscriptionTransformation{SetAttributes: map[string]string{"type": "message"}, RemoveAttributes: []string{"type"}},
+				expectedErrorPayload: `{"transformation":{"set_attributes":"cannot set and remove the same attribute"}}`,
+			},
+			{
+				transformation:       SubscriptionTransformation{RemoveAttributes: []string{""}},
+				expectedErrorPayload: `{"transformation":{"remove_attributes":{"0":"cannot be blank"}}}`,
+			},
+			{
+				transformation:       SubscriptionTransformation{SetLabel: pointString("")},
+				expectedErrorPayload: `{"transformation":{"set_label":"cannot be blank"}}`,
+			},
+		}
+
+		for i := range tests {
+			t.Run("", func(t *testing.T) {
+				subs := Subscription{ID: "my-subscription", TopicID: "my-topic", QueueID: pointString("my-queue"), Transformation: &tests[i].transformation}
+				err := subs.Validate()
+				assert.NotNil(t, err)
+				errorPayload, err := json.Marshal(err)
+				assert.Nil(t, err)
+				assert.Equal(t, tests[i].expectedErrorPayload, string(errorPayload))
+			})
+		}
+	})
+
 	t.Run("Validation ok", func(t *testing.T) {
-		subs := Subscription{ID: "my-subscription", TopicID: "my-topic", QueueID: "my-queue"}
+		subs := Subscription{ID: "my-subscription", TopicID: "my-topic", QueueID: pointString("my-queue")}
+		err := subs.Validate()
+		assert.Nil(t, err)
+	})
+
+	t.Run("Validation ok with target topic", func(t *testing.T) {
+		subs := Subscription{ID: "my-subscription", TopicID: "my-topic", TargetTopicID: pointString("my-target-topic")}
 		err := subs.Validate()
 		assert.Nil(t, err)
 	})
@@ -93,4 +175,136 @@ func TestSubscription(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("Match", func(t *testing.T) {
+		tests := []struct {
+			subscription      Subscription
+			message           Message
+			matched           bool
+			rejectedFilterKey *string
+		}{
+			{
+				subscription:      Subscription{ID: "my-subscription", QueueID: pointString("my-queue")},
+				message:           Message{},
+				matched:           true,
+				rejectedFilterKey: nil,
+			},
+			{
+				subscription:      Subscription{ID: "my-subscription", QueueID: pointString("my-queue"), MessageFilters: map[string][]string{"type": {"message"}}},
+				message:           Message{},
+				matched:           false,
+				rejectedFilterKey: pointString("type"),
+			},
+			{
+				subscription:      Subscription{ID: "my-subscription", QueueID: pointString("my-queue"), MessageFilters: map[string][]string{"type": {"message"}, "subtype": {"post"}}},
+				message:           Message{Attributes: map[string]string{"type": "message", "subtype": "comment"}},
+				matched:           false,
+				rejectedFilterKey: pointString("subtype"),
+			},
+			{
+				subscription:      Subscription{ID: "my-subscription", QueueID: pointString("my-queue"), MessageFilters: map[string][]string{"type": {"message"}, "subtype": {"post"}}},
+				message:           Message{Attributes: map[string]string{"type": "message2", "subtype": "comment"}},
+				matched:           false,
+				rejectedFilterKey: pointString("subtype"),
+			},
+			{
+				subscription:      Subscription{ID: "my-subscription", QueueID: pointString("my-queue"), MessageFilters: map[string][]string{"type": {"message"}, "subtype": {"post"}}},
+				message:           Message{Attributes: map[string]string{"type": "message2", "subtype": "post"}},
+				matched:           false,
+				rejectedFilterKey: pointString("type"),
+			},
+			{
+				subscription:      Subscription{ID: "my-subscription", QueueID: pointString("my-queue"), MessageFilters: map[string][]string{"type": {"message"}, "subtype": {"post"}}},
+				message:           Message{Attributes: map[string]string{"type": "message", "subtype": "post"}},
+				matched:           true,
+				rejectedFilterKey: nil,
+			},
+		}
+
+		for i := range tests {
+			t.Run("", func(t *testing.T) {
+				match := tests[i].subscription.Match(&tests[i].message)
+				assert.Equal(t, tests[i].subscription.ID, match.SubscriptionID)
+				assert.Equal(t, tests[i].subscription.QueueID, match.QueueID)
+				assert.Equal(t, tests[i].matched, match.Matched)
+				assert.Equal(t, tests[i].rejectedFilterKey, match.RejectedFilterKey)
+			})
+		}
+	})
+
+	t.Run("Transform", func(t *testing.T) {
+		publishedAt := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+		tests := []struct {
+			transformation *SubscriptionTransformation
+			message        Message
+			expected       Message
+		}{
+			{
+				transformation: nil,
+				message:        Message{Label: pointString("label"), Body: "body", Attributes: map[string]string{"type": "message"}},
+				expected:       Message{Label: pointString("label"), Body: "body", Attributes: map[string]string{"type": "message"}},
+			},
+			{
+				transformation: &SubscriptionTransformation{SetAttributes: map[string]string{"source": "orders"}},
+				message:        Message{Body: "body"},
+				expected:       Message{Body: "body", Attributes: map[string]string{"source": "orders"}},
+			},
+			{
+				transformation: &SubscriptionTransformation{SetAttributes: map[string]string{"type": "post"}},
+				message:        Message{Body: "body", Attributes: map[string]string{"type": "message", "subtype": "comment"}},
+				expected:       Message{Body: "body", Attributes: map[string]string{"type": "post", "subtype": "comment"}},
+			},
+			{
+				transformation: &SubscriptionTransformation{RemoveAttributes: []string{"subtype", "missing"}},
+				message:        Message{Body: "body", Attributes: map[string]string{"type": "message", "subtype": "comment"}},
+				expected:       Message{Body: "body", Attributes: map[string]string{"type": "message"}},
+			},
+			{
+				transformation: &SubscriptionTransformation{SetLabel: pointString("new-label")},
+				message:        Message{Label: pointString("label"), Body: "body"},
+				expected:       Message{Label: pointString("new-label"), Body: "body"},
+			},
+			{
+				transformation: &SubscriptionTransformation{Envelope: true},
+				message:        Message{Body: `{"id": 1}`},
+				expected:       Message{Body: `{"topic_id":"my-topic","subscription_id":"my-subscription","published_at":"2024-01-02T00:00:00Z","body":"{\"id\": 1}"}`},
+			},
+		}
+
+		for i := range tests {
+			t.Run("", func(t *testing.T) {
+				subs := Subscription{ID: "my-subscription", TopicID: "my-topic", QueueID: pointString("my-queue"), Transformation: tests[i].transformation}
+				message, err := subs.Transform(&tests[i].message, publishedAt)
+				assert.Nil(t, err)
+				assert.Equal(t, &tests[i].expected, message)
+			})
+		}
+	})
+
+	t.Run("Transform does not change the original message", func(t *testing.T) {
+		subs := Subscription{ID: "my-subscription", TopicID: "my-topic", QueueID: pointString("my-queue"), Transformation: &SubscriptionTransformation{RemoveAttributes: []string{"type"}}}
+		message := Message{Body: "body", Attributes: map[string]string{"type": "message"}}
+		_, err := subs.Transform(&message, time.Now().UTC())
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]string{"type": "message"}, message.Attributes)
+	})
+}
+
+func TestSubscriptionReplay(t *testing.T) {
+	t.Run("Validation fail", func(t *testing.T) {
+		expectedErrorPayload := `{"from":"cannot be blank","to":"cannot be blank"}`
+		replay := SubscriptionReplay{SubscriptionID: "my-subscription"}
+		err := replay.Validate()
+		assert.NotNil(t, err)
+		errorPayload, err := json.Marshal(err)
+		assert.Nil(t, err)
+		assert.Equal(t, expectedErrorPayload, string(errorPayload))
+	})
+
+	t.Run("Validation ok", func(t *testing.T) {
+		now := time.Now().UTC()
+		replay := SubscriptionReplay{SubscriptionID: "my-subscription", From: now.Add(-time.Hour), To: now}
+		err := replay.Validate()
+		assert.Nil(t, err)
+	})
 }
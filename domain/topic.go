@@ -2,6 +2,10 @@ package domain
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
 	"time"
 
 	"github.com/jellydator/validation"
@@ -9,16 +13,138 @@ import (
 
 // Topic entity.
 type Topic struct {
-	ID        string    `json:"id" db:"id" form:"id"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	ID                      string          `json:"id" db:"id" form:"id"`
+	MessageRetentionSeconds uint            `json:"message_retention_seconds" db:"message_retention_seconds" form:"message_retention_seconds"`
+	Ingestion               *TopicIngestion `json:"ingestion" db:"ingestion" form:"ingestion"`
+	CreatedAt               time.Time       `json:"created_at" db:"created_at"`
 }
 
 func (t Topic) Validate() error {
 	return validation.ValidateStruct(&t,
 		validation.Field(&t.ID, validation.Required, validation.Match(idRegex)),
+		validation.Field(&t.Ingestion),
 	)
 }
 
+// TopicIngestion entity.
+type TopicIngestion struct {
+	HeaderAttributes map[string]string `json:"header_attributes"`
+	LabelHeader      *string           `json:"label_header"`
+	SignatureHeader  *string           `json:"signature_header"`
+	SignaturePrefix  string            `json:"signature_prefix"`
+	SignatureSecret  *string           `json:"signature_secret"`
+}
+
+func (t TopicIngestion) Validate() error {
+	return validation.ValidateStruct(&t,
+		validation.Field(&t.HeaderAttributes, validation.Each(validation.Required)),
+		validation.Field(&t.LabelHeader, validation.NilOrNotEmpty),
+		validation.Field(&t.SignatureHeader, validation.When(t.SignatureSecret != nil, validation.Required)),
+		validation.Field(&t.SignatureSecret, validation.When(t.SignatureHeader != nil, validation.Required)),
+	)
+}
+
+// VerifySignature checks the HMAC-SHA256 signature of the payload, the headers keys must be lower case.
+func (t *TopicIngestion) VerifySignature(payload []byte, headers map[string]string) error {
+	if t == nil || t.SignatureHeader == nil {
+		return nil
+	}
+
+	value, ok := headers[strings.ToLower(*t.SignatureHeader)]
+	if !ok || !strings.HasPrefix(value, t.SignaturePrefix) {
+		return ErrTopicIngestionInvalidSignature
+	}
+
+	signature, err := hex.DecodeString(strings.TrimPrefix(value, t.SignaturePrefix))
+	if err != nil {
+		return ErrTopicIngestionInvalidSignature
+	}
+
+	mac := hmac.New(sha256.New, []byte(*t.SignatureSecret))
+	mac.Write(payload)
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return ErrTopicIngestionInvalidSignature
+	}
+
+	return nil
+}
+
+// NewMessage returns a message with the payload as body and the configured headers as attributes and label, the headers keys must be lower case.
+func (t *TopicIngestion) NewMessage(payload []byte, headers map[string]string) *Message {
+	message := &Message{Body: string(payload)}
+	if t == nil {
+		return message
+	}
+
+	for header, key := range t.HeaderAttributes {
+		value, ok := headers[strings.ToLower(header)]
+		if !ok {
+			continue
+		}
+		if message.Attributes == nil {
+			message.Attributes = make(map[string]string, len(t.HeaderAttributes))
+		}
+		message.Attributes[key] = value
+	}
+
+	if t.LabelHeader != nil {
+		if value, ok := headers[strings.ToLower(*t.LabelHeader)]; ok && value != "" {
+			message.Label = &value
+		}
+	}
+
+	return message
+}
+
+// Redacted returns a copy of the ingestion without the signature secret.
+func (t *TopicIngestion) Redacted() *TopicIngestion {
+	if t == nil {
+		return nil
+	}
+
+	ingestion := *t
+	if ingestion.SignatureSecret != nil {
+		redacted := "********"
+		ingestion.SignatureSecret = &redacted
+	}
+
+	return &ingestion
+}
+
+// TopicPublish entity.
+type TopicPublish struct {
+	ID                      string   `json:"id"`
+	TopicID                 string   `json:"topic_id"`
+	NumMatchedSubscriptions uint     `json:"num_matched_subscriptions"`
+	MessageIDs              []string `json:"message_ids"`
+}
+
+// TopicPublishMessage entity.
+type TopicPublishMessage struct {
+	ID                   string    `json:"id"`
+	QueueID              string    `json:"queue_id"`
+	SourceTopicID        *string   `json:"source_topic_id"`
+	SourceSubscriptionID *string   `json:"source_subscription_id"`
+	DeliveryAttempts     uint      `json:"delivery_attempts"`
+	Status               string    `json:"status"`
+	CreatedAt            time.Time `json:"created_at"`
+	UpdatedAt            time.Time `json:"updated_at"`
+}
+
+// TopicPublishLineage entity.
+type TopicPublishLineage struct {
+	ID       string                 `json:"id"`
+	TopicID  string                 `json:"topic_id"`
+	Messages []*TopicPublishMessage `json:"messages"`
+}
+
+// TopicSimulation entity.
+type TopicSimulation struct {
+	TopicID                 string               `json:"topic_id"`
+	NumMatchedSubscriptions uint                 `json:"num_matched_subscriptions"`
+	Subscriptions           []*SubscriptionMatch `json:"subscriptions"`
+}
+
 // TopicRepository is the repository interface for the Topic entity.
 type TopicRepository interface {
 	Create(ctx context.Context, topic *Topic) error
@@ -33,5 +159,10 @@ type TopicService interface {
 	Get(ctx context.Context, id string) (*Topic, error)
 	List(ctx context.Context, offset, limit uint) ([]*Topic, error)
 	Delete(ctx context.Context, id string) error
-	CreateMessage(ctx context.Context, topicID string, message *Message) error
+	CreateMessage(ctx context.Context, topicID string, message *Message, requireMatch bool) (*TopicPublish, error)
+	Simulate(ctx context.Context, topicID string, message *Message) (*TopicSimulation, error)
+	Cleanup(ctx context.Context, id string) error
+	Stats(ctx context.Context, id string) (*TopicStats, error)
+	GetPublish(ctx context.Context, topicID, publishID string) (*TopicPublishLineage, error)
+	Ingest(ctx context.Context, topicID string, payload []byte, headers map[string]string, requireMatch bool) (*TopicPublish, error)
 }
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// TopicMessage entity.
+type TopicMessage struct {
+	ID         string            `json:"id" db:"id"`
+	TopicID    string            `json:"topic_id" db:"topic_id"`
+	Label      *string           `json:"label" db:"label"`
+	Body       string            `json:"body" db:"body"`
+	Attributes map[string]string `json:"attributes" db:"attributes"`
+	ExpiredAt  time.Time         `json:"-" db:"expired_at"`
+	CreatedAt  time.Time         `json:"created_at" db:"created_at"`
+}
+
+// NewTopicMessage returns the entry of the topic message log for a message published to the topic.
+func NewTopicMessage(topic *Topic, message *Message, now time.Time) *TopicMessage {
+	return &TopicMessage{
+		ID:         ulid.Make().String(),
+		TopicID:    topic.ID,
+		Label:      message.Label,
+		Body:       message.Body,
+		Attributes: message.Attributes,
+		ExpiredAt:  now.Add(time.Duration(topic.MessageRetentionSeconds) * time.Second),
+		CreatedAt:  now,
+	}
+}
+
+func (t *TopicMessage) Message() *Message {
+	return &Message{
+		Label:      t.Label,
+		Body:       t.Body,
+		Attributes: t.Attributes,
+	}
+}
+
+// TopicPublishBatch holds the rows written by a topic publish, they are stored in a single transaction.
+type TopicPublishBatch struct {
+	TopicMessages        []*TopicMessage
+	Messages             []*Message
+	TopicCounters        []*TopicCounter
+	SubscriptionCounters []*SubscriptionCounter
+}
+
+// TopicMessageRepository is the repository interface for the TopicMessage entity.
+type TopicMessageRepository interface {
+	Publish(ctx context.Context, batch *TopicPublishBatch) error
+	ListByTopic(ctx context.Context, topicID string, from, to time.Time, offset, limit uint) ([]*TopicMessage, error)
+	Cleanup(ctx context.Context, topicID string, statsBefore time.Time) error
+}
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// TopicStatsWindowsSeconds are the rolling windows reported by the topic stats.
+var TopicStatsWindowsSeconds = []uint{300, 3600, 86400}
+
+// TopicCounter holds the number of messages published to a topic in a one minute bucket.
+type TopicCounter struct {
+	TopicID              string    `db:"topic_id"`
+	Bucket               time.Time `db:"bucket"`
+	NumPublishedMessages uint      `db:"num_published_messages"`
+}
+
+// SubscriptionCounter holds the number of messages delivered and filtered out by a subscription in a one minute bucket.
+type SubscriptionCounter struct {
+	SubscriptionID       string    `db:"subscription_id"`
+	TopicID              string    `db:"topic_id"`
+	Bucket               time.Time `db:"bucket"`
+	NumDeliveredMessages uint      `db:"num_delivered_messages"`
+	NumFilteredMessages  uint      `db:"num_filtered_messages"`
+}
+
+// SubscriptionStats entity.
+type SubscriptionStats struct {
+	SubscriptionID       string `json:"subscription_id" db:"subscription_id"`
+	NumDeliveredMessages uint   `json:"num_delivered_messages" db:"num_delivered_messages"`
+	NumFilteredMessages  uint   `json:"num_filtered_messages" db:"num_filtered_messages"`
+}
+
+// TopicStatsWindow entity.
+type TopicStatsWindow struct {
+	WindowSeconds        uint                 `json:"window_seconds"`
+	NumPublishedMessages uint                 `json:"num_published_messages"`
+	Subscriptions        []*SubscriptionStats `json:"subscriptions"`
+}
+
+// TopicStats entity.
+type TopicStats struct {
+	TopicID string              `json:"topic_id"`
+	Windows []*TopicStatsWindow `json:"windows"`
+}
+
+// TopicStatsRepository is the repository interface for the TopicStats entity.
+type TopicStatsRepository interface {
+	Stats(ctx context.Context, topicID string, since time.Time) (*TopicStatsWindow, error)
+}
@@ -23,4 +23,64 @@ func TestTopic(t *testing.T) {
 		err := topic.Validate()
 		assert.Nil(t, err)
 	})
+
+	t.Run("Validation fail with invalid ingestion", func(t *testing.T) {
+		expectedErrorPayload := `{"ingestion":{"header_attributes":{"X-Event":"cannot be blank"},"signature_secret":"cannot be blank"}}`
+		topic := Topic{ID: "my-topic", Ingestion: &TopicIngestion{HeaderAttributes: map[string]string{"X-Event": ""}, SignatureHeader: pointString("X-Signature")}}
+		err := topic.Validate()
+		assert.NotNil(t, err)
+		errorPayload, err := json.Marshal(err)
+		assert.Nil(t, err)
+		assert.Equal(t, expectedErrorPayload, string(errorPayload))
+	})
+}
+
+func TestTopicIngestion(t *testing.T) {
+	// hex encoded HMAC-SHA256 of "my-payload" with "my-secret"
+	signature := "d978f3b056c5f81d6fb61dfd16d84823db0633148f19fdaff5ce461fccad6d44"
+	ingestion := TopicIngestion{
+		HeaderAttributes: map[string]string{"X-Event": "event"},
+		LabelHeader:      pointString("X-Event"),
+		SignatureHeader:  pointString("X-Signature"),
+		SignaturePrefix:  "sha256=",
+		SignatureSecret:  pointString("my-secret"),
+	}
+
+	t.Run("VerifySignature", func(t *testing.T) {
+		err := ingestion.VerifySignature([]byte("my-payload"), map[string]string{"x-signature": "sha256=" + signature})
+		assert.Nil(t, err)
+	})
+
+	t.Run("VerifySignature with invalid signature", func(t *testing.T) {
+		tests := []map[string]string{
+			{},
+			{"x-signature": signature},
+			{"x-signature": "sha256=invalid"},
+			{"x-signature": "sha256=" + signature[:len(signature)-2] + "00"},
+		}
+
+		for _, headers := range tests {
+			err := ingestion.VerifySignature([]byte("my-payload"), headers)
+			assert.ErrorIs(t, err, ErrTopicIngestionInvalidSignature)
+		}
+	})
+
+	t.Run("VerifySignature without signature", func(t *testing.T) {
+		var nilIngestion *TopicIngestion
+		err := nilIngestion.VerifySignature([]byte("my-payload"), map[string]string{})
+		assert.Nil(t, err)
+	})
+
+	t.Run("NewMessage", func(t *testing.T) {
+		message := ingestion.NewMessage([]byte("my-payload"), map[string]string{"x-event": "push", "x-other": "other"})
+		assert.Equal(t, "my-payload", message.Body)
+		assert.Equal(t, "push", *message.Label)
+		assert.Equal(t, map[string]string{"event": "push"}, message.Attributes)
+	})
+
+	t.Run("Redacted", func(t *testing.T) {
+		redacted := ingestion.Redacted()
+		assert.Equal(t, "********", *redacted.SignatureSecret)
+		assert.Equal(t, "my-secret", *ingestion.SignatureSecret)
+	})
 }
@@ -0,0 +1,106 @@
+package http
+
+import (
+	"io"
+	"log/slog"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/allisson/psqlqueue/domain"
+)
+
+const (
+	cloudEventHeaderPrefix   = "ce-"
+	messageFormatCloudEvents = "cloudevents"
+)
+
+// bindMessage binds the request to the message, accepting a plain message, a CloudEvent in structured mode
+// or a CloudEvent in binary mode.
+func bindMessage(c *gin.Context, message *domain.Message) *errorResponse {
+	malformedRequest := func(err error) *errorResponse {
+		slog.Error("malformed request", "error", err.Error())
+		er := errorResponses["malformed_request"]
+		return &er
+	}
+
+	var event *domain.CloudEvent
+	switch {
+	case c.ContentType() == domain.CloudEventContentType:
+		event = &domain.CloudEvent{}
+		if err := c.ShouldBindJSON(event); err != nil {
+			return malformedRequest(err)
+		}
+	case c.GetHeader(cloudEventHeaderPrefix+"specversion") != "":
+		payload, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			return malformedRequest(err)
+		}
+		event = newCloudEventFromHeaders(c)
+		event.SetData(payload)
+	default:
+		if err := c.ShouldBindJSON(message); err != nil {
+			return malformedRequest(err)
+		}
+		return nil
+	}
+
+	if err := event.Validate(); err != nil {
+		er := parseServiceError("cloudEvent", "Validate", err)
+		return &er
+	}
+
+	*message = *event.ToMessage()
+
+	return nil
+}
+
+func newCloudEventFromHeaders(c *gin.Context) *domain.CloudEvent {
+	event := &domain.CloudEvent{DataContentType: c.GetHeader("Content-Type")}
+
+	for key, values := range c.Request.Header {
+		key = strings.ToLower(key)
+		if !strings.HasPrefix(key, cloudEventHeaderPrefix) || len(values) == 0 {
+			continue
+		}
+
+		name := strings.TrimPrefix(key, cloudEventHeaderPrefix)
+		value, err := url.PathUnescape(values[0])
+		if err != nil {
+			value = values[0]
+		}
+
+		switch name {
+		case "specversion":
+			event.SpecVersion = value
+		case "id":
+			event.ID = value
+		case "source":
+			event.Source = value
+		case "type":
+			event.Type = value
+		case "subject":
+			event.Subject = value
+		case "time":
+			event.Time = value
+		case "dataschema":
+			event.DataSchema = value
+		default:
+			if event.Extensions == nil {
+				event.Extensions = make(map[string]string)
+			}
+			event.Extensions[name] = value
+		}
+	}
+
+	return event
+}
+
+func newCloudEventsFromMessages(messages []*domain.Message) []*domain.CloudEvent {
+	events := make([]*domain.CloudEvent, 0, len(messages))
+	for _, message := range messages {
+		events = append(events, domain.NewCloudEvent(message))
+	}
+	return events
+}
@@ -22,6 +22,16 @@ const (
 	topicNotFound
 	subscriptionAlreadyExists
 	subscriptionNotFound
+	topicNoMatchingSubscription
+	topicMaxHopsExceeded
+	subscriptionCycle
+	topicPublishNotFound
+	topicIngestionInvalidSignature
+	messageReplyTimeout
+	messageNotInFlight
+	messageDuplicated
+	messageTooManyRequests
+	subscriptionReplayTooLarge
 )
 
 var errorResponses = map[string]errorResponse{
@@ -75,6 +85,56 @@ var errorResponses = map[string]errorResponse{
 		Message:    "subscription not found",
 		StatusCode: http.StatusNotFound,
 	},
+	"topic_no_matching_subscription": {
+		Code:       topicNoMatchingSubscription,
+		Message:    "no subscription matched the message",
+		StatusCode: http.StatusUnprocessableEntity,
+	},
+	"topic_max_hops_exceeded": {
+		Code:       topicMaxHopsExceeded,
+		Message:    "topic max hops exceeded",
+		StatusCode: http.StatusUnprocessableEntity,
+	},
+	"subscription_cycle": {
+		Code:       subscriptionCycle,
+		Message:    "subscription creates a topic cycle",
+		StatusCode: http.StatusBadRequest,
+	},
+	"topic_publish_not_found": {
+		Code:       topicPublishNotFound,
+		Message:    "topic publish not found",
+		StatusCode: http.StatusNotFound,
+	},
+	"topic_ingestion_invalid_signature": {
+		Code:       topicIngestionInvalidSignature,
+		Message:    "invalid ingestion signature",
+		StatusCode: http.StatusUnauthorized,
+	},
+	"message_reply_timeout": {
+		Code:       messageReplyTimeout,
+		Message:    "message reply timeout",
+		StatusCode: http.StatusGatewayTimeout,
+	},
+	"message_not_in_flight": {
+		Code:       messageNotInFlight,
+		Message:    "message is not in flight",
+		StatusCode: http.StatusConflict,
+	},
+	"message_duplicated": {
+		Code:       messageDuplicated,
+		Message:    "message with the same unique key is pending",
+		StatusCode: http.StatusConflict,
+	},
+	"message_too_many_requests": {
+		Code:       messageTooManyRequests,
+		Message:    "too many requests waiting for a reply",
+		StatusCode: http.StatusTooManyRequests,
+	},
+	"subscription_replay_too_large": {
+		Code:       subscriptionReplayTooLarge,
+		Message:    "subscription replay too large",
+		StatusCode: http.StatusUnprocessableEntity,
+	},
 }
 
 type errorResponse struct {
@@ -98,6 +158,14 @@ func parseServiceError(serviceName, serviceMethod string, err error) errorRespon
 		return errorResponses["queue_not_found"]
 	case domain.ErrMessageNotFound:
 		return errorResponses["message_not_found"]
+	case domain.ErrMessageDuplicated:
+		return errorResponses["message_duplicated"]
+	case domain.ErrMessageNotInFlight:
+		return errorResponses["message_not_in_flight"]
+	case domain.ErrMessageReplyTimeout:
+		return errorResponses["message_reply_timeout"]
+	case domain.ErrMessageTooManyRequests:
+		return errorResponses["message_too_many_requests"]
 	case domain.ErrTopicAlreadyExists:
 		return errorResponses["topic_already_exists"]
 	case domain.ErrTopicNotFound:
@@ -106,6 +174,18 @@ func parseServiceError(serviceName, serviceMethod string, err error) errorRespon
 		return errorResponses["subscription_already_exists"]
 	case domain.ErrSubscriptionNotFound:
 		return errorResponses["subscription_not_found"]
+	case domain.ErrTopicNoMatchingSubscription:
+		return errorResponses["topic_no_matching_subscription"]
+	case domain.ErrTopicMaxHopsExceeded:
+		return errorResponses["topic_max_hops_exceeded"]
+	case domain.ErrSubscriptionCycle:
+		return errorResponses["subscription_cycle"]
+	case domain.ErrSubscriptionReplayTooLarge:
+		return errorResponses["subscription_replay_too_large"]
+	case domain.ErrTopicPublishNotFound:
+		return errorResponses["topic_publish_not_found"]
+	case domain.ErrTopicIngestionInvalidSignature:
+		return errorResponses["topic_ingestion_invalid_signature"]
 	default:
 		slog.Error(serviceName, "method", serviceMethod, "error", err.Error())
 		return errorResponses["internal_server_error"]
@@ -2,7 +2,9 @@ package http
 
 import (
 	"log/slog"
+	"math"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -10,39 +12,143 @@ import (
 	"github.com/allisson/psqlqueue/domain"
 )
 
+// The headers that identify the consumer that holds the lease of the delivered messages, Consumer-ID is the documented one
+// because proxies like nginx drop the headers with underscores by default.
+const (
+	consumerIDHeader         = "Consumer-ID"
+	consumerIDFallbackHeader = "consumer_id"
+)
+
 // nolint:unused
 type messageRequest struct {
-	Body       string            `json:"body" validate:"required"`
-	Label      *string           `json:"label" validate:"optional"`
-	Attributes map[string]string `json:"attributes" validate:"optional"`
+	Body          string            `json:"body" validate:"required"`
+	Label         *string           `json:"label" validate:"optional"`
+	Attributes    map[string]string `json:"attributes" validate:"optional"`
+	ReplyTo       *string           `json:"reply_to" example:"my-reply-queue" validate:"optional"`
+	CorrelationID *string           `json:"correlation_id" example:"01HK651Q52EZMPKBYZGVK0ZX8S" validate:"optional"`
+	UniqueKey     *string           `json:"unique_key" example:"reindex-customer-42" validate:"optional"`
+	CoalesceKey   *string           `json:"coalesce_key" example:"customer-42" validate:"optional"`
 } //@name MessageRequest
 
+// nolint:unused
+type messageErrorResponse struct {
+	Error           string    `json:"error" example:"connection refused"`
+	ErrorCode       *string   `json:"error_code" example:"ECONNREFUSED"`
+	DeliveryAttempt int       `json:"delivery_attempt" example:"1"`
+	CreatedAt       time.Time `json:"created_at" example:"2023-08-17T00:00:00Z"`
+} //@name MessageErrorResponse
+
 // nolint:unused
 type messageResponse struct {
-	ID               string            `json:"id" example:"7b98fe50-affd-4685-bd7d-3ae5e41493af"`
-	QueueID          string            `json:"queue_id" example:"my-new-queue"`
-	Label            *string           `json:"label"`
-	Body             string            `json:"body"`
-	Attributes       map[string]string `json:"attributes"`
-	DeliveryAttempts int               `json:"delivery_attempts" example:"1"`
-	CreatedAt        time.Time         `json:"created_at" db:"created_at" example:"2023-08-17T00:00:00Z"`
+	ID                   string                 `json:"id" example:"7b98fe50-affd-4685-bd7d-3ae5e41493af"`
+	QueueID              string                 `json:"queue_id" example:"my-new-queue"`
+	Label                *string                `json:"label"`
+	Body                 string                 `json:"body"`
+	Attributes           map[string]string      `json:"attributes"`
+	ReplyTo              *string                `json:"reply_to" example:"my-reply-queue"`
+	CorrelationID        *string                `json:"correlation_id" example:"01HK651Q52EZMPKBYZGVK0ZX8S"`
+	UniqueKey            *string                `json:"unique_key" example:"reindex-customer-42"`
+	CoalesceKey          *string                `json:"coalesce_key" example:"customer-42"`
+	DeliveryAttempts     int                    `json:"delivery_attempts" example:"1"`
+	Errors               []messageErrorResponse `json:"errors"`
+	PublishID            *string                `json:"publish_id" example:"01HK651Q52EZMPKBYZGVK0ZX8S"`
+	SourceTopicID        *string                `json:"source_topic_id" example:"my-new-topic"`
+	SourceSubscriptionID *string                `json:"source_subscription_id" example:"my-new-subscription"`
+	CreatedAt            time.Time              `json:"created_at" db:"created_at" example:"2023-08-17T00:00:00Z"`
 } //@name MessageResponse
 
 // nolint:unused
 type messageListRequest struct {
-	Label *string `form:"label" validate:"optional"`
-	Limit uint    `form:"limit" validate:"required"`
+	Label                    *string `form:"label" validate:"optional"`
+	Limit                    uint    `form:"limit" validate:"required"`
+	Format                   string  `form:"format" validate:"optional"`
+	VisibilityTimeoutSeconds uint    `form:"visibility_timeout_seconds" validate:"optional"`
+	AckMode                  string  `form:"ack_mode" validate:"optional"`
 } //@name MessageListRequest
 
+type messageListFromQueuesRequest struct {
+	QueueIDs []string `form:"queue_id" validate:"required"`
+	messageListRequest
+} //@name MessageListFromQueuesRequest
+
 // nolint:unused
 type messageListResponse struct {
 	Data  []*messageResponse `json:"data"`
 	Limit int                `json:"limit" example:"10"`
 } //@name MessageListResponse
 
+// nolint:unused
+type messageEventResponse struct {
+	ID         int       `json:"id" example:"1"`
+	MessageID  string    `json:"message_id" example:"01HK651Q52EZMPKBYZGVK0ZX8S"`
+	QueueID    string    `json:"queue_id" example:"my-new-queue"`
+	Type       string    `json:"type" example:"lease" enums:"enqueue,lease,ack,nack,expire,release,seek"`
+	ConsumerID *string   `json:"consumer_id" example:"worker-1"`
+	CreatedAt  time.Time `json:"created_at" example:"2023-08-17T00:00:00Z"`
+} //@name MessageEventResponse
+
+// nolint:unused
+type messageEventListResponse struct {
+	Data   []*messageEventResponse `json:"data"`
+	Offset int                     `json:"offset" example:"0"`
+	Limit  int                     `json:"limit" example:"10"`
+} //@name MessageEventListResponse
+
+// nolint:unused
+type messageLeaseResponse struct {
+	ID               string     `json:"id" example:"01HK651Q52EZMPKBYZGVK0ZX8S"`
+	QueueID          string     `json:"queue_id" example:"my-new-queue"`
+	Label            *string    `json:"label"`
+	ConsumerID       *string    `json:"consumer_id" example:"worker-1"`
+	DeliveryAttempts int        `json:"delivery_attempts" example:"1"`
+	LeasedAt         *time.Time `json:"leased_at" example:"2023-08-17T00:00:00Z"`
+	Deadline         time.Time  `json:"deadline" example:"2023-08-17T00:00:30Z"`
+} //@name MessageLeaseResponse
+
+// nolint:unused
+type messageLeaseListResponse struct {
+	Data   []*messageLeaseResponse `json:"data"`
+	Offset int                     `json:"offset" example:"0"`
+	Limit  int                     `json:"limit" example:"10"`
+} //@name MessageLeaseListResponse
+
+// nolint:unused
+type messageRequestRequest struct {
+	TimeoutSeconds uint `form:"timeout_seconds" validate:"optional"`
+} //@name MessageRequestRequest
+
+// nolint:unused
+type messageAckRequest struct {
+	Status string  `json:"status" example:"succeeded" enums:"succeeded,failed" validate:"required"`
+	Body   *string `json:"body" example:"{\"rows\": 10}" validate:"optional"`
+} //@name MessageAckRequest
+
+// nolint:unused
+type messageProgressRequest struct {
+	Percent uint    `json:"percent" example:"50" validate:"required"`
+	Note    *string `json:"note" example:"processing page 5 of 10" validate:"optional"`
+} //@name MessageProgressRequest
+
+// nolint:unused
+type messageJobResponse struct {
+	ID               string                 `json:"id" example:"01HK651Q52EZMPKBYZGVK0ZX8T"`
+	QueueID          string                 `json:"queue_id" example:"my-new-queue"`
+	Status           string                 `json:"status" example:"acked" enums:"scheduled,available,in_flight,acked,expired"`
+	DeliveryAttempts int                    `json:"delivery_attempts" example:"1"`
+	Errors           []messageErrorResponse `json:"errors"`
+	ProgressPercent  *int                   `json:"progress_percent" example:"100"`
+	ProgressNote     *string                `json:"progress_note" example:"processing page 10 of 10"`
+	ResultStatus     *string                `json:"result_status" example:"succeeded" enums:"succeeded,failed"`
+	Result           *string                `json:"result" example:"{\"rows\": 10}"`
+	CreatedAt        time.Time              `json:"created_at" example:"2023-08-17T00:00:00Z"`
+	UpdatedAt        time.Time              `json:"updated_at" example:"2023-08-17T00:00:00Z"`
+} //@name MessageJobResponse
+
 // nolint:unused
 type messageNackRequest struct {
-	VisibilityTimeoutSeconds uint `form:"visibility_timeout_seconds" validate:"required"`
+	VisibilityTimeoutSeconds uint    `json:"visibility_timeout_seconds" form:"visibility_timeout_seconds" validate:"required"`
+	Error                    *string `json:"error" form:"error" example:"connection refused" validate:"optional"`
+	ErrorCode                *string `json:"error_code" form:"error_code" example:"ECONNREFUSED" validate:"optional"`
 } //@name MessageNackRequest
 
 // Message exposes a REST API for domain.MessageService.
@@ -55,21 +161,20 @@ type MessageHandler struct {
 //
 //	@Summary	Add a message
 //	@Tags		messages
-//	@Accept		json
+//	@Accept		json,application/cloudevents+json
 //	@Produce	json
 //	@Param		queue_id	path	string			true	"Queue id"
 //	@Param		request		body	messageRequest	true	"Add a message"
 //	@Success	204			"No Content"
 //	@Failure	400			{object}	errorResponse
+//	@Failure	409			{object}	errorResponse
 //	@Failure	500			{object}	errorResponse
 //	@Router		/queue/{queue_id}/messages [post]
 func (m *MessageHandler) Create(c *gin.Context) {
 	message := domain.Message{}
 
-	if err := c.ShouldBindJSON(&message); err != nil {
-		slog.Error("malformed request", "error", err.Error())
-		er := errorResponses["malformed_request"]
-		c.JSON(er.StatusCode, &er)
+	if er := bindMessage(c, &message); er != nil {
+		c.JSON(er.StatusCode, er)
 		return
 	}
 
@@ -93,7 +198,13 @@ func (m *MessageHandler) Create(c *gin.Context) {
 //	@Param		queue_id	path		string	true	"Queue id"
 //	@Param		label		path		string	false	"Filter by label"
 //	@Param		limit		query		int		false	"The limit indicates the maximum number of items to return"
+//	@Param		format		query		string	false	"Render the messages as CloudEvents"	Enums(cloudevents)
+//	@Param		visibility_timeout_seconds	query	int	false	"The lease of the messages, bounded by the max visibility timeout of the queue (default ack_deadline_seconds)"
+//	@Param		ack_mode	query		string	false	"Use auto to ack the messages on the delivery, the messages are delivered at most once (default manual)"	Enums(manual, auto)
+//	@Param		Consumer-ID	header		string	false	"Identify the consumer that holds the lease of the messages"
 //	@Success	200			{object}	messageListResponse
+//	@Header		200			{integer}	Retry-After	"Seconds until the queue delivery rate limit allows new deliveries"
+//	@Failure	400			{object}	errorResponse
 //	@Failure	404			{object}	errorResponse
 //	@Failure	500			{object}	errorResponse
 //	@Router		/queues/{queue_id}/messages [get]
@@ -107,34 +218,210 @@ func (m *MessageHandler) List(c *gin.Context) {
 
 	request.Limit = min(request.Limit, m.cfg.QueueMaxNumberOfMessages)
 
-	messages, err := m.messageService.List(c.Request.Context(), queueID, request.Label, request.Limit)
+	messages, retryAfter, err := m.messageService.List(c.Request.Context(), queueID, request.Label, request.Limit, request.VisibilityTimeoutSeconds, request.AckMode, consumerIDFromHeader(c))
 	if err != nil {
 		er := parseServiceError("messageService", "List", err)
 		c.JSON(er.StatusCode, &er)
 		return
 	}
 
+	if retryAfter > 0 {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	}
+
+	response := listResponse{Data: messages, Offset: 0, Limit: request.Limit}
+	if request.Format == messageFormatCloudEvents {
+		response.Data = newCloudEventsFromMessages(messages)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// List messages from multiple queues.
+//
+//	@Summary	List messages from multiple queues
+//	@Tags		messages
+//	@Accept		json
+//	@Produce	json
+//	@Param		queue_id	query		[]string	true	"Queue ids, polled in the given order"	collectionFormat(multi)
+//	@Param		label		query		string		false	"Filter by label"
+//	@Param		limit		query		int			false	"The limit indicates the maximum number of items to return"
+//	@Param		format		query		string		false	"Render the messages as CloudEvents"	Enums(cloudevents)
+//	@Param		visibility_timeout_seconds	query	int	false	"The lease of the messages, bounded by the max visibility timeout of each queue (default ack_deadline_seconds)"
+//	@Param		ack_mode	query		string	false	"Use auto to ack the messages on the delivery, the messages are delivered at most once (default manual)"	Enums(manual, auto)
+//	@Param		Consumer-ID	header		string	false	"Identify the consumer that holds the lease of the messages"
+//	@Success	200			{object}	messageListResponse
+//	@Header		200			{integer}	Retry-After	"Seconds until the queue delivery rate limit allows new deliveries"
+//	@Failure	400			{object}	errorResponse
+//	@Failure	404			{object}	errorResponse
+//	@Failure	500			{object}	errorResponse
+//	@Router		/messages [get]
+func (m *MessageHandler) ListFromQueues(c *gin.Context) {
+	request := messageListFromQueuesRequest{messageListRequest: messageListRequest{Limit: 10}}
+	if err := c.ShouldBindQuery(&request); err != nil {
+		slog.Warn("message list from queues request error", "error", err)
+	}
+
+	request.Limit = min(request.Limit, m.cfg.QueueMaxNumberOfMessages)
+
+	messages, retryAfter, err := m.messageService.ListFromQueues(c.Request.Context(), request.QueueIDs, request.Label, request.Limit, request.VisibilityTimeoutSeconds, request.AckMode, consumerIDFromHeader(c))
+	if err != nil {
+		er := parseServiceError("messageService", "ListFromQueues", err)
+		c.JSON(er.StatusCode, &er)
+		return
+	}
+
+	if retryAfter > 0 {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	}
+
 	response := listResponse{Data: messages, Offset: 0, Limit: request.Limit}
+	if request.Format == messageFormatCloudEvents {
+		response.Data = newCloudEventsFromMessages(messages)
+	}
 
 	c.JSON(http.StatusOK, response)
 }
 
+// Request a reply.
+//
+//	@Summary	Add a message and wait for the reply with the same correlation id
+//	@Tags		messages
+//	@Accept		json,application/cloudevents+json
+//	@Produce	json
+//	@Param		queue_id		path		string			true	"Queue id"
+//	@Param		timeout_seconds	query		int				false	"The maximum time to wait for the reply"
+//	@Param		request			body		messageRequest	true	"Add a message"
+//	@Success	200				{object}	messageResponse
+//	@Failure	400				{object}	errorResponse
+//	@Failure	404				{object}	errorResponse
+//	@Failure	429				{object}	errorResponse
+//	@Failure	500				{object}	errorResponse
+//	@Failure	504				{object}	errorResponse
+//	@Router		/queues/{queue_id}/request [post]
+func (m *MessageHandler) Request(c *gin.Context) {
+	message := domain.Message{}
+
+	if er := bindMessage(c, &message); er != nil {
+		c.JSON(er.StatusCode, er)
+		return
+	}
+
+	message.QueueID = c.Param("queue_id")
+
+	request := messageRequestRequest{TimeoutSeconds: m.cfg.MessageMaxRequestTimeoutSeconds}
+	if err := c.ShouldBindQuery(&request); err != nil {
+		slog.Warn("message request request error", "error", err)
+	}
+
+	// a zero timeout would expire before the reply could be received, it falls back to the default.
+	if request.TimeoutSeconds == 0 {
+		request.TimeoutSeconds = m.cfg.MessageMaxRequestTimeoutSeconds
+	}
+	request.TimeoutSeconds = min(request.TimeoutSeconds, m.cfg.MessageMaxRequestTimeoutSeconds)
+	timeout := time.Duration(request.TimeoutSeconds) * time.Second
+
+	reply, err := m.messageService.Request(c.Request.Context(), &message, timeout)
+	if err != nil {
+		er := parseServiceError("messageService", "Request", err)
+		c.JSON(er.StatusCode, &er)
+		return
+	}
+
+	c.JSON(http.StatusOK, reply)
+}
+
+// Get the job of a message.
+//
+//	@Summary	Show the job status, progress and result of a message
+//	@Tags		messages
+//	@Accept		json
+//	@Produce	json
+//	@Param		queue_id	path		string	true	"Queue id"
+//	@Param		message_id	path		string	true	"Message id"
+//	@Success	200			{object}	messageJobResponse
+//	@Failure	404			{object}	errorResponse
+//	@Failure	500			{object}	errorResponse
+//	@Router		/queues/{queue_id}/messages/{message_id} [get]
+func (m *MessageHandler) GetJob(c *gin.Context) {
+	queueID := c.Param("queue_id")
+	messageID := c.Param("message_id")
+
+	job, err := m.messageService.GetJob(c.Request.Context(), queueID, messageID)
+	if err != nil {
+		er := parseServiceError("messageService", "GetJob", err)
+		c.JSON(er.StatusCode, &er)
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// Update the progress of a message.
+//
+//	@Summary	Update the job progress of an in flight message
+//	@Tags		messages
+//	@Accept		json
+//	@Produce	json
+//	@Param		queue_id	path	string					true	"Queue id"
+//	@Param		message_id	path	string					true	"Message id"
+//	@Param		request		body	messageProgressRequest	true	"Update the progress"
+//	@Success	204			"No Content"
+//	@Failure	400			{object}	errorResponse
+//	@Failure	404			{object}	errorResponse
+//	@Failure	409			{object}	errorResponse
+//	@Failure	500			{object}	errorResponse
+//	@Router		/queues/{queue_id}/messages/{message_id}/progress [put]
+func (m *MessageHandler) Progress(c *gin.Context) {
+	queueID := c.Param("queue_id")
+	messageID := c.Param("message_id")
+	progress := domain.MessageProgress{}
+
+	if err := c.ShouldBindJSON(&progress); err != nil {
+		slog.Error("malformed request", "error", err.Error())
+		er := errorResponses["malformed_request"]
+		c.JSON(er.StatusCode, &er)
+		return
+	}
+
+	if err := m.messageService.Progress(c.Request.Context(), queueID, messageID, &progress); err != nil {
+		er := parseServiceError("messageService", "Progress", err)
+		c.JSON(er.StatusCode, &er)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
 // Ack a message.
 //
 //	@Summary	Ack a message
 //	@Tags		messages
 //	@Accept		json
 //	@Produce	json
-//	@Param		queue_id	path	string	true	"Queue id"
-//	@Param		message_id	path	string	true	"Message id"
+//	@Param		queue_id	path	string				true	"Queue id"
+//	@Param		message_id	path	string				true	"Message id"
+//	@Param		request		body	messageAckRequest	false	"Store the job result"
 //	@Success	204			"No Content"
+//	@Failure	400			{object}	errorResponse
 //	@Failure	404			{object}	errorResponse
 //	@Failure	500			{object}	errorResponse
 //	@Router		/queues/{queue_id}/messages/{message_id}/ack [put]
 func (m *MessageHandler) Ack(c *gin.Context) {
 	messageID := c.Param("message_id")
 
-	if err := m.messageService.Ack(c.Request.Context(), messageID); err != nil {
+	var result *domain.MessageResult
+	if c.Request.ContentLength != 0 {
+		result = &domain.MessageResult{}
+		if err := c.ShouldBindJSON(result); err != nil {
+			slog.Error("malformed request", "error", err.Error())
+			er := errorResponses["malformed_request"]
+			c.JSON(er.StatusCode, &er)
+			return
+		}
+	}
+
+	if err := m.messageService.Ack(c.Request.Context(), messageID, result); err != nil {
 		er := parseServiceError("messageService", "Ack", err)
 		c.JSON(er.StatusCode, &er)
 		return
@@ -151,9 +438,11 @@ func (m *MessageHandler) Ack(c *gin.Context) {
 //	@Produce	json
 //	@Param		queue_id	path	string				true	"Queue id"
 //	@Param		message_id	path	string				true	"Message id"
-//	@Param		request		body	messageNackRequest	true	"Nack a message"
+//	@Param		request		body	messageNackRequest	true	"Nack a message, optionally reporting the error"
 //	@Success	204			"No Content"
+//	@Failure	400			{object}	errorResponse
 //	@Failure	404			{object}	errorResponse
+//	@Failure	409			{object}	errorResponse
 //	@Failure	500			{object}	errorResponse
 //	@Router		/queues/{queue_id}/messages/{message_id}/nack [put]
 func (m *MessageHandler) Nack(c *gin.Context) {
@@ -163,8 +452,24 @@ func (m *MessageHandler) Nack(c *gin.Context) {
 	if err := c.ShouldBindQuery(&request); err != nil {
 		slog.Warn("message nack request error", "error", err)
 	}
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			slog.Error("malformed request", "error", err.Error())
+			er := errorResponses["malformed_request"]
+			c.JSON(er.StatusCode, &er)
+			return
+		}
+	}
+
+	var messageError *domain.MessageError
+	if request.Error != nil || request.ErrorCode != nil {
+		messageError = &domain.MessageError{Code: request.ErrorCode}
+		if request.Error != nil {
+			messageError.Message = *request.Error
+		}
+	}
 
-	if err := m.messageService.Nack(c.Request.Context(), messageID, request.VisibilityTimeoutSeconds); err != nil {
+	if err := m.messageService.Nack(c.Request.Context(), messageID, request.VisibilityTimeoutSeconds, messageError); err != nil {
 		er := parseServiceError("messageService", "Ack", err)
 		c.JSON(er.StatusCode, &er)
 		return
@@ -173,6 +478,101 @@ func (m *MessageHandler) Nack(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
+// List the events of a message.
+//
+//	@Summary	List the delivery log events of a message
+//	@Tags		messages
+//	@Accept		json
+//	@Produce	json
+//	@Param		queue_id	path		string	true	"Queue id"
+//	@Param		message_id	path		string	true	"Message id"
+//	@Param		limit		query		int		false	"The limit indicates the maximum number of items to return"
+//	@Param		offset		query		int		false	"The offset indicates the starting position of the query in relation to the complete set of unpaginated items"
+//	@Success	200			{object}	messageEventListResponse
+//	@Failure	500			{object}	errorResponse
+//	@Router		/queues/{queue_id}/messages/{message_id}/events [get]
+func (m *MessageHandler) ListEvents(c *gin.Context) {
+	queueID := c.Param("queue_id")
+	messageID := c.Param("message_id")
+	request := newListRequestFromGIN(c)
+
+	events, err := m.messageService.ListEvents(c.Request.Context(), queueID, messageID, request.Offset, request.Limit)
+	if err != nil {
+		er := parseServiceError("messageService", "ListEvents", err)
+		c.JSON(er.StatusCode, &er)
+		return
+	}
+
+	response := listResponse{Data: events, Offset: request.Offset, Limit: request.Limit}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// List the in flight messages of a queue.
+//
+//	@Summary	List the leased messages of a queue
+//	@Tags		messages
+//	@Accept		json
+//	@Produce	json
+//	@Param		queue_id	path		string	true	"Queue id"
+//	@Param		limit		query		int		false	"The limit indicates the maximum number of items to return"
+//	@Param		offset		query		int		false	"The offset indicates the starting position of the query in relation to the complete set of unpaginated items"
+//	@Success	200			{object}	messageLeaseListResponse
+//	@Failure	404			{object}	errorResponse
+//	@Failure	500			{object}	errorResponse
+//	@Router		/queues/{queue_id}/in-flight [get]
+func (m *MessageHandler) ListInFlight(c *gin.Context) {
+	queueID := c.Param("queue_id")
+	request := newListRequestFromGIN(c)
+
+	leases, err := m.messageService.ListInFlight(c.Request.Context(), queueID, request.Offset, request.Limit)
+	if err != nil {
+		er := parseServiceError("messageService", "ListInFlight", err)
+		c.JSON(er.StatusCode, &er)
+		return
+	}
+
+	response := listResponse{Data: leases, Offset: request.Offset, Limit: request.Limit}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Release the leases of a consumer.
+//
+//	@Summary	Release the leases held by a consumer, the messages are available for delivery right away
+//	@Tags		messages
+//	@Accept		json
+//	@Produce	json
+//	@Param		queue_id	path	string	true	"Queue id"
+//	@Param		consumer_id	path	string	true	"Consumer id"
+//	@Success	204			"No Content"
+//	@Failure	404			{object}	errorResponse
+//	@Failure	500			{object}	errorResponse
+//	@Router		/queues/{queue_id}/consumers/{consumer_id}/release [put]
+func (m *MessageHandler) ReleaseConsumer(c *gin.Context) {
+	queueID := c.Param("queue_id")
+	consumerID := c.Param("consumer_id")
+
+	if err := m.messageService.ReleaseConsumer(c.Request.Context(), queueID, consumerID); err != nil {
+		er := parseServiceError("messageService", "ReleaseConsumer", err)
+		c.JSON(er.StatusCode, &er)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func consumerIDFromHeader(c *gin.Context) *string {
+	consumerID := c.GetHeader(consumerIDHeader)
+	if consumerID == "" {
+		consumerID = c.GetHeader(consumerIDFallbackHeader)
+	}
+	if consumerID == "" {
+		return nil
+	}
+	return &consumerID
+}
+
 // NewMessageHandler returns a new MessageHandler.
 func NewMessageHandler(messageService domain.MessageService) *MessageHandler {
 	return &MessageHandler{
@@ -6,13 +6,19 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/jellydator/validation"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
 	"github.com/allisson/psqlqueue/domain"
 )
 
+func pointString(x string) *string {
+	return &x
+}
+
 func nilString() *string {
 	var s *string = nil
 	return s
@@ -59,27 +65,390 @@ func TestMessageHandler(t *testing.T) {
 		assert.Equal(t, http.StatusNoContent, reqRec.Code)
 	})
 
+	t.Run("Create with duplicated unique key", func(t *testing.T) {
+		expectedPayload := `{"code":18,"message":"message with the same unique key is pending"}`
+		message := domain.Message{QueueID: "my-queue", Body: `{"message": true}`, UniqueKey: pointString("reindex-customer-42")}
+		jsonMessage, _ := json.Marshal(&message)
+		tc := makeTestContext(t)
+		reqRec := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/v1/queues/my-queue/messages", bytes.NewBuffer(jsonMessage))
+
+		tc.messageService.On("Create", mock.Anything, &message).Return(domain.ErrMessageDuplicated)
+		tc.router.ServeHTTP(reqRec, req)
+
+		assert.Equal(t, http.StatusConflict, reqRec.Code)
+		assert.Equal(t, expectedPayload, reqRec.Body.String())
+	})
+
+	t.Run("Create with structured CloudEvent", func(t *testing.T) {
+		payload := `{"specversion":"1.0","id":"A234","source":"/orders","type":"order.created","data":{"id":1},"tenant":"acme"}`
+		message := domain.Message{
+			QueueID: "my-queue",
+			Label:   pointString("order.created"),
+			Body:    `{"id":1}`,
+			Attributes: map[string]string{
+				"tenant":         "acme",
+				"ce_id":          "A234",
+				"ce_source":      "/orders",
+				"ce_type":        "order.created",
+				"ce_specversion": "1.0",
+			},
+		}
+		tc := makeTestContext(t)
+		reqRec := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/v1/queues/my-queue/messages", bytes.NewBuffer([]byte(payload)))
+		req.Header.Set("Content-Type", "application/cloudevents+json; charset=utf-8")
+
+		tc.messageService.On("Create", mock.Anything, &message).Return(nil)
+		tc.router.ServeHTTP(reqRec, req)
+
+		assert.Equal(t, http.StatusNoContent, reqRec.Code)
+	})
+
+	t.Run("Create with binary CloudEvent", func(t *testing.T) {
+		message := domain.Message{
+			QueueID: "my-queue",
+			Label:   pointString("order.created"),
+			Body:    "order 1 created",
+			Attributes: map[string]string{
+				"tenant":             "acme",
+				"ce_id":              "A234",
+				"ce_source":          "/orders",
+				"ce_type":            "order.created",
+				"ce_specversion":     "1.0",
+				"ce_datacontenttype": "text/plain",
+			},
+		}
+		tc := makeTestContext(t)
+		reqRec := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/v1/queues/my-queue/messages", bytes.NewBuffer([]byte("order 1 created")))
+		req.Header.Set("Content-Type", "text/plain")
+		req.Header.Set("ce-specversion", "1.0")
+		req.Header.Set("ce-id", "A234")
+		req.Header.Set("ce-source", "%2Forders")
+		req.Header.Set("ce-type", "order.created")
+		req.Header.Set("ce-tenant", "acme")
+
+		tc.messageService.On("Create", mock.Anything, &message).Return(nil)
+		tc.router.ServeHTTP(reqRec, req)
+
+		assert.Equal(t, http.StatusNoContent, reqRec.Code)
+	})
+
+	t.Run("Create with CloudEvent validation error", func(t *testing.T) {
+		expectedPayload := `{"code":3,"message":"request validation failed","details":"source: cannot be blank; type: cannot be blank."}`
+		tc := makeTestContext(t)
+		reqRec := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/v1/queues/my-queue/messages", bytes.NewBuffer([]byte(`{"specversion":"1.0","id":"A234","data":"hello"}`)))
+		req.Header.Set("Content-Type", "application/cloudevents+json")
+
+		tc.router.ServeHTTP(reqRec, req)
+
+		assert.Equal(t, http.StatusBadRequest, reqRec.Code)
+		assert.Equal(t, expectedPayload, reqRec.Body.String())
+	})
+
 	t.Run("List", func(t *testing.T) {
-		expectedPayload := `{"data":[{"id":"","queue_id":"my-queue","label":null,"body":"{\"message\": true}","attributes":null,"delivery_attempts":0,"created_at":"0001-01-01T00:00:00Z"},{"id":"","queue_id":"my-queue","label":null,"body":"{\"message\": true}","attributes":null,"delivery_attempts":0,"created_at":"0001-01-01T00:00:00Z"}],"limit":10}`
+		expectedPayload := `{"data":[{"id":"","queue_id":"my-queue","label":null,"body":"{\"message\": true}","attributes":null,"reply_to":null,"correlation_id":null,"unique_key":null,"coalesce_key":null,"delivery_attempts":0,"errors":null,"publish_id":null,"source_topic_id":null,"source_subscription_id":null,"created_at":"0001-01-01T00:00:00Z"},{"id":"","queue_id":"my-queue","label":null,"body":"{\"message\": true}","attributes":null,"reply_to":null,"correlation_id":null,"unique_key":null,"coalesce_key":null,"delivery_attempts":0,"errors":null,"publish_id":null,"source_topic_id":null,"source_subscription_id":null,"created_at":"0001-01-01T00:00:00Z"}],"limit":10}`
 		message1 := domain.Message{QueueID: "my-queue", Body: `{"message": true}`}
 		message2 := domain.Message{QueueID: "my-queue", Body: `{"message": true}`}
 		tc := makeTestContext(t)
 		reqRec := httptest.NewRecorder()
 		req, _ := http.NewRequest("GET", "/v1/queues/my-queue/messages", nil)
 
-		tc.messageService.On("List", mock.Anything, "my-queue", nilString(), uint(10)).Return([]*domain.Message{&message1, &message2}, nil)
+		tc.messageService.On("List", mock.Anything, "my-queue", nilString(), uint(10), uint(0), "", nilString()).Return([]*domain.Message{&message1, &message2}, time.Duration(0), nil)
+		tc.router.ServeHTTP(reqRec, req)
+
+		assert.Equal(t, http.StatusOK, reqRec.Code)
+		assert.Equal(t, expectedPayload, reqRec.Body.String())
+	})
+
+	t.Run("List with visibility timeout", func(t *testing.T) {
+		expectedPayload := `{"data":[],"limit":10}`
+		tc := makeTestContext(t)
+		reqRec := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/v1/queues/my-queue/messages?visibility_timeout_seconds=300", nil)
+
+		tc.messageService.On("List", mock.Anything, "my-queue", nilString(), uint(10), uint(300), "", nilString()).Return([]*domain.Message{}, time.Duration(0), nil)
+		tc.router.ServeHTTP(reqRec, req)
+
+		assert.Equal(t, http.StatusOK, reqRec.Code)
+		assert.Equal(t, expectedPayload, reqRec.Body.String())
+	})
+
+	t.Run("List with auto ack", func(t *testing.T) {
+		expectedPayload := `{"data":[],"limit":10}`
+		tc := makeTestContext(t)
+		reqRec := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/v1/queues/my-queue/messages?ack_mode=auto", nil)
+
+		tc.messageService.On("List", mock.Anything, "my-queue", nilString(), uint(10), uint(0), domain.MessageAckModeAuto, nilString()).Return([]*domain.Message{}, time.Duration(0), nil)
+		tc.router.ServeHTTP(reqRec, req)
+
+		assert.Equal(t, http.StatusOK, reqRec.Code)
+		assert.Equal(t, expectedPayload, reqRec.Body.String())
+	})
+
+	t.Run("List with consumer id", func(t *testing.T) {
+		expectedPayload := `{"data":[],"limit":10}`
+		tc := makeTestContext(t)
+		reqRec := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/v1/queues/my-queue/messages", nil)
+		req.Header.Set("Consumer-ID", "worker-1")
+
+		tc.messageService.On("List", mock.Anything, "my-queue", nilString(), uint(10), uint(0), "", pointString("worker-1")).Return([]*domain.Message{}, time.Duration(0), nil)
+		tc.router.ServeHTTP(reqRec, req)
+
+		assert.Equal(t, http.StatusOK, reqRec.Code)
+		assert.Equal(t, expectedPayload, reqRec.Body.String())
+	})
+
+	t.Run("List with consumer_id header", func(t *testing.T) {
+		expectedPayload := `{"data":[],"limit":10}`
+		tc := makeTestContext(t)
+		reqRec := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/v1/queues/my-queue/messages", nil)
+		req.Header.Set("consumer_id", "worker-1")
+
+		tc.messageService.On("List", mock.Anything, "my-queue", nilString(), uint(10), uint(0), "", pointString("worker-1")).Return([]*domain.Message{}, time.Duration(0), nil)
+		tc.router.ServeHTTP(reqRec, req)
+
+		assert.Equal(t, http.StatusOK, reqRec.Code)
+		assert.Equal(t, expectedPayload, reqRec.Body.String())
+	})
+
+	t.Run("List with rate limit exhausted", func(t *testing.T) {
+		expectedPayload := `{"data":[],"limit":10}`
+		tc := makeTestContext(t)
+		reqRec := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/v1/queues/my-queue/messages", nil)
+
+		tc.messageService.On("List", mock.Anything, "my-queue", nilString(), uint(10), uint(0), "", nilString()).Return([]*domain.Message{}, 1500*time.Millisecond, nil)
 		tc.router.ServeHTTP(reqRec, req)
 
 		assert.Equal(t, http.StatusOK, reqRec.Code)
+		assert.Equal(t, "2", reqRec.Header().Get("Retry-After"))
 		assert.Equal(t, expectedPayload, reqRec.Body.String())
 	})
 
+	t.Run("ListFromQueues", func(t *testing.T) {
+		expectedPayload := `{"data":[{"id":"","queue_id":"queue-a","label":null,"body":"{\"message\": true}","attributes":null,"reply_to":null,"correlation_id":null,"unique_key":null,"coalesce_key":null,"delivery_attempts":0,"errors":null,"publish_id":null,"source_topic_id":null,"source_subscription_id":null,"created_at":"0001-01-01T00:00:00Z"},{"id":"","queue_id":"queue-b","label":null,"body":"{\"message\": true}","attributes":null,"reply_to":null,"correlation_id":null,"unique_key":null,"coalesce_key":null,"delivery_attempts":0,"errors":null,"publish_id":null,"source_topic_id":null,"source_subscription_id":null,"created_at":"0001-01-01T00:00:00Z"}],"limit":5}`
+		message1 := domain.Message{QueueID: "queue-a", Body: `{"message": true}`}
+		message2 := domain.Message{QueueID: "queue-b", Body: `{"message": true}`}
+		tc := makeTestContext(t)
+		reqRec := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/v1/messages?queue_id=queue-a&queue_id=queue-b&limit=5", nil)
+
+		tc.messageService.On("ListFromQueues", mock.Anything, []string{"queue-a", "queue-b"}, nilString(), uint(5), uint(0), "", nilString()).Return([]*domain.Message{&message1, &message2}, time.Duration(0), nil)
+		tc.router.ServeHTTP(reqRec, req)
+
+		assert.Equal(t, http.StatusOK, reqRec.Code)
+		assert.Equal(t, expectedPayload, reqRec.Body.String())
+	})
+
+	t.Run("ListFromQueues without queues", func(t *testing.T) {
+		expectedPayload := `{"code":3,"message":"request validation failed","details":"queue_id: cannot be blank."}`
+		tc := makeTestContext(t)
+		reqRec := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/v1/messages", nil)
+
+		tc.messageService.On("ListFromQueues", mock.Anything, []string(nil), nilString(), uint(10), uint(0), "", nilString()).Return(nil, time.Duration(0), validation.Errors{"queue_id": validation.ErrRequired})
+		tc.router.ServeHTTP(reqRec, req)
+
+		assert.Equal(t, http.StatusBadRequest, reqRec.Code)
+		assert.Equal(t, expectedPayload, reqRec.Body.String())
+	})
+
+	t.Run("List as CloudEvents", func(t *testing.T) {
+		expectedPayload := `{"data":[{"data":{"id":1},"deliveryattempts":"1","id":"A234","messageid":"my-message","source":"/orders","specversion":"1.0","tenant":"acme","time":"0001-01-01T00:00:00Z","type":"order.created"}],"limit":10}`
+		message := domain.Message{
+			ID:               "my-message",
+			QueueID:          "my-queue",
+			Label:            pointString("order.created"),
+			Body:             `{"id":1}`,
+			DeliveryAttempts: 1,
+			Attributes: map[string]string{
+				"tenant":    "acme",
+				"ce_id":     "A234",
+				"ce_source": "/orders",
+			},
+		}
+		tc := makeTestContext(t)
+		reqRec := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/v1/queues/my-queue/messages?format=cloudevents", nil)
+
+		tc.messageService.On("List", mock.Anything, "my-queue", nilString(), uint(10), uint(0), "", nilString()).Return([]*domain.Message{&message}, time.Duration(0), nil)
+		tc.router.ServeHTTP(reqRec, req)
+
+		assert.Equal(t, http.StatusOK, reqRec.Code)
+		assert.Equal(t, expectedPayload, reqRec.Body.String())
+	})
+
+	t.Run("Request", func(t *testing.T) {
+		expectedPayload := `{"id":"my-reply","queue_id":"my-reply-queue","label":null,"body":"{\"reply\": true}","attributes":null,"reply_to":null,"correlation_id":"my-correlation-id","unique_key":null,"coalesce_key":null,"delivery_attempts":1,"errors":null,"publish_id":null,"source_topic_id":null,"source_subscription_id":null,"created_at":"0001-01-01T00:00:00Z"}`
+		message := domain.Message{QueueID: "my-queue", Body: `{"message": true}`, ReplyTo: pointString("my-reply-queue"), CorrelationID: pointString("my-correlation-id")}
+		reply := domain.Message{ID: "my-reply", QueueID: "my-reply-queue", Body: `{"reply": true}`, CorrelationID: pointString("my-correlation-id"), DeliveryAttempts: 1}
+		jsonMessage, _ := json.Marshal(&message)
+		tc := makeTestContext(t)
+		reqRec := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/v1/queues/my-queue/request?timeout_seconds=5", bytes.NewBuffer(jsonMessage))
+
+		tc.messageService.On("Request", mock.Anything, &message, 5*time.Second).Return(&reply, nil)
+		tc.router.ServeHTTP(reqRec, req)
+
+		assert.Equal(t, http.StatusOK, reqRec.Code)
+		assert.Equal(t, expectedPayload, reqRec.Body.String())
+	})
+
+	t.Run("Request with timeout", func(t *testing.T) {
+		expectedPayload := `{"code":16,"message":"message reply timeout"}`
+		message := domain.Message{QueueID: "my-queue", Body: `{"message": true}`}
+		jsonMessage, _ := json.Marshal(&message)
+		tc := makeTestContext(t)
+		reqRec := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/v1/queues/my-queue/request?timeout_seconds=3600", bytes.NewBuffer(jsonMessage))
+
+		tc.messageService.On("Request", mock.Anything, &message, 30*time.Second).Return(nil, domain.ErrMessageReplyTimeout)
+		tc.router.ServeHTTP(reqRec, req)
+
+		assert.Equal(t, http.StatusGatewayTimeout, reqRec.Code)
+		assert.Equal(t, expectedPayload, reqRec.Body.String())
+	})
+
+	t.Run("Request with zero timeout", func(t *testing.T) {
+		message := domain.Message{QueueID: "my-queue", Body: `{"message": true}`}
+		reply := domain.Message{ID: "my-reply", QueueID: "my-reply-queue", Body: `{"reply": true}`}
+		jsonMessage, _ := json.Marshal(&message)
+		tc := makeTestContext(t)
+		reqRec := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/v1/queues/my-queue/request?timeout_seconds=0", bytes.NewBuffer(jsonMessage))
+
+		tc.messageService.On("Request", mock.Anything, &message, 30*time.Second).Return(&reply, nil)
+		tc.router.ServeHTTP(reqRec, req)
+
+		assert.Equal(t, http.StatusOK, reqRec.Code)
+	})
+
 	t.Run("Ack", func(t *testing.T) {
 		tc := makeTestContext(t)
 		reqRec := httptest.NewRecorder()
 		req, _ := http.NewRequest("PUT", "/v1/queues/my-queue/messages/message-id/ack", nil)
 
-		tc.messageService.On("Ack", mock.Anything, "message-id").Return(nil)
+		tc.messageService.On("Ack", mock.Anything, "message-id", (*domain.MessageResult)(nil)).Return(nil)
+		tc.router.ServeHTTP(reqRec, req)
+
+		assert.Equal(t, http.StatusNoContent, reqRec.Code)
+	})
+
+	t.Run("Ack with result", func(t *testing.T) {
+		body := `{"rows": 10}`
+		result := domain.MessageResult{Status: domain.MessageResultStatusSucceeded, Body: &body}
+		tc := makeTestContext(t)
+		reqRec := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/v1/queues/my-queue/messages/message-id/ack", bytes.NewBuffer([]byte(`{"status": "succeeded", "body": "{\"rows\": 10}"}`)))
+
+		tc.messageService.On("Ack", mock.Anything, "message-id", &result).Return(nil)
+		tc.router.ServeHTTP(reqRec, req)
+
+		assert.Equal(t, http.StatusNoContent, reqRec.Code)
+	})
+
+	t.Run("Ack with malformed result", func(t *testing.T) {
+		expectedPayload := `{"code":2,"message":"malformed request body"}`
+		tc := makeTestContext(t)
+		reqRec := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/v1/queues/my-queue/messages/message-id/ack", bytes.NewBuffer([]byte(`{"status":`)))
+
+		tc.router.ServeHTTP(reqRec, req)
+
+		assert.Equal(t, http.StatusBadRequest, reqRec.Code)
+		assert.Equal(t, expectedPayload, reqRec.Body.String())
+	})
+
+	t.Run("Progress", func(t *testing.T) {
+		note := "processing page 5 of 10"
+		progress := domain.MessageProgress{Percent: 50, Note: &note}
+		tc := makeTestContext(t)
+		reqRec := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/v1/queues/my-queue/messages/message-id/progress", bytes.NewBuffer([]byte(`{"percent": 50, "note": "processing page 5 of 10"}`)))
+
+		tc.messageService.On("Progress", mock.Anything, "my-queue", "message-id", &progress).Return(nil)
+		tc.router.ServeHTTP(reqRec, req)
+
+		assert.Equal(t, http.StatusNoContent, reqRec.Code)
+	})
+
+	t.Run("Progress with message not in flight", func(t *testing.T) {
+		expectedPayload := `{"code":17,"message":"message is not in flight"}`
+		progress := domain.MessageProgress{Percent: 50}
+		tc := makeTestContext(t)
+		reqRec := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/v1/queues/my-queue/messages/message-id/progress", bytes.NewBuffer([]byte(`{"percent": 50}`)))
+
+		tc.messageService.On("Progress", mock.Anything, "my-queue", "message-id", &progress).Return(domain.ErrMessageNotInFlight)
+		tc.router.ServeHTTP(reqRec, req)
+
+		assert.Equal(t, http.StatusConflict, reqRec.Code)
+		assert.Equal(t, expectedPayload, reqRec.Body.String())
+	})
+
+	t.Run("GetJob", func(t *testing.T) {
+		now := time.Date(2023, 8, 17, 0, 0, 0, 0, time.UTC)
+		resultStatus := domain.MessageResultStatusSucceeded
+		job := domain.MessageJob{ID: "message-id", QueueID: "my-queue", Status: domain.MessageStatusAcked, DeliveryAttempts: 1, ResultStatus: &resultStatus, CreatedAt: now, UpdatedAt: now}
+		expectedPayload := `{"id":"message-id","queue_id":"my-queue","status":"acked","delivery_attempts":1,"errors":null,"progress_percent":null,"progress_note":null,"result_status":"succeeded","result":null,"created_at":"2023-08-17T00:00:00Z","updated_at":"2023-08-17T00:00:00Z"}`
+		tc := makeTestContext(t)
+		reqRec := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/v1/queues/my-queue/messages/message-id", nil)
+
+		tc.messageService.On("GetJob", mock.Anything, "my-queue", "message-id").Return(&job, nil)
+		tc.router.ServeHTTP(reqRec, req)
+
+		assert.Equal(t, http.StatusOK, reqRec.Code)
+		assert.Equal(t, expectedPayload, reqRec.Body.String())
+	})
+
+	t.Run("ListEvents", func(t *testing.T) {
+		now := time.Date(2023, 8, 17, 0, 0, 0, 0, time.UTC)
+		events := []*domain.MessageEvent{
+			{ID: 1, MessageID: "message-id", QueueID: "my-queue", Type: domain.MessageEventTypeEnqueue, CreatedAt: now, ExpiredAt: now},
+			{ID: 2, MessageID: "message-id", QueueID: "my-queue", Type: domain.MessageEventTypeLease, ConsumerID: pointString("worker-1"), CreatedAt: now, ExpiredAt: now},
+		}
+		expectedPayload := `{"data":[{"id":1,"message_id":"message-id","queue_id":"my-queue","type":"enqueue","consumer_id":null,"created_at":"2023-08-17T00:00:00Z"},{"id":2,"message_id":"message-id","queue_id":"my-queue","type":"lease","consumer_id":"worker-1","created_at":"2023-08-17T00:00:00Z"}],"limit":10}`
+		tc := makeTestContext(t)
+		reqRec := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/v1/queues/my-queue/messages/message-id/events?limit=10", nil)
+
+		tc.messageService.On("ListEvents", mock.Anything, "my-queue", "message-id", uint(0), uint(10)).Return(events, nil)
+		tc.router.ServeHTTP(reqRec, req)
+
+		assert.Equal(t, http.StatusOK, reqRec.Code)
+		assert.Equal(t, expectedPayload, reqRec.Body.String())
+	})
+
+	t.Run("ListInFlight", func(t *testing.T) {
+		now := time.Date(2023, 8, 17, 0, 0, 0, 0, time.UTC)
+		leases := []*domain.MessageLease{
+			{ID: "message-id", QueueID: "my-queue", ConsumerID: pointString("worker-1"), DeliveryAttempts: 1, LeasedAt: &now, Deadline: now.Add(30 * time.Second)},
+		}
+		expectedPayload := `{"data":[{"id":"message-id","queue_id":"my-queue","label":null,"consumer_id":"worker-1","delivery_attempts":1,"leased_at":"2023-08-17T00:00:00Z","deadline":"2023-08-17T00:00:30Z"}],"limit":10}`
+		tc := makeTestContext(t)
+		reqRec := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/v1/queues/my-queue/in-flight?limit=10", nil)
+
+		tc.messageService.On("ListInFlight", mock.Anything, "my-queue", uint(0), uint(10)).Return(leases, nil)
+		tc.router.ServeHTTP(reqRec, req)
+
+		assert.Equal(t, http.StatusOK, reqRec.Code)
+		assert.Equal(t, expectedPayload, reqRec.Body.String())
+	})
+
+	t.Run("ReleaseConsumer", func(t *testing.T) {
+		tc := makeTestContext(t)
+		reqRec := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/v1/queues/my-queue/consumers/worker-1/release", nil)
+
+		tc.messageService.On("ReleaseConsumer", mock.Anything, "my-queue", "worker-1").Return(nil)
 		tc.router.ServeHTTP(reqRec, req)
 
 		assert.Equal(t, http.StatusNoContent, reqRec.Code)
@@ -90,9 +459,33 @@ func TestMessageHandler(t *testing.T) {
 		reqRec := httptest.NewRecorder()
 		req, _ := http.NewRequest("PUT", "/v1/queues/my-queue/messages/message-id/nack", bytes.NewBuffer([]byte(`{"visibility_timeout_seconds": 0}`)))
 
-		tc.messageService.On("Nack", mock.Anything, "message-id", uint(0)).Return(nil)
+		tc.messageService.On("Nack", mock.Anything, "message-id", uint(0), (*domain.MessageError)(nil)).Return(nil)
+		tc.router.ServeHTTP(reqRec, req)
+
+		assert.Equal(t, http.StatusNoContent, reqRec.Code)
+	})
+
+	t.Run("Nack with error", func(t *testing.T) {
+		tc := makeTestContext(t)
+		reqRec := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/v1/queues/my-queue/messages/message-id/nack", bytes.NewBuffer([]byte(`{"visibility_timeout_seconds": 30, "error": "connection refused", "error_code": "ECONNREFUSED"}`)))
+
+		messageError := domain.MessageError{Message: "connection refused", Code: pointString("ECONNREFUSED")}
+		tc.messageService.On("Nack", mock.Anything, "message-id", uint(30), &messageError).Return(nil)
 		tc.router.ServeHTTP(reqRec, req)
 
 		assert.Equal(t, http.StatusNoContent, reqRec.Code)
 	})
+
+	t.Run("Nack with malformed request", func(t *testing.T) {
+		expectedPayload := `{"code":2,"message":"malformed request body"}`
+		tc := makeTestContext(t)
+		reqRec := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/v1/queues/my-queue/messages/message-id/nack", bytes.NewBuffer([]byte(`{`)))
+
+		tc.router.ServeHTTP(reqRec, req)
+
+		assert.Equal(t, http.StatusBadRequest, reqRec.Code)
+		assert.Equal(t, expectedPayload, reqRec.Body.String())
+	})
 }
@@ -12,27 +12,69 @@ import (
 
 // nolint:unused
 type queueRequest struct {
-	ID                      string `json:"id" example:"my-new-queue" validate:"required"`
-	AckDeadlineSeconds      int    `json:"ack_deadline_seconds" example:"30" validate:"required"`
-	MessageRetentionSeconds int    `json:"message_retention_seconds" example:"604800" validate:"required"`
-	DeliveryDelaySeconds    int    `json:"delivery_delay_seconds" example:"0" validate:"required"`
+	ID                          string         `json:"id" example:"my-new-queue" validate:"required"`
+	AckDeadlineSeconds          int            `json:"ack_deadline_seconds" example:"30" validate:"required"`
+	MessageRetentionSeconds     int            `json:"message_retention_seconds" example:"604800" validate:"required"`
+	DeliveryDelaySeconds        int            `json:"delivery_delay_seconds" example:"0" validate:"required"`
+	ResultRetentionSeconds      int            `json:"result_retention_seconds" example:"0" validate:"optional"`
+	UniqueKeyPolicy             string         `json:"unique_key_policy" example:"reject" enums:"reject,ignore" validate:"optional"`
+	Coalesce                    bool           `json:"coalesce" example:"false" validate:"optional"`
+	DeliveryKeySource           string         `json:"delivery_key_source" example:"attribute" enums:"label,attribute" validate:"optional"`
+	DeliveryKeyAttribute        string         `json:"delivery_key_attribute" example:"tenant" validate:"optional"`
+	MaxInFlightPerKey           int            `json:"max_in_flight_per_key" example:"0" validate:"optional"`
+	FairDelivery                bool           `json:"fair_delivery" example:"false" validate:"optional"`
+	FairDeliveryWeights         map[string]int `json:"fair_delivery_weights" validate:"optional"`
+	MaxDeliveriesPerSecond      uint           `json:"max_deliveries_per_second" example:"0" validate:"optional"`
+	DeliveryBurst               uint           `json:"delivery_burst" example:"0" validate:"optional"`
+	MaxVisibilityTimeoutSeconds uint           `json:"max_visibility_timeout_seconds" example:"0" validate:"optional"`
+	DeliveryLog                 bool           `json:"delivery_log" example:"false" validate:"optional"`
+	DeliveryLogRetentionSeconds uint           `json:"delivery_log_retention_seconds" example:"0" validate:"optional"`
+	AckedRetentionSeconds       uint           `json:"acked_retention_seconds" example:"0" validate:"optional"`
 } //@name QueueRequest
 
 // nolint:unused
 type queueUpdateRequest struct {
-	AckDeadlineSeconds      int `json:"ack_deadline_seconds" example:"30" validate:"required"`
-	MessageRetentionSeconds int `json:"message_retention_seconds" example:"604800" validate:"required"`
-	DeliveryDelaySeconds    int `json:"delivery_delay_seconds" example:"0" validate:"required"`
+	AckDeadlineSeconds          int            `json:"ack_deadline_seconds" example:"30" validate:"required"`
+	MessageRetentionSeconds     int            `json:"message_retention_seconds" example:"604800" validate:"required"`
+	DeliveryDelaySeconds        int            `json:"delivery_delay_seconds" example:"0" validate:"required"`
+	ResultRetentionSeconds      int            `json:"result_retention_seconds" example:"0" validate:"optional"`
+	UniqueKeyPolicy             string         `json:"unique_key_policy" example:"reject" enums:"reject,ignore" validate:"optional"`
+	Coalesce                    bool           `json:"coalesce" example:"false" validate:"optional"`
+	DeliveryKeySource           string         `json:"delivery_key_source" example:"attribute" enums:"label,attribute" validate:"optional"`
+	DeliveryKeyAttribute        string         `json:"delivery_key_attribute" example:"tenant" validate:"optional"`
+	MaxInFlightPerKey           int            `json:"max_in_flight_per_key" example:"0" validate:"optional"`
+	FairDelivery                bool           `json:"fair_delivery" example:"false" validate:"optional"`
+	FairDeliveryWeights         map[string]int `json:"fair_delivery_weights" validate:"optional"`
+	MaxDeliveriesPerSecond      uint           `json:"max_deliveries_per_second" example:"0" validate:"optional"`
+	DeliveryBurst               uint           `json:"delivery_burst" example:"0" validate:"optional"`
+	MaxVisibilityTimeoutSeconds uint           `json:"max_visibility_timeout_seconds" example:"0" validate:"optional"`
+	DeliveryLog                 bool           `json:"delivery_log" example:"false" validate:"optional"`
+	DeliveryLogRetentionSeconds uint           `json:"delivery_log_retention_seconds" example:"0" validate:"optional"`
+	AckedRetentionSeconds       uint           `json:"acked_retention_seconds" example:"0" validate:"optional"`
 } //@name QueueUpdateRequest
 
 // nolint:unused
 type queueResponse struct {
-	ID                      string    `json:"id" example:"my-new-queue"`
-	AckDeadlineSeconds      int       `json:"ack_deadline_seconds" example:"30"`
-	MessageRetentionSeconds int       `json:"message_retention_seconds" example:"604800"`
-	DeliveryDelaySeconds    int       `json:"delivery_delay_seconds" example:"0"`
-	CreatedAt               time.Time `json:"created_at" example:"2023-08-17T00:00:00Z"`
-	UpdatedAt               time.Time `json:"updated_at" example:"2023-08-17T00:00:00Z"`
+	ID                          string         `json:"id" example:"my-new-queue"`
+	AckDeadlineSeconds          int            `json:"ack_deadline_seconds" example:"30"`
+	MessageRetentionSeconds     int            `json:"message_retention_seconds" example:"604800"`
+	DeliveryDelaySeconds        int            `json:"delivery_delay_seconds" example:"0"`
+	ResultRetentionSeconds      int            `json:"result_retention_seconds" example:"0"`
+	UniqueKeyPolicy             string         `json:"unique_key_policy" example:"reject" enums:"reject,ignore"`
+	Coalesce                    bool           `json:"coalesce" example:"false"`
+	DeliveryKeySource           string         `json:"delivery_key_source" example:"attribute" enums:"label,attribute"`
+	DeliveryKeyAttribute        *string        `json:"delivery_key_attribute" example:"tenant"`
+	MaxInFlightPerKey           int            `json:"max_in_flight_per_key" example:"0"`
+	FairDelivery                bool           `json:"fair_delivery" example:"false"`
+	FairDeliveryWeights         map[string]int `json:"fair_delivery_weights"`
+	MaxDeliveriesPerSecond      uint           `json:"max_deliveries_per_second" example:"0"`
+	DeliveryBurst               uint           `json:"delivery_burst" example:"0"`
+	MaxVisibilityTimeoutSeconds uint           `json:"max_visibility_timeout_seconds" example:"0"`
+	DeliveryLog                 bool           `json:"delivery_log" example:"false"`
+	DeliveryLogRetentionSeconds uint           `json:"delivery_log_retention_seconds" example:"0"`
+	AckedRetentionSeconds       uint           `json:"acked_retention_seconds" example:"0"`
+	CreatedAt                   time.Time      `json:"created_at" example:"2023-08-17T00:00:00Z"`
+	UpdatedAt                   time.Time      `json:"updated_at" example:"2023-08-17T00:00:00Z"`
 } //@name QueueResponse
 
 // nolint:unused
@@ -46,8 +88,16 @@ type queueListResponse struct {
 type queueStatsResponse struct {
 	NumUndeliveredMessages         int `json:"num_undelivered_messages" example:"1"`
 	OldestUnackedMessageAgeSeconds int `json:"oldest_unacked_message_age_seconds" example:"1"`
+	NumCoalescedMessages           int `json:"num_coalesced_messages" example:"0"`
+	NumAckedMessages               int `json:"num_acked_messages" example:"0"`
+	NumExpiredMessages             int `json:"num_expired_messages" example:"0"`
 } //@name QueueStatsResponse
 
+// nolint:unused
+type queueSeekRequest struct {
+	Timestamp time.Time `json:"timestamp" example:"2023-08-17T00:00:00Z" validate:"required"`
+} //@name QueueSeekRequest
+
 // Queue exposes a REST API for domain.QueueService.
 type QueueHandler struct {
 	queueService domain.QueueService
@@ -260,6 +310,39 @@ func (q *QueueHandler) Cleanup(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
+// Seek a queue.
+//
+//	@Summary	Deliver again the acked messages after a timestamp
+//	@Tags		queues
+//	@Accept		json
+//	@Produce	json
+//	@Param		queue_id	path	string				true	"Queue id"
+//	@Param		request		body	queueSeekRequest	true	"Seek the queue"
+//	@Success	204			"No Content"
+//	@Failure	400			{object}	errorResponse
+//	@Failure	404			{object}	errorResponse
+//	@Failure	500			{object}	errorResponse
+//	@Router		/queues/{queue_id}/seek [put]
+func (q *QueueHandler) Seek(c *gin.Context) {
+	id := c.Param("queue_id")
+	seek := domain.QueueSeek{}
+
+	if err := c.ShouldBindJSON(&seek); err != nil {
+		slog.Error("malformed request", "error", err.Error())
+		er := errorResponses["malformed_request"]
+		c.JSON(er.StatusCode, &er)
+		return
+	}
+
+	if err := q.queueService.Seek(c.Request.Context(), id, &seek); err != nil {
+		er := parseServiceError("queueService", "Seek", err)
+		c.JSON(er.StatusCode, &er)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
 // NewQueueHandler returns a new QueueHandler.
 func NewQueueHandler(queueService domain.QueueService) *QueueHandler {
 	return &QueueHandler{queueService: queueService}
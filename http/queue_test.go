@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -86,7 +87,7 @@ func TestQueueHandler(t *testing.T) {
 	})
 
 	t.Run("Create", func(t *testing.T) {
-		expectedPayload := `{"id":"my-queue","ack_deadline_seconds":0,"message_retention_seconds":0,"delivery_delay_seconds":0,"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"}`
+		expectedPayload := `{"id":"my-queue","ack_deadline_seconds":0,"message_retention_seconds":0,"delivery_delay_seconds":0,"result_retention_seconds":0,"unique_key_policy":"","coalesce":false,"delivery_key_source":"","delivery_key_attribute":null,"max_in_flight_per_key":0,"fair_delivery":false,"fair_delivery_weights":null,"max_deliveries_per_second":0,"delivery_burst":0,"max_visibility_timeout_seconds":0,"delivery_log":false,"delivery_log_retention_seconds":0,"acked_retention_seconds":0,"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"}`
 		queue := domain.Queue{ID: "my-queue"}
 		jsonQueue, _ := json.Marshal(&queue)
 		tc := makeTestContext(t)
@@ -114,7 +115,7 @@ func TestQueueHandler(t *testing.T) {
 	})
 
 	t.Run("Get", func(t *testing.T) {
-		expectedPayload := `{"id":"my-queue","ack_deadline_seconds":0,"message_retention_seconds":0,"delivery_delay_seconds":0,"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"}`
+		expectedPayload := `{"id":"my-queue","ack_deadline_seconds":0,"message_retention_seconds":0,"delivery_delay_seconds":0,"result_retention_seconds":0,"unique_key_policy":"","coalesce":false,"delivery_key_source":"","delivery_key_attribute":null,"max_in_flight_per_key":0,"fair_delivery":false,"fair_delivery_weights":null,"max_deliveries_per_second":0,"delivery_burst":0,"max_visibility_timeout_seconds":0,"delivery_log":false,"delivery_log_retention_seconds":0,"acked_retention_seconds":0,"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"}`
 		queue := domain.Queue{ID: "my-queue"}
 		tc := makeTestContext(t)
 		reqRec := httptest.NewRecorder()
@@ -155,7 +156,7 @@ func TestQueueHandler(t *testing.T) {
 	})
 
 	t.Run("Update", func(t *testing.T) {
-		expectedPayload := `{"id":"my-queue","ack_deadline_seconds":0,"message_retention_seconds":0,"delivery_delay_seconds":0,"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"}`
+		expectedPayload := `{"id":"my-queue","ack_deadline_seconds":0,"message_retention_seconds":0,"delivery_delay_seconds":0,"result_retention_seconds":0,"unique_key_policy":"","coalesce":false,"delivery_key_source":"","delivery_key_attribute":null,"max_in_flight_per_key":0,"fair_delivery":false,"fair_delivery_weights":null,"max_deliveries_per_second":0,"delivery_burst":0,"max_visibility_timeout_seconds":0,"delivery_log":false,"delivery_log_retention_seconds":0,"acked_retention_seconds":0,"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"}`
 		queue := domain.Queue{ID: "my-queue"}
 		jsonQueue, _ := json.Marshal(&queue)
 		tc := makeTestContext(t)
@@ -170,7 +171,7 @@ func TestQueueHandler(t *testing.T) {
 	})
 
 	t.Run("List", func(t *testing.T) {
-		expectedPayload := `{"data":[{"id":"my-queue-1","ack_deadline_seconds":0,"message_retention_seconds":0,"delivery_delay_seconds":0,"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"},{"id":"my-queue-2","ack_deadline_seconds":0,"message_retention_seconds":0,"delivery_delay_seconds":0,"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"}],"limit":1}`
+		expectedPayload := `{"data":[{"id":"my-queue-1","ack_deadline_seconds":0,"message_retention_seconds":0,"delivery_delay_seconds":0,"result_retention_seconds":0,"unique_key_policy":"","coalesce":false,"delivery_key_source":"","delivery_key_attribute":null,"max_in_flight_per_key":0,"fair_delivery":false,"fair_delivery_weights":null,"max_deliveries_per_second":0,"delivery_burst":0,"max_visibility_timeout_seconds":0,"delivery_log":false,"delivery_log_retention_seconds":0,"acked_retention_seconds":0,"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"},{"id":"my-queue-2","ack_deadline_seconds":0,"message_retention_seconds":0,"delivery_delay_seconds":0,"result_retention_seconds":0,"unique_key_policy":"","coalesce":false,"delivery_key_source":"","delivery_key_attribute":null,"max_in_flight_per_key":0,"fair_delivery":false,"fair_delivery_weights":null,"max_deliveries_per_second":0,"delivery_burst":0,"max_visibility_timeout_seconds":0,"delivery_log":false,"delivery_log_retention_seconds":0,"acked_retention_seconds":0,"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"}],"limit":1}`
 		queue1 := domain.Queue{ID: "my-queue-1"}
 		queue2 := domain.Queue{ID: "my-queue-2"}
 		tc := makeTestContext(t)
@@ -209,7 +210,7 @@ func TestQueueHandler(t *testing.T) {
 	})
 
 	t.Run("Stats", func(t *testing.T) {
-		expectedPayload := `{"num_undelivered_messages":0,"oldest_unacked_message_age_seconds":0}`
+		expectedPayload := `{"num_undelivered_messages":0,"oldest_unacked_message_age_seconds":0,"num_coalesced_messages":0,"num_acked_messages":0,"num_expired_messages":0}`
 		tc := makeTestContext(t)
 		reqRec := httptest.NewRecorder()
 		req, _ := http.NewRequest("GET", "/v1/queues/my-queue/stats", nil)
@@ -242,4 +243,28 @@ func TestQueueHandler(t *testing.T) {
 
 		assert.Equal(t, http.StatusNoContent, reqRec.Code)
 	})
+
+	t.Run("Seek", func(t *testing.T) {
+		tc := makeTestContext(t)
+		reqRec := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/v1/queues/my-queue/seek", bytes.NewBuffer([]byte(`{"timestamp": "2023-08-17T00:00:00Z"}`)))
+
+		seek := domain.QueueSeek{Timestamp: time.Date(2023, 8, 17, 0, 0, 0, 0, time.UTC)}
+		tc.queueService.On("Seek", mock.Anything, "my-queue", &seek).Return(nil)
+		tc.router.ServeHTTP(reqRec, req)
+
+		assert.Equal(t, http.StatusNoContent, reqRec.Code)
+	})
+
+	t.Run("Seek with malformed request", func(t *testing.T) {
+		expectedPayload := `{"code":2,"message":"malformed request body"}`
+		tc := makeTestContext(t)
+		reqRec := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/v1/queues/my-queue/seek", bytes.NewBuffer([]byte(`{"timestamp": "yesterday"}`)))
+
+		tc.router.ServeHTTP(reqRec, req)
+
+		assert.Equal(t, http.StatusBadRequest, reqRec.Code)
+		assert.Equal(t, expectedPayload, reqRec.Body.String())
+	})
 }
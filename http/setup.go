@@ -53,12 +53,20 @@ func SetupRouter(logger *slog.Logger, queueHandler *QueueHandler, messageHandler
 	v1.GET("/queues/:queue_id/stats", queueHandler.Stats)
 	v1.PUT("/queues/:queue_id/purge", queueHandler.Purge)
 	v1.PUT("/queues/:queue_id/cleanup", queueHandler.Cleanup)
+	v1.PUT("/queues/:queue_id/seek", queueHandler.Seek)
 
 	// message handler
 	v1.POST("/queues/:queue_id/messages", messageHandler.Create)
 	v1.GET("/queues/:queue_id/messages", messageHandler.List)
+	v1.POST("/queues/:queue_id/request", messageHandler.Request)
+	v1.GET("/queues/:queue_id/messages/:message_id", messageHandler.GetJob)
+	v1.PUT("/queues/:queue_id/messages/:message_id/progress", messageHandler.Progress)
 	v1.PUT("/queues/:queue_id/messages/:message_id/ack", messageHandler.Ack)
 	v1.PUT("/queues/:queue_id/messages/:message_id/nack", messageHandler.Nack)
+	v1.GET("/queues/:queue_id/messages/:message_id/events", messageHandler.ListEvents)
+	v1.GET("/queues/:queue_id/in-flight", messageHandler.ListInFlight)
+	v1.PUT("/queues/:queue_id/consumers/:consumer_id/release", messageHandler.ReleaseConsumer)
+	v1.GET("/messages", messageHandler.ListFromQueues)
 
 	// topic handler
 	v1.POST("/topics", topicHandler.Create)
@@ -66,12 +74,21 @@ func SetupRouter(logger *slog.Logger, queueHandler *QueueHandler, messageHandler
 	v1.GET("/topics", topicHandler.List)
 	v1.DELETE("/topics/:topic_id", topicHandler.Delete)
 	v1.POST("/topics/:topic_id/messages", topicHandler.CreateMessage)
+	v1.POST("/topics/:topic_id/ingest", topicHandler.Ingest)
+	v1.GET("/topics/:topic_id/messages/:publish_id", topicHandler.GetPublish)
+	v1.POST("/topics/:topic_id/simulate", topicHandler.Simulate)
+	v1.PUT("/topics/:topic_id/cleanup", topicHandler.Cleanup)
+	v1.GET("/topics/:topic_id/stats", topicHandler.Stats)
 
 	// subscription handler
 	v1.POST("/subscriptions", subscriptionHandler.Create)
+	v1.PUT("/subscriptions/:subscription_id", subscriptionHandler.Update)
 	v1.GET("/subscriptions/:subscription_id", subscriptionHandler.Get)
 	v1.GET("/subscriptions", subscriptionHandler.List)
 	v1.DELETE("/subscriptions/:subscription_id", subscriptionHandler.Delete)
+	v1.POST("/subscriptions/:subscription_id/replay", subscriptionHandler.Replay)
+	v1.GET("/topics/:topic_id/subscriptions", subscriptionHandler.ListByTopic)
+	v1.GET("/queues/:queue_id/subscriptions", subscriptionHandler.ListByQueue)
 
 	// health check handler
 	v1.GET("/healthz", healthCheckHandler.Check)
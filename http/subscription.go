@@ -10,21 +10,40 @@ import (
 	"github.com/allisson/psqlqueue/domain"
 )
 
+// nolint:unused
+type subscriptionTransformation struct {
+	SetAttributes    map[string]string `json:"set_attributes"`
+	RemoveAttributes []string          `json:"remove_attributes" example:"internal_id"`
+	SetLabel         *string           `json:"set_label" example:"orders"`
+	Envelope         bool              `json:"envelope" example:"false"`
+} //@name SubscriptionTransformation
+
 // nolint:unused
 type subscriptionRequest struct {
-	ID             string              `json:"id" example:"my-new-subscription" validate:"required"`
-	TopicID        string              `json:"topic_id" example:"my-new-topic" validate:"required"`
-	QueueID        string              `json:"queue_id" example:"my-new-queue" validate:"required"`
-	MessageFilters map[string][]string `json:"message_filters"`
+	ID             string                      `json:"id" example:"my-new-subscription" validate:"required"`
+	TopicID        string                      `json:"topic_id" example:"my-new-topic" validate:"required"`
+	QueueID        *string                     `json:"queue_id" example:"my-new-queue" validate:"optional"`
+	TargetTopicID  *string                     `json:"target_topic_id" example:"my-other-topic" validate:"optional"`
+	MessageFilters map[string][]string         `json:"message_filters"`
+	Transformation *subscriptionTransformation `json:"transformation"`
 } //@name SubscriptionRequest
 
+// nolint:unused
+type subscriptionUpdateRequest struct {
+	MessageFilters map[string][]string         `json:"message_filters"`
+	Transformation *subscriptionTransformation `json:"transformation"`
+} //@name SubscriptionUpdateRequest
+
 // nolint:unused
 type subscriptionResponse struct {
-	ID             string              `json:"id" example:"my-new-subscription"`
-	TopicID        string              `json:"topic_id" example:"my-new-topic"`
-	QueueID        string              `json:"queue_id" example:"my-new-queue"`
-	MessageFilters map[string][]string `json:"message_filters"`
-	CreatedAt      time.Time           `json:"created_at" example:"2023-08-17T00:00:00Z"`
+	ID             string                      `json:"id" example:"my-new-subscription"`
+	TopicID        string                      `json:"topic_id" example:"my-new-topic"`
+	QueueID        *string                     `json:"queue_id" example:"my-new-queue"`
+	TargetTopicID  *string                     `json:"target_topic_id" example:"my-other-topic"`
+	MessageFilters map[string][]string         `json:"message_filters"`
+	Transformation *subscriptionTransformation `json:"transformation"`
+	CreatedAt      time.Time                   `json:"created_at" example:"2023-08-17T00:00:00Z"`
+	UpdatedAt      time.Time                   `json:"updated_at" example:"2023-08-17T00:00:00Z"`
 } //@name SubscriptionResponse
 
 // nolint:unused
@@ -34,6 +53,23 @@ type subscriptionListResponse struct {
 	Limit  int              `json:"limit" example:"10"`
 } //@name SubscriptionListResponse
 
+// nolint:unused
+type subscriptionReplayRequest struct {
+	From time.Time `json:"from" example:"2023-08-17T00:00:00Z" validate:"required"`
+	To   time.Time `json:"to" example:"2023-08-18T00:00:00Z" validate:"optional"`
+} //@name SubscriptionReplayRequest
+
+// nolint:unused
+type subscriptionReplayResponse struct {
+	SubscriptionID string    `json:"subscription_id" example:"my-new-subscription"`
+	QueueID        *string   `json:"queue_id" example:"my-new-queue"`
+	TargetTopicID  *string   `json:"target_topic_id" example:"my-other-topic"`
+	From           time.Time `json:"from" example:"2023-08-17T00:00:00Z"`
+	To             time.Time `json:"to" example:"2023-08-18T00:00:00Z"`
+	PublishID      string    `json:"publish_id" example:"01HK651Q52EZMPKBYZGVK0ZX8S"`
+	NumMessages    int       `json:"num_messages" example:"10"`
+} //@name SubscriptionReplayResponse
+
 // SubscriptionHandler exposes a REST API for domain.SubscriptionService.
 type SubscriptionHandler struct {
 	subscriptionService domain.SubscriptionService
@@ -69,6 +105,40 @@ func (s *SubscriptionHandler) Create(c *gin.Context) {
 	c.JSON(http.StatusCreated, &subscription)
 }
 
+// Update a subscription.
+//
+//	@Summary	Update a subscription
+//	@Tags		subscriptions
+//	@Accept		json
+//	@Produce	json
+//	@Param		subscription_id	path		string						true	"Subscription id"
+//	@Param		request			body		subscriptionUpdateRequest	true	"Update a subscription"
+//	@Success	200				{object}	subscriptionResponse
+//	@Failure	400				{object}	errorResponse
+//	@Failure	404				{object}	errorResponse
+//	@Failure	500				{object}	errorResponse
+//	@Router		/subscriptions/{subscription_id} [put]
+func (s *SubscriptionHandler) Update(c *gin.Context) {
+	subscription := domain.Subscription{}
+
+	if err := c.ShouldBindJSON(&subscription); err != nil {
+		slog.Error("malformed request", "error", err.Error())
+		er := errorResponses["malformed_request"]
+		c.JSON(er.StatusCode, &er)
+		return
+	}
+
+	subscription.ID = c.Param("subscription_id")
+
+	if err := s.subscriptionService.Update(c.Request.Context(), &subscription); err != nil {
+		er := parseServiceError("subscriptionService", "Update", err)
+		c.JSON(er.StatusCode, &er)
+		return
+	}
+
+	c.JSON(http.StatusOK, &subscription)
+}
+
 // Get a subscription.
 //
 //	@Summary	Show a subscription
@@ -119,6 +189,64 @@ func (s *SubscriptionHandler) List(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// List subscriptions by topic.
+//
+//	@Summary	List the subscriptions of a topic
+//	@Tags		subscriptions
+//	@Accept		json
+//	@Produce	json
+//	@Param		topic_id	path		string	true	"Topic id"
+//	@Param		limit		query		int		false	"The limit indicates the maximum number of items to return"
+//	@Param		offset		query		int		false	"The offset indicates the starting position of the query in relation to the complete set of unpaginated items"
+//	@Success	200			{object}	subscriptionListResponse
+//	@Failure	404			{object}	errorResponse
+//	@Failure	500			{object}	errorResponse
+//	@Router		/topics/{topic_id}/subscriptions [get]
+func (s *SubscriptionHandler) ListByTopic(c *gin.Context) {
+	topicID := c.Param("topic_id")
+	request := newListRequestFromGIN(c)
+
+	subscriptions, err := s.subscriptionService.ListByTopic(c.Request.Context(), topicID, request.Offset, request.Limit)
+	if err != nil {
+		er := parseServiceError("subscriptionService", "ListByTopic", err)
+		c.JSON(er.StatusCode, &er)
+		return
+	}
+
+	response := listResponse{Data: subscriptions, Offset: request.Offset, Limit: request.Limit}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// List subscriptions by queue.
+//
+//	@Summary	List the subscriptions that deliver messages to a queue
+//	@Tags		subscriptions
+//	@Accept		json
+//	@Produce	json
+//	@Param		queue_id	path		string	true	"Queue id"
+//	@Param		limit		query		int		false	"The limit indicates the maximum number of items to return"
+//	@Param		offset		query		int		false	"The offset indicates the starting position of the query in relation to the complete set of unpaginated items"
+//	@Success	200			{object}	subscriptionListResponse
+//	@Failure	404			{object}	errorResponse
+//	@Failure	500			{object}	errorResponse
+//	@Router		/queues/{queue_id}/subscriptions [get]
+func (s *SubscriptionHandler) ListByQueue(c *gin.Context) {
+	queueID := c.Param("queue_id")
+	request := newListRequestFromGIN(c)
+
+	subscriptions, err := s.subscriptionService.ListByQueue(c.Request.Context(), queueID, request.Offset, request.Limit)
+	if err != nil {
+		er := parseServiceError("subscriptionService", "ListByQueue", err)
+		c.JSON(er.StatusCode, &er)
+		return
+	}
+
+	response := listResponse{Data: subscriptions, Offset: request.Offset, Limit: request.Limit}
+
+	c.JSON(http.StatusOK, response)
+}
+
 // Delete a subscription.
 //
 //	@Summary	Delete a subscription
@@ -142,6 +270,41 @@ func (s *SubscriptionHandler) Delete(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
+// Replay the topic message log into a subscription.
+//
+//	@Summary	Replay the messages published to the topic in a time range into the subscription queue
+//	@Tags		subscriptions
+//	@Accept		json
+//	@Produce	json
+//	@Param		subscription_id	path		string						true	"Subscription id"
+//	@Param		request			body		subscriptionReplayRequest	true	"Replay a time range"
+//	@Success	200				{object}	subscriptionReplayResponse
+//	@Failure	400				{object}	errorResponse
+//	@Failure	404				{object}	errorResponse
+//	@Failure	422				{object}	errorResponse
+//	@Failure	500				{object}	errorResponse
+//	@Router		/subscriptions/{subscription_id}/replay [post]
+func (s *SubscriptionHandler) Replay(c *gin.Context) {
+	replay := domain.SubscriptionReplay{}
+
+	if err := c.ShouldBindJSON(&replay); err != nil {
+		slog.Error("malformed request", "error", err.Error())
+		er := errorResponses["malformed_request"]
+		c.JSON(er.StatusCode, &er)
+		return
+	}
+
+	replay.SubscriptionID = c.Param("subscription_id")
+
+	if err := s.subscriptionService.Replay(c.Request.Context(), &replay); err != nil {
+		er := parseServiceError("subscriptionService", "Replay", err)
+		c.JSON(er.StatusCode, &er)
+		return
+	}
+
+	c.JSON(http.StatusOK, &replay)
+}
+
 // NewSubscriptionHandler returns a new SubscriptionHandler.
 func NewSubscriptionHandler(subscriptionService domain.SubscriptionService) *SubscriptionHandler {
 	return &SubscriptionHandler{subscriptionService: subscriptionService}
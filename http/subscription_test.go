@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -42,8 +43,8 @@ func TestSubscriptionHandler(t *testing.T) {
 	})
 
 	t.Run("Create", func(t *testing.T) {
-		expectedPayload := `{"id":"my-subscription","topic_id":"my-topic","queue_id":"my-queue","message_filters":null,"created_at":"0001-01-01T00:00:00Z"}`
-		subscription := domain.Subscription{ID: "my-subscription", TopicID: "my-topic", QueueID: "my-queue"}
+		expectedPayload := `{"id":"my-subscription","topic_id":"my-topic","queue_id":"my-queue","target_topic_id":null,"message_filters":null,"transformation":null,"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"}`
+		subscription := domain.Subscription{ID: "my-subscription", TopicID: "my-topic", QueueID: pointString("my-queue")}
 		jsonSubscription, _ := json.Marshal(&subscription)
 		tc := makeTestContext(t)
 		reqRec := httptest.NewRecorder()
@@ -56,6 +57,49 @@ func TestSubscriptionHandler(t *testing.T) {
 		assert.Equal(t, expectedPayload, reqRec.Body.String())
 	})
 
+	t.Run("Create with topic cycle", func(t *testing.T) {
+		expectedPayload := `{"code":13,"message":"subscription creates a topic cycle"}`
+		subscription := domain.Subscription{ID: "my-subscription", TopicID: "my-topic", TargetTopicID: pointString("my-other-topic")}
+		jsonSubscription, _ := json.Marshal(&subscription)
+		tc := makeTestContext(t)
+		reqRec := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/v1/subscriptions", bytes.NewBuffer(jsonSubscription))
+
+		tc.subscriptionService.On("Create", mock.Anything, &subscription).Return(domain.ErrSubscriptionCycle)
+		tc.router.ServeHTTP(reqRec, req)
+
+		assert.Equal(t, http.StatusBadRequest, reqRec.Code)
+		assert.Equal(t, expectedPayload, reqRec.Body.String())
+	})
+
+	t.Run("Update with object not found", func(t *testing.T) {
+		expectedPayload := `{"code":10,"message":"subscription not found"}`
+		subscription := domain.Subscription{ID: "my-subscription", MessageFilters: map[string][]string{"status": {"processed"}}}
+		tc := makeTestContext(t)
+		reqRec := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/v1/subscriptions/my-subscription", bytes.NewBuffer([]byte(`{"message_filters":{"status":["processed"]}}`)))
+
+		tc.subscriptionService.On("Update", mock.Anything, &subscription).Return(domain.ErrSubscriptionNotFound)
+		tc.router.ServeHTTP(reqRec, req)
+
+		assert.Equal(t, http.StatusNotFound, reqRec.Code)
+		assert.Equal(t, expectedPayload, reqRec.Body.String())
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		expectedPayload := `{"id":"my-subscription","topic_id":"","queue_id":null,"target_topic_id":null,"message_filters":{"status":["processed"]},"transformation":null,"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"}`
+		subscription := domain.Subscription{ID: "my-subscription", MessageFilters: map[string][]string{"status": {"processed"}}}
+		tc := makeTestContext(t)
+		reqRec := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/v1/subscriptions/my-subscription", bytes.NewBuffer([]byte(`{"message_filters":{"status":["processed"]}}`)))
+
+		tc.subscriptionService.On("Update", mock.Anything, &subscription).Return(nil)
+		tc.router.ServeHTTP(reqRec, req)
+
+		assert.Equal(t, http.StatusOK, reqRec.Code)
+		assert.Equal(t, expectedPayload, reqRec.Body.String())
+	})
+
 	t.Run("Get with object not found", func(t *testing.T) {
 		expectedPayload := `{"code":10,"message":"subscription not found"}`
 		tc := makeTestContext(t)
@@ -70,8 +114,8 @@ func TestSubscriptionHandler(t *testing.T) {
 	})
 
 	t.Run("Get", func(t *testing.T) {
-		expectedPayload := `{"id":"my-subscription","topic_id":"my-topic","queue_id":"my-queue","message_filters":null,"created_at":"0001-01-01T00:00:00Z"}`
-		subscription := domain.Subscription{ID: "my-subscription", TopicID: "my-topic", QueueID: "my-queue"}
+		expectedPayload := `{"id":"my-subscription","topic_id":"my-topic","queue_id":"my-queue","target_topic_id":null,"message_filters":null,"transformation":null,"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"}`
+		subscription := domain.Subscription{ID: "my-subscription", TopicID: "my-topic", QueueID: pointString("my-queue")}
 		tc := makeTestContext(t)
 		reqRec := httptest.NewRecorder()
 		req, _ := http.NewRequest("GET", "/v1/subscriptions/my-subscription", nil)
@@ -84,9 +128,9 @@ func TestSubscriptionHandler(t *testing.T) {
 	})
 
 	t.Run("List", func(t *testing.T) {
-		expectedPayload := `{"data":[{"id":"my-subscription-1","topic_id":"my-topic","queue_id":"my-queue-1","message_filters":null,"created_at":"0001-01-01T00:00:00Z"},{"id":"my-subscription-2","topic_id":"my-topic","queue_id":"my-queue-2","message_filters":null,"created_at":"0001-01-01T00:00:00Z"}],"limit":1}`
-		subscription1 := domain.Subscription{ID: "my-subscription-1", TopicID: "my-topic", QueueID: "my-queue-1"}
-		subscription2 := domain.Subscription{ID: "my-subscription-2", TopicID: "my-topic", QueueID: "my-queue-2"}
+		expectedPayload := `{"data":[{"id":"my-subscription-1","topic_id":"my-topic","queue_id":"my-queue-1","target_topic_id":null,"message_filters":null,"transformation":null,"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"},{"id":"my-subscription-2","topic_id":"my-topic","queue_id":"my-queue-2","target_topic_id":null,"message_filters":null,"transformation":null,"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"}],"limit":1}`
+		subscription1 := domain.Subscription{ID: "my-subscription-1", TopicID: "my-topic", QueueID: pointString("my-queue-1")}
+		subscription2 := domain.Subscription{ID: "my-subscription-2", TopicID: "my-topic", QueueID: pointString("my-queue-2")}
 		tc := makeTestContext(t)
 		reqRec := httptest.NewRecorder()
 		req, _ := http.NewRequest("GET", "/v1/subscriptions", nil)
@@ -98,6 +142,62 @@ func TestSubscriptionHandler(t *testing.T) {
 		assert.Equal(t, expectedPayload, reqRec.Body.String())
 	})
 
+	t.Run("ListByTopic with object not found", func(t *testing.T) {
+		expectedPayload := `{"code":8,"message":"topic not found"}`
+		tc := makeTestContext(t)
+		reqRec := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/v1/topics/my-topic/subscriptions", nil)
+
+		tc.subscriptionService.On("ListByTopic", mock.Anything, "my-topic", uint(0), uint(1)).Return(nil, domain.ErrTopicNotFound)
+		tc.router.ServeHTTP(reqRec, req)
+
+		assert.Equal(t, http.StatusNotFound, reqRec.Code)
+		assert.Equal(t, expectedPayload, reqRec.Body.String())
+	})
+
+	t.Run("ListByTopic", func(t *testing.T) {
+		expectedPayload := `{"data":[{"id":"my-subscription-1","topic_id":"my-topic","queue_id":"my-queue-1","target_topic_id":null,"message_filters":null,"transformation":null,"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"},{"id":"my-subscription-2","topic_id":"my-topic","queue_id":"my-queue-2","target_topic_id":null,"message_filters":null,"transformation":null,"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"}],"offset":2,"limit":2}`
+		subscription1 := domain.Subscription{ID: "my-subscription-1", TopicID: "my-topic", QueueID: pointString("my-queue-1")}
+		subscription2 := domain.Subscription{ID: "my-subscription-2", TopicID: "my-topic", QueueID: pointString("my-queue-2")}
+		tc := makeTestContext(t)
+		reqRec := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/v1/topics/my-topic/subscriptions?offset=2&limit=2", nil)
+
+		tc.subscriptionService.On("ListByTopic", mock.Anything, "my-topic", uint(2), uint(2)).Return([]*domain.Subscription{&subscription1, &subscription2}, nil)
+		tc.router.ServeHTTP(reqRec, req)
+
+		assert.Equal(t, http.StatusOK, reqRec.Code)
+		assert.Equal(t, expectedPayload, reqRec.Body.String())
+	})
+
+	t.Run("ListByQueue with object not found", func(t *testing.T) {
+		expectedPayload := `{"code":5,"message":"queue not found"}`
+		tc := makeTestContext(t)
+		reqRec := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/v1/queues/my-queue/subscriptions", nil)
+
+		tc.subscriptionService.On("ListByQueue", mock.Anything, "my-queue", uint(0), uint(1)).Return(nil, domain.ErrQueueNotFound)
+		tc.router.ServeHTTP(reqRec, req)
+
+		assert.Equal(t, http.StatusNotFound, reqRec.Code)
+		assert.Equal(t, expectedPayload, reqRec.Body.String())
+	})
+
+	t.Run("ListByQueue", func(t *testing.T) {
+		expectedPayload := `{"data":[{"id":"my-subscription-1","topic_id":"my-topic-1","queue_id":"my-queue","target_topic_id":null,"message_filters":null,"transformation":null,"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"},{"id":"my-subscription-2","topic_id":"my-topic-2","queue_id":"my-queue","target_topic_id":null,"message_filters":null,"transformation":null,"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"}],"limit":1}`
+		subscription1 := domain.Subscription{ID: "my-subscription-1", TopicID: "my-topic-1", QueueID: pointString("my-queue")}
+		subscription2 := domain.Subscription{ID: "my-subscription-2", TopicID: "my-topic-2", QueueID: pointString("my-queue")}
+		tc := makeTestContext(t)
+		reqRec := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/v1/queues/my-queue/subscriptions", nil)
+
+		tc.subscriptionService.On("ListByQueue", mock.Anything, "my-queue", uint(0), uint(1)).Return([]*domain.Subscription{&subscription1, &subscription2}, nil)
+		tc.router.ServeHTTP(reqRec, req)
+
+		assert.Equal(t, http.StatusOK, reqRec.Code)
+		assert.Equal(t, expectedPayload, reqRec.Body.String())
+	})
+
 	t.Run("Delete with object not found", func(t *testing.T) {
 		expectedPayload := `{"code":10,"message":"subscription not found"}`
 		tc := makeTestContext(t)
@@ -121,4 +221,22 @@ func TestSubscriptionHandler(t *testing.T) {
 
 		assert.Equal(t, http.StatusNoContent, reqRec.Code)
 	})
+
+	t.Run("Replay", func(t *testing.T) {
+		expectedPayload := `{"subscription_id":"my-subscription","queue_id":null,"target_topic_id":null,"from":"2023-08-17T00:00:00Z","to":"2023-08-18T00:00:00Z","publish_id":"","num_messages":0}`
+		replay := domain.SubscriptionReplay{
+			SubscriptionID: "my-subscription",
+			From:           time.Date(2023, 8, 17, 0, 0, 0, 0, time.UTC),
+			To:             time.Date(2023, 8, 18, 0, 0, 0, 0, time.UTC),
+		}
+		tc := makeTestContext(t)
+		reqRec := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/v1/subscriptions/my-subscription/replay", bytes.NewBuffer([]byte(`{"from":"2023-08-17T00:00:00Z","to":"2023-08-18T00:00:00Z"}`)))
+
+		tc.subscriptionService.On("Replay", mock.Anything, &replay).Return(nil)
+		tc.router.ServeHTTP(reqRec, req)
+
+		assert.Equal(t, http.StatusOK, reqRec.Code)
+		assert.Equal(t, expectedPayload, reqRec.Body.String())
+	})
 }
@@ -1,8 +1,10 @@
 package http
 
 import (
+	"io"
 	"log/slog"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -10,15 +12,37 @@ import (
 	"github.com/allisson/psqlqueue/domain"
 )
 
+// nolint:unused
+type topicIngestionRequest struct {
+	HeaderAttributes map[string]string `json:"header_attributes" validate:"optional"`
+	LabelHeader      *string           `json:"label_header" example:"X-GitHub-Event" validate:"optional"`
+	SignatureHeader  *string           `json:"signature_header" example:"X-Hub-Signature-256" validate:"optional"`
+	SignaturePrefix  string            `json:"signature_prefix" example:"sha256=" validate:"optional"`
+	SignatureSecret  *string           `json:"signature_secret" example:"my-secret" validate:"optional"`
+} //@name TopicIngestionRequest
+
 // nolint:unused
 type topicRequest struct {
-	ID string `json:"id" example:"my-new-topic" validate:"required"`
+	ID                      string                 `json:"id" example:"my-new-topic" validate:"required"`
+	MessageRetentionSeconds int                    `json:"message_retention_seconds" example:"604800" validate:"optional"`
+	Ingestion               *topicIngestionRequest `json:"ingestion" validate:"optional"`
 } //@name TopicRequest
 
+// nolint:unused
+type topicIngestionResponse struct {
+	HeaderAttributes map[string]string `json:"header_attributes"`
+	LabelHeader      *string           `json:"label_header" example:"X-GitHub-Event"`
+	SignatureHeader  *string           `json:"signature_header" example:"X-Hub-Signature-256"`
+	SignaturePrefix  string            `json:"signature_prefix" example:"sha256="`
+	SignatureSecret  *string           `json:"signature_secret" example:"********"`
+} //@name TopicIngestionResponse
+
 // nolint:unused
 type topicResponse struct {
-	ID        string    `json:"id" example:"my-new-topic"`
-	CreatedAt time.Time `json:"created_at" example:"2023-08-17T00:00:00Z"`
+	ID                      string                  `json:"id" example:"my-new-topic"`
+	MessageRetentionSeconds int                     `json:"message_retention_seconds" example:"604800"`
+	Ingestion               *topicIngestionResponse `json:"ingestion"`
+	CreatedAt               time.Time               `json:"created_at" example:"2023-08-17T00:00:00Z"`
 } //@name TopicResponse
 
 // nolint:unused
@@ -28,6 +52,74 @@ type topicListResponse struct {
 	Limit  int              `json:"limit" example:"10"`
 } //@name QueueListResponse
 
+// nolint:unused
+type topicMessageRequest struct {
+	RequireMatch bool `form:"require_match" validate:"optional"`
+} //@name TopicMessageRequest
+
+// nolint:unused
+type topicPublishResponse struct {
+	ID                      string   `json:"id" example:"01HK651Q52EZMPKBYZGVK0ZX8S"`
+	TopicID                 string   `json:"topic_id" example:"my-new-topic"`
+	NumMatchedSubscriptions int      `json:"num_matched_subscriptions" example:"1"`
+	MessageIDs              []string `json:"message_ids" example:"01HK651Q52EZMPKBYZGVK0ZX8T"`
+} //@name TopicPublishResponse
+
+// nolint:unused
+type subscriptionMatchResponse struct {
+	SubscriptionID    string  `json:"subscription_id" example:"my-new-subscription"`
+	QueueID           *string `json:"queue_id" example:"my-new-queue"`
+	TargetTopicID     *string `json:"target_topic_id" example:"my-other-topic"`
+	Matched           bool    `json:"matched" example:"false"`
+	RejectedFilterKey *string `json:"rejected_filter_key" example:"status"`
+} //@name SubscriptionMatchResponse
+
+// nolint:unused
+type topicSimulationResponse struct {
+	TopicID                 string                       `json:"topic_id" example:"my-new-topic"`
+	NumMatchedSubscriptions int                          `json:"num_matched_subscriptions" example:"0"`
+	Subscriptions           []*subscriptionMatchResponse `json:"subscriptions"`
+} //@name TopicSimulationResponse
+
+// nolint:unused
+type subscriptionStatsResponse struct {
+	SubscriptionID       string `json:"subscription_id" example:"my-new-subscription"`
+	NumDeliveredMessages int    `json:"num_delivered_messages" example:"1"`
+	NumFilteredMessages  int    `json:"num_filtered_messages" example:"1"`
+} //@name SubscriptionStatsResponse
+
+// nolint:unused
+type topicStatsWindowResponse struct {
+	WindowSeconds        int                          `json:"window_seconds" example:"300"`
+	NumPublishedMessages int                          `json:"num_published_messages" example:"2"`
+	Subscriptions        []*subscriptionStatsResponse `json:"subscriptions"`
+} //@name TopicStatsWindowResponse
+
+// nolint:unused
+type topicStatsResponse struct {
+	TopicID string                      `json:"topic_id" example:"my-new-topic"`
+	Windows []*topicStatsWindowResponse `json:"windows"`
+} //@name TopicStatsResponse
+
+// nolint:unused
+type topicPublishMessageResponse struct {
+	ID                   string    `json:"id" example:"01HK651Q52EZMPKBYZGVK0ZX8T"`
+	QueueID              string    `json:"queue_id" example:"my-new-queue"`
+	SourceTopicID        *string   `json:"source_topic_id" example:"my-new-topic"`
+	SourceSubscriptionID *string   `json:"source_subscription_id" example:"my-new-subscription"`
+	DeliveryAttempts     int       `json:"delivery_attempts" example:"1"`
+	Status               string    `json:"status" example:"in_flight" enums:"scheduled,available,in_flight,acked,expired"`
+	CreatedAt            time.Time `json:"created_at" example:"2023-08-17T00:00:00Z"`
+	UpdatedAt            time.Time `json:"updated_at" example:"2023-08-17T00:00:00Z"`
+} //@name TopicPublishMessageResponse
+
+// nolint:unused
+type topicPublishLineageResponse struct {
+	ID       string                         `json:"id" example:"01HK651Q52EZMPKBYZGVK0ZX8S"`
+	TopicID  string                         `json:"topic_id" example:"my-new-topic"`
+	Messages []*topicPublishMessageResponse `json:"messages"`
+} //@name TopicPublishLineageResponse
+
 // Topic exposes a REST API for domain.TopicService.
 type TopicHandler struct {
 	topicService domain.TopicService
@@ -60,7 +152,7 @@ func (t *TopicHandler) Create(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusCreated, &topic)
+	c.JSON(http.StatusCreated, redactTopic(&topic))
 }
 
 // Get a topic.
@@ -84,7 +176,7 @@ func (t *TopicHandler) Get(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, &topic)
+	c.JSON(http.StatusOK, redactTopic(topic))
 }
 
 // List topics.
@@ -108,6 +200,10 @@ func (t *TopicHandler) List(c *gin.Context) {
 		return
 	}
 
+	for i := range topics {
+		topics[i] = redactTopic(topics[i])
+	}
+
 	response := listResponse{Data: topics, Offset: request.Offset, Limit: request.Limit}
 
 	c.JSON(http.StatusOK, response)
@@ -140,31 +236,198 @@ func (t *TopicHandler) Delete(c *gin.Context) {
 //
 //	@Summary	Add a message
 //	@Tags		topics
-//	@Accept		json
+//	@Accept		json,application/cloudevents+json
 //	@Produce	json
-//	@Param		request	body		messageRequest	true	"Add a message"
-//	@Success	201		{object}	topicResponse
-//	@Failure	400		{object}	errorResponse
-//	@Failure	500		{object}	errorResponse
+//	@Param		topic_id		path		string			true	"Topic id"
+//	@Param		require_match	query		bool			false	"Fail when no subscription matches the message"
+//	@Param		request			body		messageRequest	true	"Add a message"
+//	@Success	201				{object}	topicPublishResponse
+//	@Failure	400				{object}	errorResponse
+//	@Failure	404				{object}	errorResponse
+//	@Failure	422				{object}	errorResponse
+//	@Failure	500				{object}	errorResponse
 //	@Router		/topics/{topic_id}/messages [post]
 func (t *TopicHandler) CreateMessage(c *gin.Context) {
 	message := domain.Message{}
 	id := c.Param("topic_id")
 
-	if err := c.ShouldBindJSON(&message); err != nil {
+	if er := bindMessage(c, &message); er != nil {
+		c.JSON(er.StatusCode, er)
+		return
+	}
+
+	request := topicMessageRequest{}
+	if err := c.ShouldBindQuery(&request); err != nil {
+		slog.Warn("topic message request error", "error", err)
+	}
+
+	publish, err := t.topicService.CreateMessage(c.Request.Context(), id, &message, request.RequireMatch)
+	if err != nil {
+		er := parseServiceError("topicService", "CreateMessage", err)
+		c.JSON(er.StatusCode, &er)
+		return
+	}
+
+	c.JSON(http.StatusCreated, &publish)
+}
+
+// Simulate the routing of a message.
+//
+//	@Summary	Simulate the routing of a message without enqueueing it
+//	@Tags		topics
+//	@Accept		json,application/cloudevents+json
+//	@Produce	json
+//	@Param		topic_id	path		string			true	"Topic id"
+//	@Param		request		body		messageRequest	true	"Simulate a message"
+//	@Success	200			{object}	topicSimulationResponse
+//	@Failure	400			{object}	errorResponse
+//	@Failure	404			{object}	errorResponse
+//	@Failure	500			{object}	errorResponse
+//	@Router		/topics/{topic_id}/simulate [post]
+func (t *TopicHandler) Simulate(c *gin.Context) {
+	message := domain.Message{}
+	id := c.Param("topic_id")
+
+	if er := bindMessage(c, &message); er != nil {
+		c.JSON(er.StatusCode, er)
+		return
+	}
+
+	simulation, err := t.topicService.Simulate(c.Request.Context(), id, &message)
+	if err != nil {
+		er := parseServiceError("topicService", "Simulate", err)
+		c.JSON(er.StatusCode, &er)
+		return
+	}
+
+	c.JSON(http.StatusOK, &simulation)
+}
+
+// Cleanup a topic.
+//
+//	@Summary	Cleanup a topic removing expired messages from the message log
+//	@Tags		topics
+//	@Accept		json
+//	@Produce	json
+//	@Param		topic_id	path	string	true	"Topic id"
+//	@Success	204			"No Content"
+//	@Failure	404			{object}	errorResponse
+//	@Failure	500			{object}	errorResponse
+//	@Router		/topics/{topic_id}/cleanup [put]
+func (t *TopicHandler) Cleanup(c *gin.Context) {
+	id := c.Param("topic_id")
+
+	if err := t.topicService.Cleanup(c.Request.Context(), id); err != nil {
+		er := parseServiceError("topicService", "Cleanup", err)
+		c.JSON(er.StatusCode, &er)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Get the topic stats.
+//
+//	@Summary	Get the topic stats
+//	@Tags		topics
+//	@Accept		json
+//	@Produce	json
+//	@Param		topic_id	path		string	true	"Topic id"
+//	@Success	200			{object}	topicStatsResponse
+//	@Failure	404			{object}	errorResponse
+//	@Failure	500			{object}	errorResponse
+//	@Router		/topics/{topic_id}/stats [get]
+func (t *TopicHandler) Stats(c *gin.Context) {
+	id := c.Param("topic_id")
+
+	stats, err := t.topicService.Stats(c.Request.Context(), id)
+	if err != nil {
+		er := parseServiceError("topicService", "Stats", err)
+		c.JSON(er.StatusCode, &er)
+		return
+	}
+
+	c.JSON(http.StatusOK, &stats)
+}
+
+// Get the messages of a topic publish.
+//
+//	@Summary	Show the queue messages created by a topic publish with their delivery status
+//	@Tags		topics
+//	@Accept		json
+//	@Produce	json
+//	@Param		topic_id	path		string	true	"Topic id"
+//	@Param		publish_id	path		string	true	"Publish id"
+//	@Success	200			{object}	topicPublishLineageResponse
+//	@Failure	404			{object}	errorResponse
+//	@Failure	500			{object}	errorResponse
+//	@Router		/topics/{topic_id}/messages/{publish_id} [get]
+func (t *TopicHandler) GetPublish(c *gin.Context) {
+	topicID := c.Param("topic_id")
+	publishID := c.Param("publish_id")
+
+	lineage, err := t.topicService.GetPublish(c.Request.Context(), topicID, publishID)
+	if err != nil {
+		er := parseServiceError("topicService", "GetPublish", err)
+		c.JSON(er.StatusCode, &er)
+		return
+	}
+
+	c.JSON(http.StatusOK, &lineage)
+}
+
+// Ingest a raw payload.
+//
+//	@Summary	Add a message from a raw payload, mapping the configured headers to the message attributes
+//	@Tags		topics
+//	@Accept		*/*
+//	@Produce	json
+//	@Param		topic_id		path		string	true	"Topic id"
+//	@Param		require_match	query		bool	false	"Fail when no subscription matches the message"
+//	@Param		request			body		string	true	"Raw payload"
+//	@Success	201				{object}	topicPublishResponse
+//	@Failure	400				{object}	errorResponse
+//	@Failure	401				{object}	errorResponse
+//	@Failure	404				{object}	errorResponse
+//	@Failure	422				{object}	errorResponse
+//	@Failure	500				{object}	errorResponse
+//	@Router		/topics/{topic_id}/ingest [post]
+func (t *TopicHandler) Ingest(c *gin.Context) {
+	id := c.Param("topic_id")
+
+	payload, err := io.ReadAll(c.Request.Body)
+	if err != nil {
 		slog.Error("malformed request", "error", err.Error())
 		er := errorResponses["malformed_request"]
 		c.JSON(er.StatusCode, &er)
 		return
 	}
 
-	if err := t.topicService.CreateMessage(c.Request.Context(), id, &message); err != nil {
-		er := parseServiceError("topicService", "CreateMessage", err)
+	headers := make(map[string]string, len(c.Request.Header))
+	for key := range c.Request.Header {
+		headers[strings.ToLower(key)] = c.Request.Header.Get(key)
+	}
+
+	request := topicMessageRequest{}
+	if err := c.ShouldBindQuery(&request); err != nil {
+		slog.Warn("topic ingest request error", "error", err)
+	}
+
+	publish, err := t.topicService.Ingest(c.Request.Context(), id, payload, headers, request.RequireMatch)
+	if err != nil {
+		er := parseServiceError("topicService", "Ingest", err)
 		c.JSON(er.StatusCode, &er)
 		return
 	}
 
-	c.Status(http.StatusNoContent)
+	c.JSON(http.StatusCreated, &publish)
+}
+
+// redactTopic returns a copy of the topic without the ingestion signature secret.
+func redactTopic(topic *domain.Topic) *domain.Topic {
+	redacted := *topic
+	redacted.Ingestion = topic.Ingestion.Redacted()
+	return &redacted
 }
 
 // NewTopicHandler returns a new TopicHandler.
@@ -42,7 +42,7 @@ func TestTopicHandler(t *testing.T) {
 	})
 
 	t.Run("Create", func(t *testing.T) {
-		expectedPayload := `{"id":"my-topic","created_at":"0001-01-01T00:00:00Z"}`
+		expectedPayload := `{"id":"my-topic","message_retention_seconds":0,"ingestion":null,"created_at":"0001-01-01T00:00:00Z"}`
 		topic := domain.Topic{ID: "my-topic"}
 		jsonTopic, _ := json.Marshal(&topic)
 		tc := makeTestContext(t)
@@ -70,7 +70,7 @@ func TestTopicHandler(t *testing.T) {
 	})
 
 	t.Run("Get", func(t *testing.T) {
-		expectedPayload := `{"id":"my-topic","created_at":"0001-01-01T00:00:00Z"}`
+		expectedPayload := `{"id":"my-topic","message_retention_seconds":0,"ingestion":null,"created_at":"0001-01-01T00:00:00Z"}`
 		topic := domain.Topic{ID: "my-topic"}
 		tc := makeTestContext(t)
 		reqRec := httptest.NewRecorder()
@@ -83,8 +83,22 @@ func TestTopicHandler(t *testing.T) {
 		assert.Equal(t, expectedPayload, reqRec.Body.String())
 	})
 
+	t.Run("Get with ingestion", func(t *testing.T) {
+		expectedPayload := `{"id":"my-topic","message_retention_seconds":0,"ingestion":{"header_attributes":null,"label_header":null,"signature_header":"X-Signature","signature_prefix":"","signature_secret":"********"},"created_at":"0001-01-01T00:00:00Z"}`
+		topic := domain.Topic{ID: "my-topic", Ingestion: &domain.TopicIngestion{SignatureHeader: pointString("X-Signature"), SignatureSecret: pointString("my-secret")}}
+		tc := makeTestContext(t)
+		reqRec := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/v1/topics/my-topic", nil)
+
+		tc.topicService.On("Get", mock.Anything, topic.ID).Return(&topic, nil)
+		tc.router.ServeHTTP(reqRec, req)
+
+		assert.Equal(t, http.StatusOK, reqRec.Code)
+		assert.Equal(t, expectedPayload, reqRec.Body.String())
+	})
+
 	t.Run("List", func(t *testing.T) {
-		expectedPayload := `{"data":[{"id":"my-topic-1","created_at":"0001-01-01T00:00:00Z"},{"id":"my-topic-2","created_at":"0001-01-01T00:00:00Z"}],"limit":1}`
+		expectedPayload := `{"data":[{"id":"my-topic-1","message_retention_seconds":0,"ingestion":null,"created_at":"0001-01-01T00:00:00Z"},{"id":"my-topic-2","message_retention_seconds":0,"ingestion":null,"created_at":"0001-01-01T00:00:00Z"}],"limit":1}`
 		topic1 := domain.Topic{ID: "my-topic-1"}
 		topic2 := domain.Topic{ID: "my-topic-2"}
 		tc := makeTestContext(t)
@@ -123,15 +137,177 @@ func TestTopicHandler(t *testing.T) {
 	})
 
 	t.Run("CreateMessage", func(t *testing.T) {
+		expectedPayload := `{"id":"my-publish","topic_id":"my-topic","num_matched_subscriptions":1,"message_ids":["my-message"]}`
 		message := domain.Message{QueueID: "my-queue", Body: `{"message": true}`}
+		publish := domain.TopicPublish{ID: "my-publish", TopicID: "my-topic", NumMatchedSubscriptions: 1, MessageIDs: []string{"my-message"}}
 		jsonMessage, _ := json.Marshal(&message)
 		tc := makeTestContext(t)
 		reqRec := httptest.NewRecorder()
 		req, _ := http.NewRequest("POST", "/v1/topics/my-topic/messages", bytes.NewBuffer(jsonMessage))
 
-		tc.topicService.On("CreateMessage", mock.Anything, "my-topic", &message).Return(nil)
+		tc.topicService.On("CreateMessage", mock.Anything, "my-topic", &message, false).Return(&publish, nil)
+		tc.router.ServeHTTP(reqRec, req)
+
+		assert.Equal(t, http.StatusCreated, reqRec.Code)
+		assert.Equal(t, expectedPayload, reqRec.Body.String())
+	})
+
+	t.Run("CreateMessage with CloudEvent", func(t *testing.T) {
+		expectedPayload := `{"id":"my-publish","topic_id":"my-topic","num_matched_subscriptions":1,"message_ids":["my-message"]}`
+		message := domain.Message{
+			Label: pointString("order.created"),
+			Body:  `{"id":1}`,
+			Attributes: map[string]string{
+				"ce_id":          "A234",
+				"ce_source":      "/orders",
+				"ce_type":        "order.created",
+				"ce_specversion": "1.0",
+			},
+		}
+		publish := domain.TopicPublish{ID: "my-publish", TopicID: "my-topic", NumMatchedSubscriptions: 1, MessageIDs: []string{"my-message"}}
+		tc := makeTestContext(t)
+		reqRec := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/v1/topics/my-topic/messages", bytes.NewBuffer([]byte(`{"specversion":"1.0","id":"A234","source":"/orders","type":"order.created","data":{"id":1}}`)))
+		req.Header.Set("Content-Type", "application/cloudevents+json")
+
+		tc.topicService.On("CreateMessage", mock.Anything, "my-topic", &message, false).Return(&publish, nil)
+		tc.router.ServeHTTP(reqRec, req)
+
+		assert.Equal(t, http.StatusCreated, reqRec.Code)
+		assert.Equal(t, expectedPayload, reqRec.Body.String())
+	})
+
+	t.Run("CreateMessage with require match", func(t *testing.T) {
+		expectedPayload := `{"code":11,"message":"no subscription matched the message"}`
+		message := domain.Message{QueueID: "my-queue", Body: `{"message": true}`}
+		jsonMessage, _ := json.Marshal(&message)
+		tc := makeTestContext(t)
+		reqRec := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/v1/topics/my-topic/messages?require_match=true", bytes.NewBuffer(jsonMessage))
+
+		tc.topicService.On("CreateMessage", mock.Anything, "my-topic", &message, true).Return(nil, domain.ErrTopicNoMatchingSubscription)
+		tc.router.ServeHTTP(reqRec, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, reqRec.Code)
+		assert.Equal(t, expectedPayload, reqRec.Body.String())
+	})
+
+	t.Run("Ingest", func(t *testing.T) {
+		expectedPayload := `{"id":"my-publish","topic_id":"my-topic","num_matched_subscriptions":1,"message_ids":["my-message"]}`
+		publish := domain.TopicPublish{ID: "my-publish", TopicID: "my-topic", NumMatchedSubscriptions: 1, MessageIDs: []string{"my-message"}}
+		tc := makeTestContext(t)
+		reqRec := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/v1/topics/my-topic/ingest?require_match=true", bytes.NewBuffer([]byte(`{"action":"opened"}`)))
+		req.Header.Set("X-GitHub-Event", "pull_request")
+
+		tc.topicService.On("Ingest", mock.Anything, "my-topic", []byte(`{"action":"opened"}`), map[string]string{"x-github-event": "pull_request"}, true).Return(&publish, nil)
+		tc.router.ServeHTTP(reqRec, req)
+
+		assert.Equal(t, http.StatusCreated, reqRec.Code)
+		assert.Equal(t, expectedPayload, reqRec.Body.String())
+	})
+
+	t.Run("Ingest with invalid signature", func(t *testing.T) {
+		expectedPayload := `{"code":15,"message":"invalid ingestion signature"}`
+		tc := makeTestContext(t)
+		reqRec := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/v1/topics/my-topic/ingest", bytes.NewBuffer([]byte(`{"action":"opened"}`)))
+
+		tc.topicService.On("Ingest", mock.Anything, "my-topic", []byte(`{"action":"opened"}`), map[string]string{}, false).Return(nil, domain.ErrTopicIngestionInvalidSignature)
+		tc.router.ServeHTTP(reqRec, req)
+
+		assert.Equal(t, http.StatusUnauthorized, reqRec.Code)
+		assert.Equal(t, expectedPayload, reqRec.Body.String())
+	})
+
+	t.Run("Simulate", func(t *testing.T) {
+		expectedPayload := `{"topic_id":"my-topic","num_matched_subscriptions":1,"subscriptions":[{"subscription_id":"my-subscription-1","queue_id":"my-queue-1","target_topic_id":null,"matched":true,"rejected_filter_key":null},{"subscription_id":"my-subscription-2","queue_id":"my-queue-2","target_topic_id":null,"matched":false,"rejected_filter_key":"type"}]}`
+		message := domain.Message{Body: `{"message": true}`, Attributes: map[string]string{"type": "user"}}
+		rejectedFilterKey := "type"
+		simulation := domain.TopicSimulation{
+			TopicID:                 "my-topic",
+			NumMatchedSubscriptions: 1,
+			Subscriptions: []*domain.SubscriptionMatch{
+				{SubscriptionID: "my-subscription-1", QueueID: pointString("my-queue-1"), Matched: true},
+				{SubscriptionID: "my-subscription-2", QueueID: pointString("my-queue-2"), Matched: false, RejectedFilterKey: &rejectedFilterKey},
+			},
+		}
+		jsonMessage, _ := json.Marshal(&message)
+		tc := makeTestContext(t)
+		reqRec := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/v1/topics/my-topic/simulate", bytes.NewBuffer(jsonMessage))
+
+		tc.topicService.On("Simulate", mock.Anything, "my-topic", &message).Return(&simulation, nil)
+		tc.router.ServeHTTP(reqRec, req)
+
+		assert.Equal(t, http.StatusOK, reqRec.Code)
+		assert.Equal(t, expectedPayload, reqRec.Body.String())
+	})
+
+	t.Run("Cleanup", func(t *testing.T) {
+		tc := makeTestContext(t)
+		reqRec := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/v1/topics/my-topic/cleanup", nil)
+
+		tc.topicService.On("Cleanup", mock.Anything, "my-topic").Return(nil)
 		tc.router.ServeHTTP(reqRec, req)
 
 		assert.Equal(t, http.StatusNoContent, reqRec.Code)
 	})
+
+	t.Run("Stats", func(t *testing.T) {
+		expectedPayload := `{"topic_id":"my-topic","windows":[{"window_seconds":300,"num_published_messages":2,"subscriptions":[{"subscription_id":"my-subscription","num_delivered_messages":1,"num_filtered_messages":1}]}]}`
+		stats := domain.TopicStats{
+			TopicID: "my-topic",
+			Windows: []*domain.TopicStatsWindow{
+				{
+					WindowSeconds:        300,
+					NumPublishedMessages: 2,
+					Subscriptions:        []*domain.SubscriptionStats{{SubscriptionID: "my-subscription", NumDeliveredMessages: 1, NumFilteredMessages: 1}},
+				},
+			},
+		}
+		tc := makeTestContext(t)
+		reqRec := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/v1/topics/my-topic/stats", nil)
+
+		tc.topicService.On("Stats", mock.Anything, "my-topic").Return(&stats, nil)
+		tc.router.ServeHTTP(reqRec, req)
+
+		assert.Equal(t, http.StatusOK, reqRec.Code)
+		assert.Equal(t, expectedPayload, reqRec.Body.String())
+	})
+
+	t.Run("GetPublish", func(t *testing.T) {
+		expectedPayload := `{"id":"my-publish","topic_id":"my-topic","messages":[{"id":"my-message","queue_id":"my-queue","source_topic_id":"my-topic","source_subscription_id":"my-subscription","delivery_attempts":1,"status":"acked","created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"}]}`
+		lineage := domain.TopicPublishLineage{
+			ID:      "my-publish",
+			TopicID: "my-topic",
+			Messages: []*domain.TopicPublishMessage{
+				{ID: "my-message", QueueID: "my-queue", SourceTopicID: pointString("my-topic"), SourceSubscriptionID: pointString("my-subscription"), DeliveryAttempts: 1, Status: domain.MessageStatusAcked},
+			},
+		}
+		tc := makeTestContext(t)
+		reqRec := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/v1/topics/my-topic/messages/my-publish", nil)
+
+		tc.topicService.On("GetPublish", mock.Anything, "my-topic", "my-publish").Return(&lineage, nil)
+		tc.router.ServeHTTP(reqRec, req)
+
+		assert.Equal(t, http.StatusOK, reqRec.Code)
+		assert.Equal(t, expectedPayload, reqRec.Body.String())
+	})
+
+	t.Run("GetPublish with object not found", func(t *testing.T) {
+		expectedPayload := `{"code":14,"message":"topic publish not found"}`
+		tc := makeTestContext(t)
+		reqRec := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/v1/topics/my-topic/messages/my-publish", nil)
+
+		tc.topicService.On("GetPublish", mock.Anything, "my-topic", "my-publish").Return(nil, domain.ErrTopicPublishNotFound)
+		tc.router.ServeHTTP(reqRec, req)
+
+		assert.Equal(t, http.StatusNotFound, reqRec.Code)
+		assert.Equal(t, expectedPayload, reqRec.Body.String())
+	})
 }
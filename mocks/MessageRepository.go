@@ -68,6 +68,24 @@ func (_m *MessageRepository) CreateMany(ctx context.Context, messages []*domain.
 	return r0
 }
 
+// CreateOrCoalesce provides a mock function with given fields: ctx, message
+func (_m *MessageRepository) CreateOrCoalesce(ctx context.Context, message *domain.Message) error {
+	ret := _m.Called(ctx, message)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateOrCoalesce")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Message) error); ok {
+		r0 = rf(ctx, message)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Get provides a mock function with given fields: ctx, id
 func (_m *MessageRepository) Get(ctx context.Context, id string) (*domain.Message, error) {
 	ret := _m.Called(ctx, id)
@@ -98,9 +116,9 @@ func (_m *MessageRepository) Get(ctx context.Context, id string) (*domain.Messag
 	return r0, r1
 }
 
-// List provides a mock function with given fields: ctx, queue, label, limit
-func (_m *MessageRepository) List(ctx context.Context, queue *domain.Queue, label *string, limit uint) ([]*domain.Message, error) {
-	ret := _m.Called(ctx, queue, label, limit)
+// List provides a mock function with given fields: ctx, queue, label, limit, visibilityTimeoutSeconds, ackMode, consumerID
+func (_m *MessageRepository) List(ctx context.Context, queue *domain.Queue, label *string, limit uint, visibilityTimeoutSeconds uint, ackMode string, consumerID *string) ([]*domain.Message, error) {
+	ret := _m.Called(ctx, queue, label, limit, visibilityTimeoutSeconds, ackMode, consumerID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for List")
@@ -108,19 +126,19 @@ func (_m *MessageRepository) List(ctx context.Context, queue *domain.Queue, labe
 
 	var r0 []*domain.Message
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *domain.Queue, *string, uint) ([]*domain.Message, error)); ok {
-		return rf(ctx, queue, label, limit)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Queue, *string, uint, uint, string, *string) ([]*domain.Message, error)); ok {
+		return rf(ctx, queue, label, limit, visibilityTimeoutSeconds, ackMode, consumerID)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, *domain.Queue, *string, uint) []*domain.Message); ok {
-		r0 = rf(ctx, queue, label, limit)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Queue, *string, uint, uint, string, *string) []*domain.Message); ok {
+		r0 = rf(ctx, queue, label, limit, visibilityTimeoutSeconds, ackMode, consumerID)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*domain.Message)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, *domain.Queue, *string, uint) error); ok {
-		r1 = rf(ctx, queue, label, limit)
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.Queue, *string, uint, uint, string, *string) error); ok {
+		r1 = rf(ctx, queue, label, limit, visibilityTimeoutSeconds, ackMode, consumerID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -128,17 +146,173 @@ func (_m *MessageRepository) List(ctx context.Context, queue *domain.Queue, labe
 	return r0, r1
 }
 
-// Nack provides a mock function with given fields: ctx, id, visibilityTimeoutSeconds
-func (_m *MessageRepository) Nack(ctx context.Context, id string, visibilityTimeoutSeconds uint) error {
-	ret := _m.Called(ctx, id, visibilityTimeoutSeconds)
+// ListByPublish provides a mock function with given fields: ctx, topicID, publishID, offset, limit
+func (_m *MessageRepository) ListByPublish(ctx context.Context, topicID string, publishID string, offset uint, limit uint) ([]*domain.Message, error) {
+	ret := _m.Called(ctx, topicID, publishID, offset, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListByPublish")
+	}
+
+	var r0 []*domain.Message
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, uint, uint) ([]*domain.Message, error)); ok {
+		return rf(ctx, topicID, publishID, offset, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, uint, uint) []*domain.Message); ok {
+		r0 = rf(ctx, topicID, publishID, offset, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Message)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, uint, uint) error); ok {
+		r1 = rf(ctx, topicID, publishID, offset, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListInFlight provides a mock function with given fields: ctx, queueID, offset, limit
+func (_m *MessageRepository) ListInFlight(ctx context.Context, queueID string, offset uint, limit uint) ([]*domain.Message, error) {
+	ret := _m.Called(ctx, queueID, offset, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListInFlight")
+	}
+
+	var r0 []*domain.Message
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint, uint) ([]*domain.Message, error)); ok {
+		return rf(ctx, queueID, offset, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint, uint) []*domain.Message); ok {
+		r0 = rf(ctx, queueID, offset, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Message)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, uint, uint) error); ok {
+		r1 = rf(ctx, queueID, offset, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Nack provides a mock function with given fields: ctx, id, visibilityTimeoutSeconds, messageError
+func (_m *MessageRepository) Nack(ctx context.Context, id string, visibilityTimeoutSeconds uint, messageError *domain.MessageError) error {
+	ret := _m.Called(ctx, id, visibilityTimeoutSeconds, messageError)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Nack")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, uint) error); ok {
-		r0 = rf(ctx, id, visibilityTimeoutSeconds)
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint, *domain.MessageError) error); ok {
+		r0 = rf(ctx, id, visibilityTimeoutSeconds, messageError)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReceiveReply provides a mock function with given fields: ctx, queueID, correlationID
+func (_m *MessageRepository) ReceiveReply(ctx context.Context, queueID string, correlationID string) (*domain.Message, error) {
+	ret := _m.Called(ctx, queueID, correlationID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReceiveReply")
+	}
+
+	var r0 *domain.Message
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*domain.Message, error)); ok {
+		return rf(ctx, queueID, correlationID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *domain.Message); ok {
+		r0 = rf(ctx, queueID, correlationID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Message)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, queueID, correlationID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReleaseConsumer provides a mock function with given fields: ctx, queueID, consumerID
+func (_m *MessageRepository) ReleaseConsumer(ctx context.Context, queueID string, consumerID string) ([]*domain.Message, error) {
+	ret := _m.Called(ctx, queueID, consumerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseConsumer")
+	}
+
+	var r0 []*domain.Message
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) ([]*domain.Message, error)); ok {
+		return rf(ctx, queueID, consumerID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []*domain.Message); ok {
+		r0 = rf(ctx, queueID, consumerID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Message)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, queueID, consumerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateProgress provides a mock function with given fields: ctx, message
+func (_m *MessageRepository) UpdateProgress(ctx context.Context, message *domain.Message) error {
+	ret := _m.Called(ctx, message)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateProgress")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Message) error); ok {
+		r0 = rf(ctx, message)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateWithEvent provides a mock function with given fields: ctx, message, eventType
+func (_m *MessageRepository) UpdateWithEvent(ctx context.Context, message *domain.Message, eventType string) error {
+	ret := _m.Called(ctx, message, eventType)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateWithEvent")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Message, string) error); ok {
+		r0 = rf(ctx, message, eventType)
 	} else {
 		r0 = ret.Error(0)
 	}
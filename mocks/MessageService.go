@@ -4,6 +4,7 @@ package mocks
 
 import (
 	context "context"
+	time "time"
 
 	domain "github.com/allisson/psqlqueue/domain"
 	mock "github.com/stretchr/testify/mock"
@@ -14,17 +15,17 @@ type MessageService struct {
 	mock.Mock
 }
 
-// Ack provides a mock function with given fields: ctx, id
-func (_m *MessageService) Ack(ctx context.Context, id string) error {
-	ret := _m.Called(ctx, id)
+// Ack provides a mock function with given fields: ctx, id, result
+func (_m *MessageService) Ack(ctx context.Context, id string, result *domain.MessageResult) error {
+	ret := _m.Called(ctx, id, result)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Ack")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.MessageResult) error); ok {
+		r0 = rf(ctx, id, result)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -50,29 +51,163 @@ func (_m *MessageService) Create(ctx context.Context, message *domain.Message) e
 	return r0
 }
 
-// List provides a mock function with given fields: ctx, queueID, label, limit
-func (_m *MessageService) List(ctx context.Context, queueID string, label *string, limit uint) ([]*domain.Message, error) {
-	ret := _m.Called(ctx, queueID, label, limit)
+// GetJob provides a mock function with given fields: ctx, queueID, id
+func (_m *MessageService) GetJob(ctx context.Context, queueID string, id string) (*domain.MessageJob, error) {
+	ret := _m.Called(ctx, queueID, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetJob")
+	}
+
+	var r0 *domain.MessageJob
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*domain.MessageJob, error)); ok {
+		return rf(ctx, queueID, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *domain.MessageJob); ok {
+		r0 = rf(ctx, queueID, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.MessageJob)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, queueID, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with given fields: ctx, queueID, label, limit, visibilityTimeoutSeconds, ackMode, consumerID
+func (_m *MessageService) List(ctx context.Context, queueID string, label *string, limit uint, visibilityTimeoutSeconds uint, ackMode string, consumerID *string) ([]*domain.Message, time.Duration, error) {
+	ret := _m.Called(ctx, queueID, label, limit, visibilityTimeoutSeconds, ackMode, consumerID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for List")
 	}
 
 	var r0 []*domain.Message
+	var r1 time.Duration
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *string, uint, uint, string, *string) ([]*domain.Message, time.Duration, error)); ok {
+		return rf(ctx, queueID, label, limit, visibilityTimeoutSeconds, ackMode, consumerID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *string, uint, uint, string, *string) []*domain.Message); ok {
+		r0 = rf(ctx, queueID, label, limit, visibilityTimeoutSeconds, ackMode, consumerID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Message)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *string, uint, uint, string, *string) time.Duration); ok {
+		r1 = rf(ctx, queueID, label, limit, visibilityTimeoutSeconds, ackMode, consumerID)
+	} else {
+		r1 = ret.Get(1).(time.Duration)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, *string, uint, uint, string, *string) error); ok {
+		r2 = rf(ctx, queueID, label, limit, visibilityTimeoutSeconds, ackMode, consumerID)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// ListEvents provides a mock function with given fields: ctx, queueID, id, offset, limit
+func (_m *MessageService) ListEvents(ctx context.Context, queueID string, id string, offset uint, limit uint) ([]*domain.MessageEvent, error) {
+	ret := _m.Called(ctx, queueID, id, offset, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListEvents")
+	}
+
+	var r0 []*domain.MessageEvent
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, *string, uint) ([]*domain.Message, error)); ok {
-		return rf(ctx, queueID, label, limit)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, uint, uint) ([]*domain.MessageEvent, error)); ok {
+		return rf(ctx, queueID, id, offset, limit)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, *string, uint) []*domain.Message); ok {
-		r0 = rf(ctx, queueID, label, limit)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, uint, uint) []*domain.MessageEvent); ok {
+		r0 = rf(ctx, queueID, id, offset, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.MessageEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, uint, uint) error); ok {
+		r1 = rf(ctx, queueID, id, offset, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListFromQueues provides a mock function with given fields: ctx, queueIDs, label, limit, visibilityTimeoutSeconds, ackMode, consumerID
+func (_m *MessageService) ListFromQueues(ctx context.Context, queueIDs []string, label *string, limit uint, visibilityTimeoutSeconds uint, ackMode string, consumerID *string) ([]*domain.Message, time.Duration, error) {
+	ret := _m.Called(ctx, queueIDs, label, limit, visibilityTimeoutSeconds, ackMode, consumerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListFromQueues")
+	}
+
+	var r0 []*domain.Message
+	var r1 time.Duration
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string, *string, uint, uint, string, *string) ([]*domain.Message, time.Duration, error)); ok {
+		return rf(ctx, queueIDs, label, limit, visibilityTimeoutSeconds, ackMode, consumerID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string, *string, uint, uint, string, *string) []*domain.Message); ok {
+		r0 = rf(ctx, queueIDs, label, limit, visibilityTimeoutSeconds, ackMode, consumerID)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*domain.Message)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, *string, uint) error); ok {
-		r1 = rf(ctx, queueID, label, limit)
+	if rf, ok := ret.Get(1).(func(context.Context, []string, *string, uint, uint, string, *string) time.Duration); ok {
+		r1 = rf(ctx, queueIDs, label, limit, visibilityTimeoutSeconds, ackMode, consumerID)
+	} else {
+		r1 = ret.Get(1).(time.Duration)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, []string, *string, uint, uint, string, *string) error); ok {
+		r2 = rf(ctx, queueIDs, label, limit, visibilityTimeoutSeconds, ackMode, consumerID)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// ListInFlight provides a mock function with given fields: ctx, queueID, offset, limit
+func (_m *MessageService) ListInFlight(ctx context.Context, queueID string, offset uint, limit uint) ([]*domain.MessageLease, error) {
+	ret := _m.Called(ctx, queueID, offset, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListInFlight")
+	}
+
+	var r0 []*domain.MessageLease
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint, uint) ([]*domain.MessageLease, error)); ok {
+		return rf(ctx, queueID, offset, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint, uint) []*domain.MessageLease); ok {
+		r0 = rf(ctx, queueID, offset, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.MessageLease)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, uint, uint) error); ok {
+		r1 = rf(ctx, queueID, offset, limit)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -80,17 +215,53 @@ func (_m *MessageService) List(ctx context.Context, queueID string, label *strin
 	return r0, r1
 }
 
-// Nack provides a mock function with given fields: ctx, id, visibilityTimeoutSeconds
-func (_m *MessageService) Nack(ctx context.Context, id string, visibilityTimeoutSeconds uint) error {
-	ret := _m.Called(ctx, id, visibilityTimeoutSeconds)
+// Nack provides a mock function with given fields: ctx, id, visibilityTimeoutSeconds, messageError
+func (_m *MessageService) Nack(ctx context.Context, id string, visibilityTimeoutSeconds uint, messageError *domain.MessageError) error {
+	ret := _m.Called(ctx, id, visibilityTimeoutSeconds, messageError)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Nack")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, uint) error); ok {
-		r0 = rf(ctx, id, visibilityTimeoutSeconds)
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint, *domain.MessageError) error); ok {
+		r0 = rf(ctx, id, visibilityTimeoutSeconds, messageError)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Progress provides a mock function with given fields: ctx, queueID, id, progress
+func (_m *MessageService) Progress(ctx context.Context, queueID string, id string, progress *domain.MessageProgress) error {
+	ret := _m.Called(ctx, queueID, id, progress)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Progress")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *domain.MessageProgress) error); ok {
+		r0 = rf(ctx, queueID, id, progress)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReleaseConsumer provides a mock function with given fields: ctx, queueID, consumerID
+func (_m *MessageService) ReleaseConsumer(ctx context.Context, queueID string, consumerID string) error {
+	ret := _m.Called(ctx, queueID, consumerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseConsumer")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, queueID, consumerID)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -98,6 +269,36 @@ func (_m *MessageService) Nack(ctx context.Context, id string, visibilityTimeout
 	return r0
 }
 
+// Request provides a mock function with given fields: ctx, message, timeout
+func (_m *MessageService) Request(ctx context.Context, message *domain.Message, timeout time.Duration) (*domain.Message, error) {
+	ret := _m.Called(ctx, message, timeout)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Request")
+	}
+
+	var r0 *domain.Message
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Message, time.Duration) (*domain.Message, error)); ok {
+		return rf(ctx, message, timeout)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Message, time.Duration) *domain.Message); ok {
+		r0 = rf(ctx, message, timeout)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Message)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.Message, time.Duration) error); ok {
+		r1 = rf(ctx, message, timeout)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewMessageService creates a new instance of MessageService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMessageService(t interface {
@@ -4,6 +4,7 @@ package mocks
 
 import (
 	context "context"
+	time "time"
 
 	domain "github.com/allisson/psqlqueue/domain"
 	mock "github.com/stretchr/testify/mock"
@@ -68,6 +69,24 @@ func (_m *QueueRepository) Delete(ctx context.Context, id string) error {
 	return r0
 }
 
+// DeleteExpired provides a mock function with given fields: ctx
+func (_m *QueueRepository) DeleteExpired(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteExpired")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Get provides a mock function with given fields: ctx, id
 func (_m *QueueRepository) Get(ctx context.Context, id string) (*domain.Queue, error) {
 	ret := _m.Called(ctx, id)
@@ -146,6 +165,42 @@ func (_m *QueueRepository) Purge(ctx context.Context, id string) error {
 	return r0
 }
 
+// ReturnDeliveries provides a mock function with given fields: ctx, queue, n
+func (_m *QueueRepository) ReturnDeliveries(ctx context.Context, queue *domain.Queue, n uint) error {
+	ret := _m.Called(ctx, queue, n)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReturnDeliveries")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Queue, uint) error); ok {
+		r0 = rf(ctx, queue, n)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Seek provides a mock function with given fields: ctx, queue, timestamp
+func (_m *QueueRepository) Seek(ctx context.Context, queue *domain.Queue, timestamp time.Time) error {
+	ret := _m.Called(ctx, queue, timestamp)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Seek")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Queue, time.Time) error); ok {
+		r0 = rf(ctx, queue, timestamp)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Stats provides a mock function with given fields: ctx, id
 func (_m *QueueRepository) Stats(ctx context.Context, id string) (*domain.QueueStats, error) {
 	ret := _m.Called(ctx, id)
@@ -176,6 +231,41 @@ func (_m *QueueRepository) Stats(ctx context.Context, id string) (*domain.QueueS
 	return r0, r1
 }
 
+// TakeDeliveries provides a mock function with given fields: ctx, queue, n
+func (_m *QueueRepository) TakeDeliveries(ctx context.Context, queue *domain.Queue, n uint) (uint, time.Duration, error) {
+	ret := _m.Called(ctx, queue, n)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TakeDeliveries")
+	}
+
+	var r0 uint
+	var r1 time.Duration
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Queue, uint) (uint, time.Duration, error)); ok {
+		return rf(ctx, queue, n)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Queue, uint) uint); ok {
+		r0 = rf(ctx, queue, n)
+	} else {
+		r0 = ret.Get(0).(uint)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.Queue, uint) time.Duration); ok {
+		r1 = rf(ctx, queue, n)
+	} else {
+		r1 = ret.Get(1).(time.Duration)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, *domain.Queue, uint) error); ok {
+		r2 = rf(ctx, queue, n)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // Update provides a mock function with given fields: ctx, queue
 func (_m *QueueRepository) Update(ctx context.Context, queue *domain.Queue) error {
 	ret := _m.Called(ctx, queue)
@@ -146,6 +146,24 @@ func (_m *QueueService) Purge(ctx context.Context, id string) error {
 	return r0
 }
 
+// Seek provides a mock function with given fields: ctx, id, seek
+func (_m *QueueService) Seek(ctx context.Context, id string, seek *domain.QueueSeek) error {
+	ret := _m.Called(ctx, id, seek)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Seek")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.QueueSeek) error); ok {
+		r0 = rf(ctx, id, seek)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Stats provides a mock function with given fields: ctx, id
 func (_m *QueueService) Stats(ctx context.Context, id string) (*domain.QueueStats, error) {
 	ret := _m.Called(ctx, id)
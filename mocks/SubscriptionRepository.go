@@ -110,6 +110,36 @@ func (_m *SubscriptionRepository) List(ctx context.Context, offset uint, limit u
 	return r0, r1
 }
 
+// ListByQueue provides a mock function with given fields: ctx, queueID, offset, limit
+func (_m *SubscriptionRepository) ListByQueue(ctx context.Context, queueID string, offset uint, limit uint) ([]*domain.Subscription, error) {
+	ret := _m.Called(ctx, queueID, offset, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListByQueue")
+	}
+
+	var r0 []*domain.Subscription
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint, uint) ([]*domain.Subscription, error)); ok {
+		return rf(ctx, queueID, offset, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint, uint) []*domain.Subscription); ok {
+		r0 = rf(ctx, queueID, offset, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Subscription)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, uint, uint) error); ok {
+		r1 = rf(ctx, queueID, offset, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ListByTopic provides a mock function with given fields: ctx, topicID, offset, limit
 func (_m *SubscriptionRepository) ListByTopic(ctx context.Context, topicID string, offset uint, limit uint) ([]*domain.Subscription, error) {
 	ret := _m.Called(ctx, topicID, offset, limit)
@@ -140,6 +170,24 @@ func (_m *SubscriptionRepository) ListByTopic(ctx context.Context, topicID strin
 	return r0, r1
 }
 
+// Update provides a mock function with given fields: ctx, subscription
+func (_m *SubscriptionRepository) Update(ctx context.Context, subscription *domain.Subscription) error {
+	ret := _m.Called(ctx, subscription)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Subscription) error); ok {
+		r0 = rf(ctx, subscription)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewSubscriptionRepository creates a new instance of SubscriptionRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewSubscriptionRepository(t interface {
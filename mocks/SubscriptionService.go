@@ -110,6 +110,102 @@ func (_m *SubscriptionService) List(ctx context.Context, offset uint, limit uint
 	return r0, r1
 }
 
+// ListByQueue provides a mock function with given fields: ctx, queueID, offset, limit
+func (_m *SubscriptionService) ListByQueue(ctx context.Context, queueID string, offset uint, limit uint) ([]*domain.Subscription, error) {
+	ret := _m.Called(ctx, queueID, offset, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListByQueue")
+	}
+
+	var r0 []*domain.Subscription
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint, uint) ([]*domain.Subscription, error)); ok {
+		return rf(ctx, queueID, offset, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint, uint) []*domain.Subscription); ok {
+		r0 = rf(ctx, queueID, offset, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Subscription)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, uint, uint) error); ok {
+		r1 = rf(ctx, queueID, offset, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListByTopic provides a mock function with given fields: ctx, topicID, offset, limit
+func (_m *SubscriptionService) ListByTopic(ctx context.Context, topicID string, offset uint, limit uint) ([]*domain.Subscription, error) {
+	ret := _m.Called(ctx, topicID, offset, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListByTopic")
+	}
+
+	var r0 []*domain.Subscription
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint, uint) ([]*domain.Subscription, error)); ok {
+		return rf(ctx, topicID, offset, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint, uint) []*domain.Subscription); ok {
+		r0 = rf(ctx, topicID, offset, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Subscription)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, uint, uint) error); ok {
+		r1 = rf(ctx, topicID, offset, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Replay provides a mock function with given fields: ctx, replay
+func (_m *SubscriptionService) Replay(ctx context.Context, replay *domain.SubscriptionReplay) error {
+	ret := _m.Called(ctx, replay)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Replay")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.SubscriptionReplay) error); ok {
+		r0 = rf(ctx, replay)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: ctx, subscription
+func (_m *SubscriptionService) Update(ctx context.Context, subscription *domain.Subscription) error {
+	ret := _m.Called(ctx, subscription)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Subscription) error); ok {
+		r0 = rf(ctx, subscription)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewSubscriptionService creates a new instance of SubscriptionService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewSubscriptionService(t interface {
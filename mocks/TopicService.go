@@ -14,6 +14,24 @@ type TopicService struct {
 	mock.Mock
 }
 
+// Cleanup provides a mock function with given fields: ctx, id
+func (_m *TopicService) Cleanup(ctx context.Context, id string) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Cleanup")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Create provides a mock function with given fields: ctx, topic
 func (_m *TopicService) Create(ctx context.Context, topic *domain.Topic) error {
 	ret := _m.Called(ctx, topic)
@@ -32,22 +50,34 @@ func (_m *TopicService) Create(ctx context.Context, topic *domain.Topic) error {
 	return r0
 }
 
-// CreateMessage provides a mock function with given fields: ctx, topicID, message
-func (_m *TopicService) CreateMessage(ctx context.Context, topicID string, message *domain.Message) error {
-	ret := _m.Called(ctx, topicID, message)
+// CreateMessage provides a mock function with given fields: ctx, topicID, message, requireMatch
+func (_m *TopicService) CreateMessage(ctx context.Context, topicID string, message *domain.Message, requireMatch bool) (*domain.TopicPublish, error) {
+	ret := _m.Called(ctx, topicID, message, requireMatch)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateMessage")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.Message) error); ok {
-		r0 = rf(ctx, topicID, message)
+	var r0 *domain.TopicPublish
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.Message, bool) (*domain.TopicPublish, error)); ok {
+		return rf(ctx, topicID, message, requireMatch)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.Message, bool) *domain.TopicPublish); ok {
+		r0 = rf(ctx, topicID, message, requireMatch)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TopicPublish)
+		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, string, *domain.Message, bool) error); ok {
+		r1 = rf(ctx, topicID, message, requireMatch)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // Delete provides a mock function with given fields: ctx, id
@@ -98,6 +128,66 @@ func (_m *TopicService) Get(ctx context.Context, id string) (*domain.Topic, erro
 	return r0, r1
 }
 
+// GetPublish provides a mock function with given fields: ctx, topicID, publishID
+func (_m *TopicService) GetPublish(ctx context.Context, topicID string, publishID string) (*domain.TopicPublishLineage, error) {
+	ret := _m.Called(ctx, topicID, publishID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPublish")
+	}
+
+	var r0 *domain.TopicPublishLineage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*domain.TopicPublishLineage, error)); ok {
+		return rf(ctx, topicID, publishID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *domain.TopicPublishLineage); ok {
+		r0 = rf(ctx, topicID, publishID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TopicPublishLineage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, topicID, publishID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Ingest provides a mock function with given fields: ctx, topicID, payload, headers, requireMatch
+func (_m *TopicService) Ingest(ctx context.Context, topicID string, payload []byte, headers map[string]string, requireMatch bool) (*domain.TopicPublish, error) {
+	ret := _m.Called(ctx, topicID, payload, headers, requireMatch)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Ingest")
+	}
+
+	var r0 *domain.TopicPublish
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []byte, map[string]string, bool) (*domain.TopicPublish, error)); ok {
+		return rf(ctx, topicID, payload, headers, requireMatch)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, []byte, map[string]string, bool) *domain.TopicPublish); ok {
+		r0 = rf(ctx, topicID, payload, headers, requireMatch)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TopicPublish)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, []byte, map[string]string, bool) error); ok {
+		r1 = rf(ctx, topicID, payload, headers, requireMatch)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // List provides a mock function with given fields: ctx, offset, limit
 func (_m *TopicService) List(ctx context.Context, offset uint, limit uint) ([]*domain.Topic, error) {
 	ret := _m.Called(ctx, offset, limit)
@@ -128,6 +218,66 @@ func (_m *TopicService) List(ctx context.Context, offset uint, limit uint) ([]*d
 	return r0, r1
 }
 
+// Simulate provides a mock function with given fields: ctx, topicID, message
+func (_m *TopicService) Simulate(ctx context.Context, topicID string, message *domain.Message) (*domain.TopicSimulation, error) {
+	ret := _m.Called(ctx, topicID, message)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Simulate")
+	}
+
+	var r0 *domain.TopicSimulation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.Message) (*domain.TopicSimulation, error)); ok {
+		return rf(ctx, topicID, message)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.Message) *domain.TopicSimulation); ok {
+		r0 = rf(ctx, topicID, message)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TopicSimulation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *domain.Message) error); ok {
+		r1 = rf(ctx, topicID, message)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Stats provides a mock function with given fields: ctx, id
+func (_m *TopicService) Stats(ctx context.Context, id string) (*domain.TopicStats, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Stats")
+	}
+
+	var r0 *domain.TopicStats
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.TopicStats, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.TopicStats); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TopicStats)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewTopicService creates a new instance of TopicService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewTopicService(t interface {
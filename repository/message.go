@@ -2,14 +2,26 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/allisson/pgxutil/v2"
+	"github.com/allisson/sqlquery"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/allisson/psqlqueue/domain"
 )
 
+// fairDeliveryCandidatesFactor is the number of ready messages selected for each requested message when the receives
+// are not serialized, so the messages locked by other consumers can be skipped.
+const fairDeliveryCandidatesFactor = 4
+
+// deliverableStates is the "state.in" filter of the messages that can be delivered, an in flight message is delivered
+// again after the visibility timeout.
+const deliverableStates = domain.MessageStateReady + "," + domain.MessageStateInFlight
+
 // Message is an implementation of domain.MessageRepository.
 type Message struct {
 	pool      *pgxpool.Pool
@@ -25,7 +37,7 @@ func (m *Message) CreateMany(ctx context.Context, messages []*domain.Message) er
 	for i := range messages {
 		message := messages[i]
 
-		if err := pgxutil.Insert(ctx, tx, "", m.tableName, message); err != nil {
+		if err := insertMessage(ctx, tx, message); err != nil {
 			executeRollback(ctx, tx)
 			return parseError(err, domain.ErrMessageNotFound, domain.ErrMessageAlreadyExists)
 		}
@@ -35,7 +47,55 @@ func (m *Message) CreateMany(ctx context.Context, messages []*domain.Message) er
 }
 
 func (m *Message) Create(ctx context.Context, message *domain.Message) error {
-	return parseError(pgxutil.Insert(ctx, m.pool, "", m.tableName, message), domain.ErrMessageNotFound, domain.ErrMessageAlreadyExists)
+	err := insertMessage(ctx, m.pool, message)
+	if message.UniqueKey != nil {
+		// the unique index covers only the ready and in flight messages, the key is released by the ack and the expiration.
+		return parseError(err, domain.ErrMessageNotFound, domain.ErrMessageDuplicated)
+	}
+	return parseError(err, domain.ErrMessageNotFound, domain.ErrMessageAlreadyExists)
+}
+
+func (m *Message) CreateOrCoalesce(ctx context.Context, message *domain.Message) error {
+	tx, err := m.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	// serialize the publishes with the same coalesce key, the lock is released on commit/rollback.
+	sqlQuery := `SELECT pg_advisory_xact_lock(hashtext($1 || '/' || $2))`
+	if _, err := tx.Exec(ctx, sqlQuery, message.QueueID, *message.CoalesceKey); err != nil {
+		executeRollback(ctx, tx)
+		return err
+	}
+
+	// replace the newest message with the same coalesce key that was not delivered nor acked yet, the coalesced publishes are counted on the message itself so the publishes to a queue do not wait on a shared row.
+	// The replaced message keeps its enqueue event, a coalesced publish has no event of its own.
+	sqlQuery = `
+	UPDATE messages SET label = $1, body = $2, attributes = $3, num_coalesced = num_coalesced + 1, updated_at = $4
+	WHERE id = (
+		SELECT id FROM messages
+		WHERE queue_id = $5 AND coalesce_key = $6 AND state = 'ready' AND delivery_attempts = 0 AND expired_at > $4
+		ORDER BY created_at DESC LIMIT 1
+		FOR UPDATE SKIP LOCKED
+	)
+	RETURNING id
+	`
+	var id string
+	err = tx.QueryRow(ctx, sqlQuery, message.Label, message.Body, message.Attributes, message.UpdatedAt, message.QueueID, *message.CoalesceKey).Scan(&id)
+	switch err {
+	case nil:
+		message.ID = id
+	case pgx.ErrNoRows:
+		if err := insertMessage(ctx, tx, message); err != nil {
+			executeRollback(ctx, tx)
+			return parseError(err, domain.ErrMessageNotFound, domain.ErrMessageAlreadyExists)
+		}
+	default:
+		executeRollback(ctx, tx)
+		return err
+	}
+
+	return tx.Commit(ctx)
 }
 
 func (m *Message) Get(ctx context.Context, id string) (*domain.Message, error) {
@@ -45,7 +105,7 @@ func (m *Message) Get(ctx context.Context, id string) (*domain.Message, error) {
 	return &message, parseError(err, domain.ErrMessageNotFound, domain.ErrMessageAlreadyExists)
 }
 
-func (m *Message) List(ctx context.Context, queue *domain.Queue, label *string, limit uint) ([]*domain.Message, error) {
+func (m *Message) List(ctx context.Context, queue *domain.Queue, label *string, limit, visibilityTimeoutSeconds uint, ackMode string, consumerID *string) ([]*domain.Message, error) {
 	tx, err := m.pool.Begin(ctx)
 	if err != nil {
 		return nil, err
@@ -53,26 +113,44 @@ func (m *Message) List(ctx context.Context, queue *domain.Queue, label *string,
 
 	messages := []*domain.Message{}
 	now := time.Now().UTC()
-	options := pgxutil.NewFindAllOptions().
-		WithFilter("queue_id", queue.ID).
-		WithFilter("expired_at.gte", now).
-		WithFilter("scheduled_at.lte", now).
-		WithLimit(int(limit)).
-		WithForUpdate("SKIP LOCKED").
-		WithOrderBy("scheduled_at asc")
-	if label != nil {
-		options = options.WithFilter("label", label)
+	if queue.MaxInFlightPerKey > 0 || queue.FairDelivery {
+		messages, err = m.selectReady(ctx, tx, queue, label, limit, now)
+	} else {
+		options := pgxutil.NewFindAllOptions().
+			WithFilter("queue_id", queue.ID).
+			WithFilter("state.in", deliverableStates).
+			WithFilter("expired_at.gte", now).
+			WithFilter("scheduled_at.lte", now).
+			WithLimit(int(limit)).
+			WithForUpdate("SKIP LOCKED").
+			WithOrderBy("scheduled_at asc")
+		if label != nil {
+			options = options.WithFilter("label", label)
+		}
+		err = pgxutil.Select(ctx, tx, m.tableName, options, &messages)
 	}
-	if err := parseError(pgxutil.Select(ctx, tx, m.tableName, options, &messages), domain.ErrMessageNotFound, domain.ErrMessageAlreadyExists); err != nil {
+	if err := parseError(err, domain.ErrMessageNotFound, domain.ErrMessageAlreadyExists); err != nil {
 		executeRollback(ctx, tx)
 		return nil, err
 	}
 
+	eventTypes := []string{}
+	if queue.DeliveryLog {
+		eventTypes = append(eventTypes, domain.MessageEventTypeLease)
+		if ackMode == domain.MessageAckModeAuto {
+			eventTypes = append(eventTypes, domain.MessageEventTypeAck)
+		}
+	}
+
 	for i := range messages {
 		message := messages[i]
 
-		message.DeliverySetup(queue, now)
-		if err := pgxutil.Update(ctx, tx, "", m.tableName, message.ID, &message); err != nil {
+		message.DeliverySetup(queue, visibilityTimeoutSeconds, now)
+		message.SetConsumer(consumerID)
+		if ackMode == domain.MessageAckModeAuto {
+			message.Ack(now)
+		}
+		if err := updateMessage(ctx, tx, message, consumerID, now, eventTypes...); err != nil {
 			executeRollback(ctx, tx)
 			return nil, err
 		}
@@ -81,6 +159,190 @@ func (m *Message) List(ctx context.Context, queue *domain.Queue, label *string,
 	return messages, tx.Commit(ctx)
 }
 
+// selectReady locks the ready messages following the delivery key rules of the queue (max in flight per key and fair delivery).
+func (m *Message) selectReady(ctx context.Context, tx pgx.Tx, queue *domain.Queue, label *string, limit uint, now time.Time) ([]*domain.Message, error) {
+	if queue.MaxInFlightPerKey > 0 {
+		// serialize the receives of the queue, otherwise concurrent consumers could lease more than the limit of a key.
+		if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, queue.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	ids, err := m.listReadyIDs(ctx, tx, queue, label, limit, now)
+	if err != nil || len(ids) == 0 {
+		return []*domain.Message{}, err
+	}
+
+	return m.lockReady(ctx, tx, ids, limit, now)
+}
+
+// lockReady locks the candidates in order, skipping the ones locked by other consumers. The readiness is checked again
+// because another consumer can lease a candidate and commit between the listing and the locking.
+func (m *Message) lockReady(ctx context.Context, tx pgx.Tx, ids []string, limit uint, now time.Time) ([]*domain.Message, error) {
+	sqlQuery := `
+	SELECT * FROM messages
+	WHERE id = ANY($1) AND state IN ('ready', 'in_flight') AND scheduled_at <= $3 AND expired_at >= $3
+	ORDER BY array_position($1, id)
+	LIMIT $2
+	FOR UPDATE SKIP LOCKED
+	`
+	rows, err := tx.Query(ctx, sqlQuery, ids, int64(limit), now)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[domain.Message])
+}
+
+// listReadyIDs returns the ids of the ready messages in delivery order. The messages whose delivery key reached the queue
+// max in flight per key are skipped and, with fair delivery, the messages are interleaved across the delivery keys.
+func (m *Message) listReadyIDs(ctx context.Context, tx pgx.Tx, queue *domain.Queue, label *string, limit uint, now time.Time) ([]string, error) {
+	candidatesLimit := limit
+	if queue.MaxInFlightPerKey == 0 {
+		// the receives are not serialized, other consumers can lock some of the candidates.
+		candidatesLimit = limit * fairDeliveryCandidatesFactor
+	}
+	args := []interface{}{queue.ID, now, int64(candidatesLimit)}
+	key := "label"
+	if queue.DeliveryKeySource == domain.QueueDeliveryKeySourceAttribute && queue.DeliveryKeyAttribute != nil {
+		args = append(args, *queue.DeliveryKeyAttribute)
+		key = fmt.Sprintf("attributes->>$%d", len(args))
+	}
+	labelFilter := ""
+	if label != nil {
+		args = append(args, *label)
+		labelFilter = fmt.Sprintf("AND label = $%d", len(args))
+	}
+	inFlightFilter := ""
+	if queue.MaxInFlightPerKey > 0 {
+		args = append(args, int64(queue.MaxInFlightPerKey))
+		inFlightFilter = fmt.Sprintf(`
+		LEFT JOIN in_flight ON in_flight.key = ready.key
+		WHERE ready.key IS NULL OR ready.position + COALESCE(in_flight.num_messages, 0) <= $%d
+		`, len(args))
+	}
+	orderBy := "ready.scheduled_at"
+	if queue.FairDelivery {
+		// weighted round-robin, each round delivers up to weight messages of each key.
+		weights, err := json.Marshal(queue.FairDeliveryWeights)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, string(weights))
+		orderBy = fmt.Sprintf("CEIL(ready.position::numeric / COALESCE(($%d::jsonb->>ready.key)::int, 1)), ready.scheduled_at", len(args))
+	}
+
+	sqlQuery := fmt.Sprintf(`
+	WITH in_flight AS (
+		SELECT %[1]s AS key, COUNT(1) AS num_messages FROM messages
+		WHERE queue_id = $1 AND state = 'in_flight' AND scheduled_at > $2 AND expired_at > $2
+		GROUP BY 1
+	), ready AS (
+		SELECT id, scheduled_at, %[1]s AS key, ROW_NUMBER() OVER (PARTITION BY %[1]s ORDER BY scheduled_at) AS position FROM messages
+		WHERE queue_id = $1 AND state IN ('ready', 'in_flight') AND expired_at >= $2 AND scheduled_at <= $2 %[2]s
+	)
+	SELECT ready.id FROM ready
+	%[3]s
+	ORDER BY %[4]s LIMIT $3
+	`, key, labelFilter, inFlightFilter, orderBy)
+	rows, err := tx.Query(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
+
+func (m *Message) ListInFlight(ctx context.Context, queueID string, offset, limit uint) ([]*domain.Message, error) {
+	messages := []*domain.Message{}
+	now := time.Now().UTC()
+	options := pgxutil.NewFindAllOptions().
+		WithFilter("queue_id", queueID).
+		WithFilter("state", domain.MessageStateInFlight).
+		WithFilter("scheduled_at.gt", now).
+		WithFilter("expired_at.gt", now).
+		WithOffset(int(offset)).
+		WithLimit(int(limit)).
+		WithOrderBy("scheduled_at asc")
+	err := pgxutil.Select(ctx, m.pool, m.tableName, options, &messages)
+	return messages, parseError(err, domain.ErrMessageNotFound, domain.ErrMessageAlreadyExists)
+}
+
+func (m *Message) ListByPublish(ctx context.Context, topicID, publishID string, offset, limit uint) ([]*domain.Message, error) {
+	messages := []*domain.Message{}
+	options := pgxutil.NewFindAllOptions().
+		WithFilter("publish_id", publishID).
+		WithFilter("source_topic_id", topicID).
+		WithOffset(int(offset)).
+		WithLimit(int(limit)).
+		WithOrderBy("id asc")
+	err := pgxutil.Select(ctx, m.pool, m.tableName, options, &messages)
+	return messages, parseError(err, domain.ErrMessageNotFound, domain.ErrMessageAlreadyExists)
+}
+
+func (m *Message) ReceiveReply(ctx context.Context, queueID, correlationID string) (*domain.Message, error) {
+	tx, err := m.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	messages := []*domain.Message{}
+	now := time.Now().UTC()
+	options := pgxutil.NewFindAllOptions().
+		WithFilter("queue_id", queueID).
+		WithFilter("correlation_id", correlationID).
+		WithFilter("state.in", deliverableStates).
+		WithFilter("expired_at.gte", now).
+		WithFilter("scheduled_at.lte", now).
+		WithLimit(1).
+		WithForUpdate("SKIP LOCKED").
+		WithOrderBy("scheduled_at asc")
+	if err := parseError(pgxutil.Select(ctx, tx, m.tableName, options, &messages), domain.ErrMessageNotFound, domain.ErrMessageAlreadyExists); err != nil {
+		executeRollback(ctx, tx)
+		return nil, err
+	}
+
+	if len(messages) == 0 {
+		executeRollback(ctx, tx)
+		return nil, domain.ErrMessageNotFound
+	}
+
+	// the reply is leased and acked at once, like a message received with the auto ack mode.
+	message := messages[0]
+	message.DeliveryAttempts = message.DeliveryAttempts + 1
+	message.LeasedAt = &now
+	message.Ack(now)
+	if err := updateMessage(ctx, tx, message, nil, now, domain.MessageEventTypeLease, domain.MessageEventTypeAck); err != nil {
+		executeRollback(ctx, tx)
+		return nil, err
+	}
+
+	return message, tx.Commit(ctx)
+}
+
+func (m *Message) UpdateProgress(ctx context.Context, message *domain.Message) error {
+	// only the progress is written, a progress racing the ack or the end of the lease must not bring the message back.
+	sqlQuery := `
+	UPDATE messages SET progress_percent = $2, progress_note = $3, updated_at = $4
+	WHERE id = $1 AND state = 'in_flight' AND scheduled_at > $4
+	`
+	tag, err := m.pool.Exec(ctx, sqlQuery, message.ID, message.ProgressPercent, message.ProgressNote, message.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return domain.ErrMessageNotInFlight
+	}
+
+	return nil
+}
+
+func (m *Message) UpdateWithEvent(ctx context.Context, message *domain.Message, eventType string) error {
+	err := updateMessage(ctx, m.pool, message, nil, time.Now().UTC(), eventType)
+	return parseError(err, domain.ErrMessageNotFound, domain.ErrMessageAlreadyExists)
+}
+
 func (m *Message) Ack(ctx context.Context, id string) error {
 	message, err := m.Get(ctx, id)
 	if err != nil {
@@ -90,19 +352,70 @@ func (m *Message) Ack(ctx context.Context, id string) error {
 	now := time.Now().UTC()
 	message.Ack(now)
 
-	return pgxutil.Update(ctx, m.pool, "", m.tableName, message.ID, &message)
+	return updateMessage(ctx, m.pool, message, nil, now, domain.MessageEventTypeAck)
 }
 
-func (m *Message) Nack(ctx context.Context, id string, visibilityTimeoutSeconds uint) error {
+func (m *Message) Nack(ctx context.Context, id string, visibilityTimeoutSeconds uint, messageError *domain.MessageError) error {
 	message, err := m.Get(ctx, id)
 	if err != nil {
 		return err
 	}
 
 	now := time.Now().UTC()
-	message.Nack(now, visibilityTimeoutSeconds)
+	if messageError != nil {
+		message.NackWithError(messageError, visibilityTimeoutSeconds, now)
+	} else {
+		message.Nack(now, visibilityTimeoutSeconds)
+	}
 
-	return pgxutil.Update(ctx, m.pool, "", m.tableName, message.ID, &message)
+	// a late nack, for example after another consumer leased the message again and acked it, must not deliver it again.
+	sqlQuery, args := withMessageEvents(`
+	UPDATE messages SET state = 'ready', errors = $2, scheduled_at = $3, leased_at = NULL, updated_at = $4
+	WHERE id = $1 AND state = 'in_flight'
+	`, []interface{}{message.ID, message.Errors, message.ScheduledAt, message.UpdatedAt}, nil, now, domain.MessageEventTypeNack)
+	tag, err := m.pool.Exec(ctx, sqlQuery, args...)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return domain.ErrMessageNotInFlight
+	}
+
+	return nil
+}
+
+func (m *Message) ReleaseConsumer(ctx context.Context, queueID, consumerID string) ([]*domain.Message, error) {
+	// the released messages are ready for the delivery right away, like a nack without visibility timeout.
+	now := time.Now().UTC()
+	sqlQuery, args := withMessageEvents(`
+	UPDATE messages SET state = 'ready', scheduled_at = $3, updated_at = $3
+	WHERE queue_id = $1 AND consumer_id = $2 AND state = 'in_flight' AND scheduled_at > $3 AND expired_at > $3
+	`, []interface{}{queueID, consumerID, now}, &consumerID, now, domain.MessageEventTypeRelease)
+	rows, err := m.pool.Query(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[domain.Message])
+}
+
+// insertMessage stores the message with its enqueue event.
+func insertMessage(ctx context.Context, db pgxutil.Querier, message *domain.Message) error {
+	sqlQuery, args := sqlquery.InsertQuery(sqlquery.PostgreSQLFlavor, "", "messages", message)
+	sqlQuery, args = withMessageEvents(sqlQuery, args, nil, time.Now().UTC(), domain.MessageEventTypeEnqueue)
+	_, err := db.Exec(ctx, sqlQuery, args...)
+	return err
+}
+
+// updateMessage stores the changes of the message with its events.
+func updateMessage(ctx context.Context, db pgxutil.Querier, message *domain.Message, consumerID *string, now time.Time, eventTypes ...string) error {
+	sqlQuery, args := sqlquery.UpdateQuery(sqlquery.PostgreSQLFlavor, "", "messages", message.ID, message)
+	if len(eventTypes) > 0 {
+		sqlQuery, args = withMessageEvents(sqlQuery, args, consumerID, now, eventTypes...)
+	}
+	_, err := db.Exec(ctx, sqlQuery, args...)
+	return err
 }
 
 // NewMessage returns an implementation of domain.MessageRepository.
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/allisson/psqlqueue/domain"
+)
+
+// MessageEvent is an implementation of domain.MessageEventRepository.
+type MessageEvent struct {
+	pool *pgxpool.Pool
+}
+
+func (m *MessageEvent) ListByMessage(ctx context.Context, queueID, messageID string, offset, limit uint) ([]*domain.MessageEvent, error) {
+	sqlQuery := `SELECT * FROM message_events WHERE queue_id = $1 AND message_id = $2 ORDER BY id ASC OFFSET $3 LIMIT $4`
+	rows, err := m.pool.Query(ctx, sqlQuery, queueID, messageID, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[domain.MessageEvent])
+}
+
+// withMessageEvents wraps a statement that inserts or updates messages so the same statement stores the delivery log
+// events of the changed messages, one for each event type. The messages of queues without the delivery log have no events.
+// The returned statement selects the changed messages.
+func withMessageEvents(sqlQuery string, args []interface{}, consumerID *string, now time.Time, eventTypes ...string) (string, []interface{}) {
+	n := len(args)
+	sqlQuery = fmt.Sprintf(`
+	WITH changed AS (%[1]s RETURNING *), events AS (
+		INSERT INTO message_events (message_id, queue_id, type, consumer_id, created_at, expired_at)
+		SELECT changed.id, changed.queue_id, event.type, $%[2]d::VARCHAR, $%[3]d::TIMESTAMPTZ, $%[3]d::TIMESTAMPTZ + make_interval(secs => queues.delivery_log_retention_seconds)
+		FROM changed
+		JOIN queues ON queues.id = changed.queue_id
+		CROSS JOIN unnest($%[4]d::VARCHAR[]) WITH ORDINALITY AS event(type, position)
+		WHERE queues.delivery_log
+		ORDER BY changed.id, event.position
+	)
+	SELECT * FROM changed
+	`, sqlQuery, n+1, n+2, n+3)
+	return sqlQuery, append(args, consumerID, now, eventTypes)
+}
+
+// NewMessageEvent returns an implementation of domain.MessageEventRepository.
+func NewMessageEvent(pool *pgxpool.Pool) *MessageEvent {
+	return &MessageEvent{pool: pool}
+}
@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/allisson/psqlqueue/domain"
+)
+
+func TestMessageEvent(t *testing.T) {
+	cfg := domain.NewConfig()
+	ctx := context.Background()
+	pool, _ := pgxpool.New(ctx, cfg.TestDatabaseURL)
+	defer pool.Close()
+
+	setup := func(t *testing.T, deliveryLog bool) (*domain.Queue, *domain.Message) {
+		now := time.Now().UTC()
+		queue := makeQueue("my-queue")
+		queue.DeliveryLog = deliveryLog
+		queue.SetDefaults()
+		message := makeMessage(queue.ID)
+		message.Enqueue(queue, now)
+
+		err := NewQueue(pool).Create(ctx, queue)
+		assert.Nil(t, err)
+		err = NewMessage(pool).Create(ctx, message)
+		assert.Nil(t, err)
+
+		return queue, message
+	}
+
+	t.Run("ListByMessage", func(t *testing.T) {
+		defer clearDatabase(t, ctx, pool)
+
+		queue, message := setup(t, true)
+		consumerID := "worker-1"
+		messageRepo := NewMessage(pool)
+		messageEventRepo := NewMessageEvent(pool)
+
+		messages, err := messageRepo.List(ctx, queue, nil, 10, 0, domain.MessageAckModeManual, &consumerID)
+		assert.Nil(t, err)
+		assert.Len(t, messages, 1)
+		err = messageRepo.Ack(ctx, message.ID)
+		assert.Nil(t, err)
+
+		events, err := messageEventRepo.ListByMessage(ctx, message.QueueID, message.ID, 0, 10)
+		assert.Nil(t, err)
+		assert.Len(t, events, 3)
+		assert.Equal(t, domain.MessageEventTypeEnqueue, events[0].Type)
+		assert.Nil(t, events[0].ConsumerID)
+		assert.Equal(t, domain.MessageEventTypeLease, events[1].Type)
+		assert.Equal(t, consumerID, *events[1].ConsumerID)
+		assert.True(t, events[1].ExpiredAt.After(events[1].CreatedAt))
+		assert.Equal(t, domain.MessageEventTypeAck, events[2].Type)
+
+		events, err = messageEventRepo.ListByMessage(ctx, message.QueueID, message.ID, 1, 10)
+		assert.Nil(t, err)
+		assert.Len(t, events, 2)
+
+		events, err = messageEventRepo.ListByMessage(ctx, "other-queue", message.ID, 0, 10)
+		assert.Nil(t, err)
+		assert.Len(t, events, 0)
+	})
+
+	t.Run("ListByMessage without delivery log", func(t *testing.T) {
+		defer clearDatabase(t, ctx, pool)
+
+		queue, message := setup(t, false)
+		messageRepo := NewMessage(pool)
+		messageEventRepo := NewMessageEvent(pool)
+
+		_, err := messageRepo.List(ctx, queue, nil, 10, 0, domain.MessageAckModeManual, nil)
+		assert.Nil(t, err)
+		err = messageRepo.Nack(ctx, message.ID, 0, nil)
+		assert.Nil(t, err)
+
+		events, err := messageEventRepo.ListByMessage(ctx, message.QueueID, message.ID, 0, 10)
+		assert.Nil(t, err)
+		assert.Len(t, events, 0)
+	})
+
+	t.Run("ListByMessage with seek", func(t *testing.T) {
+		defer clearDatabase(t, ctx, pool)
+
+		queue, message := setup(t, true)
+		messageRepo := NewMessage(pool)
+		messageEventRepo := NewMessageEvent(pool)
+
+		err := messageRepo.Ack(ctx, message.ID)
+		assert.Nil(t, err)
+		err = NewQueue(pool).Seek(ctx, queue, time.Now().UTC().Add(-time.Minute))
+		assert.Nil(t, err)
+
+		events, err := messageEventRepo.ListByMessage(ctx, queue.ID, message.ID, 0, 10)
+		assert.Nil(t, err)
+		assert.Len(t, events, 3)
+		assert.Equal(t, domain.MessageEventTypeAck, events[1].Type)
+		assert.Equal(t, domain.MessageEventTypeSeek, events[2].Type)
+	})
+
+	t.Run("ListByMessage with reply", func(t *testing.T) {
+		defer clearDatabase(t, ctx, pool)
+
+		now := time.Now().UTC()
+		queue := makeQueue("my-reply-queue")
+		queue.DeliveryLog = true
+		queue.SetDefaults()
+		message := makeMessage(queue.ID)
+		message.SetReply(queue.ID)
+		message.Enqueue(queue, now)
+		messageRepo := NewMessage(pool)
+		messageEventRepo := NewMessageEvent(pool)
+
+		err := NewQueue(pool).Create(ctx, queue)
+		assert.Nil(t, err)
+		err = messageRepo.Create(ctx, message)
+		assert.Nil(t, err)
+
+		reply, err := messageRepo.ReceiveReply(ctx, queue.ID, *message.CorrelationID)
+		assert.Nil(t, err)
+		assert.NotNil(t, reply.LeasedAt)
+
+		events, err := messageEventRepo.ListByMessage(ctx, queue.ID, message.ID, 0, 10)
+		assert.Nil(t, err)
+		assert.Len(t, events, 3)
+		assert.Equal(t, domain.MessageEventTypeLease, events[1].Type)
+		assert.Equal(t, domain.MessageEventTypeAck, events[2].Type)
+	})
+
+	t.Run("ListByMessage with coalesced publish", func(t *testing.T) {
+		defer clearDatabase(t, ctx, pool)
+
+		now := time.Now().UTC()
+		queue := makeQueue("my-queue")
+		queue.DeliveryLog = true
+		queue.Coalesce = true
+		queue.SetDefaults()
+		message1 := makeMessage(queue.ID)
+		message1.CoalesceKey = pointString("customer-42")
+		message1.Enqueue(queue, now)
+		message2 := makeMessage(queue.ID)
+		message2.CoalesceKey = pointString("customer-42")
+		message2.Enqueue(queue, now)
+		messageRepo := NewMessage(pool)
+		messageEventRepo := NewMessageEvent(pool)
+
+		err := NewQueue(pool).Create(ctx, queue)
+		assert.Nil(t, err)
+		err = messageRepo.CreateOrCoalesce(ctx, message1)
+		assert.Nil(t, err)
+		err = messageRepo.CreateOrCoalesce(ctx, message2)
+		assert.Nil(t, err)
+
+		events, err := messageEventRepo.ListByMessage(ctx, queue.ID, message1.ID, 0, 10)
+		assert.Nil(t, err)
+		assert.Len(t, events, 1)
+		assert.Equal(t, domain.MessageEventTypeEnqueue, events[0].Type)
+	})
+}
@@ -71,6 +71,121 @@ func TestMessage(t *testing.T) {
 		assert.ErrorIs(t, err, domain.ErrMessageAlreadyExists)
 	})
 
+	t.Run("Create with unique key", func(t *testing.T) {
+		defer clearDatabase(t, ctx, pool)
+
+		now := time.Now().UTC()
+		queue := makeQueue("my-queue")
+		message1 := makeMessage(queue.ID)
+		message1.UniqueKey = pointString("reindex-customer-42")
+		message1.Enqueue(queue, now)
+		message2 := makeMessage(queue.ID)
+		message2.UniqueKey = pointString("reindex-customer-42")
+		message2.Enqueue(queue, now)
+		queueRepo := NewQueue(pool)
+		messageRepo := NewMessage(pool)
+
+		err := queueRepo.Create(ctx, queue)
+		assert.Nil(t, err)
+
+		err = messageRepo.Create(ctx, message1)
+		assert.Nil(t, err)
+
+		err = messageRepo.Create(ctx, message2)
+		assert.ErrorIs(t, err, domain.ErrMessageDuplicated)
+
+		err = messageRepo.Ack(ctx, message1.ID)
+		assert.Nil(t, err)
+
+		err = messageRepo.Create(ctx, message2)
+		assert.Nil(t, err)
+	})
+
+	t.Run("CreateOrCoalesce", func(t *testing.T) {
+		defer clearDatabase(t, ctx, pool)
+
+		now := time.Now().UTC()
+		queue := makeQueue("my-queue")
+		queue.Coalesce = true
+		message1 := makeMessage(queue.ID)
+		message1.CoalesceKey = pointString("customer-42")
+		message1.Enqueue(queue, now)
+		message2 := makeMessage(queue.ID)
+		message2.Body = `{"version": 2}`
+		message2.CoalesceKey = pointString("customer-42")
+		message2.Enqueue(queue, now)
+		queueRepo := NewQueue(pool)
+		messageRepo := NewMessage(pool)
+
+		err := queueRepo.Create(ctx, queue)
+		assert.Nil(t, err)
+
+		err = messageRepo.CreateOrCoalesce(ctx, message1)
+		assert.Nil(t, err)
+
+		err = messageRepo.CreateOrCoalesce(ctx, message2)
+		assert.Nil(t, err)
+		assert.Equal(t, message1.ID, message2.ID)
+
+		messages, err := messageRepo.List(ctx, queue, nil, 10, 0, domain.MessageAckModeManual, nil)
+		assert.Nil(t, err)
+		assert.Len(t, messages, 1)
+		assert.Equal(t, `{"version": 2}`, messages[0].Body)
+
+		stats, err := queueRepo.Stats(ctx, queue.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, uint(1), stats.NumCoalescedMessages)
+
+		message3 := makeMessage(queue.ID)
+		message3.CoalesceKey = pointString("customer-42")
+		message3.Enqueue(queue, now)
+		err = messageRepo.CreateOrCoalesce(ctx, message3)
+		assert.Nil(t, err)
+		assert.NotEqual(t, message1.ID, message3.ID)
+
+		err = queueRepo.Purge(ctx, queue.ID)
+		assert.Nil(t, err)
+
+		stats, err = queueRepo.Stats(ctx, queue.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, uint(1), stats.NumCoalescedMessages)
+	})
+
+	t.Run("CreateOrCoalesce with acked message", func(t *testing.T) {
+		defer clearDatabase(t, ctx, pool)
+
+		now := time.Now().UTC()
+		queue := makeQueue("my-queue")
+		queue.Coalesce = true
+		message1 := makeMessage(queue.ID)
+		message1.CoalesceKey = pointString("customer-42")
+		message1.Enqueue(queue, now)
+		message2 := makeMessage(queue.ID)
+		message2.CoalesceKey = pointString("customer-42")
+		message2.Enqueue(queue, now)
+		queueRepo := NewQueue(pool)
+		messageRepo := NewMessage(pool)
+
+		err := queueRepo.Create(ctx, queue)
+		assert.Nil(t, err)
+
+		err = messageRepo.CreateOrCoalesce(ctx, message1)
+		assert.Nil(t, err)
+
+		// acked without being delivered.
+		err = messageRepo.Ack(ctx, message1.ID)
+		assert.Nil(t, err)
+
+		err = messageRepo.CreateOrCoalesce(ctx, message2)
+		assert.Nil(t, err)
+		assert.NotEqual(t, message1.ID, message2.ID)
+
+		messages, err := messageRepo.List(ctx, queue, nil, 10, 0, domain.MessageAckModeManual, nil)
+		assert.Nil(t, err)
+		assert.Len(t, messages, 1)
+		assert.Equal(t, message2.ID, messages[0].ID)
+	})
+
 	t.Run("Get", func(t *testing.T) {
 		defer clearDatabase(t, ctx, pool)
 
@@ -115,11 +230,11 @@ func TestMessage(t *testing.T) {
 		err = messageRepo.Create(ctx, message2)
 		assert.Nil(t, err)
 
-		messages, err := messageRepo.List(ctx, queue, nil, 10)
+		messages, err := messageRepo.List(ctx, queue, nil, 10, 0, domain.MessageAckModeManual, nil)
 		assert.Nil(t, err)
 		assert.Len(t, messages, 2)
 
-		messages, err = messageRepo.List(ctx, queue, nil, 10)
+		messages, err = messageRepo.List(ctx, queue, nil, 10, 0, domain.MessageAckModeManual, nil)
 		assert.Nil(t, err)
 		assert.Len(t, messages, 0)
 	})
@@ -147,17 +262,215 @@ func TestMessage(t *testing.T) {
 		err = messageRepo.Create(ctx, message2)
 		assert.Nil(t, err)
 
-		messages, err := messageRepo.List(ctx, queue, pointString("label-1"), 10)
+		messages, err := messageRepo.List(ctx, queue, pointString("label-1"), 10, 0, domain.MessageAckModeManual, nil)
 		assert.Nil(t, err)
 		assert.Len(t, messages, 1)
 		assert.Equal(t, message1.ID, messages[0].ID)
 
-		messages, err = messageRepo.List(ctx, queue, pointString("label-2"), 10)
+		messages, err = messageRepo.List(ctx, queue, pointString("label-2"), 10, 0, domain.MessageAckModeManual, nil)
 		assert.Nil(t, err)
 		assert.Len(t, messages, 1)
 		assert.Equal(t, message2.ID, messages[0].ID)
 	})
 
+	t.Run("List with visibility timeout", func(t *testing.T) {
+		defer clearDatabase(t, ctx, pool)
+
+		now := time.Now().UTC()
+		queue := makeQueue("my-queue")
+		queue.MaxVisibilityTimeoutSeconds = 600
+		message := makeMessage(queue.ID)
+		message.Enqueue(queue, now)
+		queueRepo := NewQueue(pool)
+		messageRepo := NewMessage(pool)
+
+		err := queueRepo.Create(ctx, queue)
+		assert.Nil(t, err)
+
+		err = messageRepo.Create(ctx, message)
+		assert.Nil(t, err)
+
+		messages, err := messageRepo.List(ctx, queue, nil, 10, 300, domain.MessageAckModeManual, nil)
+		assert.Nil(t, err)
+		assert.Len(t, messages, 1)
+
+		messageFromDB, err := messageRepo.Get(ctx, message.ID)
+		assert.Nil(t, err)
+		assert.True(t, messageFromDB.ScheduledAt.After(now.Add(299*time.Second)))
+	})
+
+	t.Run("List with auto ack", func(t *testing.T) {
+		defer clearDatabase(t, ctx, pool)
+
+		now := time.Now().UTC()
+		queue := makeQueue("my-queue")
+		message := makeMessage(queue.ID)
+		message.Enqueue(queue, now)
+		queueRepo := NewQueue(pool)
+		messageRepo := NewMessage(pool)
+
+		err := queueRepo.Create(ctx, queue)
+		assert.Nil(t, err)
+
+		err = messageRepo.Create(ctx, message)
+		assert.Nil(t, err)
+
+		messages, err := messageRepo.List(ctx, queue, nil, 10, 0, domain.MessageAckModeAuto, nil)
+		assert.Nil(t, err)
+		assert.Len(t, messages, 1)
+
+		messageFromDB, err := messageRepo.Get(ctx, message.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, domain.MessageStatusAcked, messageFromDB.Status(time.Now().UTC()))
+	})
+
+	t.Run("List with max in flight per key", func(t *testing.T) {
+		defer clearDatabase(t, ctx, pool)
+
+		now := time.Now().UTC()
+		queue := makeQueue("my-queue")
+		queue.DeliveryKeySource = domain.QueueDeliveryKeySourceAttribute
+		queue.DeliveryKeyAttribute = pointString("tenant")
+		queue.MaxInFlightPerKey = 1
+		message1 := makeMessage(queue.ID)
+		message1.Attributes = map[string]string{"tenant": "tenant-1"}
+		message1.Enqueue(queue, now)
+		message2 := makeMessage(queue.ID)
+		message2.Attributes = map[string]string{"tenant": "tenant-1"}
+		message2.Enqueue(queue, now.Add(time.Millisecond))
+		message3 := makeMessage(queue.ID)
+		message3.Attributes = map[string]string{"tenant": "tenant-2"}
+		message3.Enqueue(queue, now.Add(2*time.Millisecond))
+		queueRepo := NewQueue(pool)
+		messageRepo := NewMessage(pool)
+
+		err := queueRepo.Create(ctx, queue)
+		assert.Nil(t, err)
+
+		err = messageRepo.CreateMany(ctx, []*domain.Message{message1, message2, message3})
+		assert.Nil(t, err)
+
+		messages, err := messageRepo.List(ctx, queue, nil, 10, 0, domain.MessageAckModeManual, nil)
+		assert.Nil(t, err)
+		assert.Len(t, messages, 2)
+		assert.Equal(t, message1.ID, messages[0].ID)
+		assert.Equal(t, message3.ID, messages[1].ID)
+
+		messages, err = messageRepo.List(ctx, queue, nil, 10, 0, domain.MessageAckModeManual, nil)
+		assert.Nil(t, err)
+		assert.Len(t, messages, 0)
+
+		err = messageRepo.Ack(ctx, message1.ID)
+		assert.Nil(t, err)
+
+		messages, err = messageRepo.List(ctx, queue, nil, 10, 0, domain.MessageAckModeManual, nil)
+		assert.Nil(t, err)
+		assert.Len(t, messages, 1)
+		assert.Equal(t, message2.ID, messages[0].ID)
+	})
+
+	t.Run("List with max in flight per key and nacked message", func(t *testing.T) {
+		defer clearDatabase(t, ctx, pool)
+
+		now := time.Now().UTC()
+		queue := makeQueue("my-queue")
+		queue.MaxInFlightPerKey = 1
+		message1 := makeMessage(queue.ID)
+		message1.Label = pointString("tenant-1")
+		message1.Enqueue(queue, now)
+		message2 := makeMessage(queue.ID)
+		message2.Label = pointString("tenant-1")
+		message2.Enqueue(queue, now.Add(time.Millisecond))
+		queueRepo := NewQueue(pool)
+		messageRepo := NewMessage(pool)
+
+		err := queueRepo.Create(ctx, queue)
+		assert.Nil(t, err)
+
+		err = messageRepo.CreateMany(ctx, []*domain.Message{message1, message2})
+		assert.Nil(t, err)
+
+		messages, err := messageRepo.List(ctx, queue, nil, 1, 0, domain.MessageAckModeManual, nil)
+		assert.Nil(t, err)
+		assert.Len(t, messages, 1)
+		assert.Equal(t, message1.ID, messages[0].ID)
+
+		// the nacked message waits for the visibility timeout without holding its key.
+		err = messageRepo.Nack(ctx, message1.ID, 60, nil)
+		assert.Nil(t, err)
+
+		messages, err = messageRepo.List(ctx, queue, nil, 10, 0, domain.MessageAckModeManual, nil)
+		assert.Nil(t, err)
+		assert.Len(t, messages, 1)
+		assert.Equal(t, message2.ID, messages[0].ID)
+	})
+
+	t.Run("List with fair delivery", func(t *testing.T) {
+		defer clearDatabase(t, ctx, pool)
+
+		now := time.Now().UTC()
+		queue := makeQueue("my-queue")
+		queue.FairDelivery = true
+		queue.FairDeliveryWeights = map[string]uint{"tenant-2": 2}
+		messages := []*domain.Message{}
+		for i, label := range []string{"tenant-1", "tenant-1", "tenant-1", "tenant-2", "tenant-2", "tenant-2"} {
+			message := makeMessage(queue.ID)
+			message.Label = pointString(label)
+			message.Enqueue(queue, now.Add(time.Duration(i)*time.Millisecond))
+			messages = append(messages, message)
+		}
+		queueRepo := NewQueue(pool)
+		messageRepo := NewMessage(pool)
+
+		err := queueRepo.Create(ctx, queue)
+		assert.Nil(t, err)
+
+		err = messageRepo.CreateMany(ctx, messages)
+		assert.Nil(t, err)
+
+		receivedMessages, err := messageRepo.List(ctx, queue, nil, 4, 0, domain.MessageAckModeManual, nil)
+		assert.Nil(t, err)
+		assert.Len(t, receivedMessages, 4)
+		assert.Equal(t, messages[0].ID, receivedMessages[0].ID)
+		assert.Equal(t, messages[3].ID, receivedMessages[1].ID)
+		assert.Equal(t, messages[4].ID, receivedMessages[2].ID)
+		assert.Equal(t, messages[1].ID, receivedMessages[3].ID)
+	})
+
+	t.Run("List with fair delivery and a candidate leased before the lock", func(t *testing.T) {
+		defer clearDatabase(t, ctx, pool)
+
+		now := time.Now().UTC()
+		queue := makeQueue("my-queue")
+		queue.FairDelivery = true
+		message := makeMessage(queue.ID)
+		message.Enqueue(queue, now)
+		queueRepo := NewQueue(pool)
+		messageRepo := NewMessage(pool)
+
+		err := queueRepo.Create(ctx, queue)
+		assert.Nil(t, err)
+
+		err = messageRepo.Create(ctx, message)
+		assert.Nil(t, err)
+
+		tx, err := pool.Begin(ctx)
+		assert.Nil(t, err)
+		defer executeRollback(ctx, tx)
+
+		ids, err := messageRepo.listReadyIDs(ctx, tx, queue, nil, 10, now)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{message.ID}, ids)
+
+		receivedMessages, err := messageRepo.List(ctx, queue, nil, 10, 0, domain.MessageAckModeManual, nil)
+		assert.Nil(t, err)
+		assert.Len(t, receivedMessages, 1)
+
+		lockedMessages, err := messageRepo.lockReady(ctx, tx, ids, 10, now)
+		assert.Nil(t, err)
+		assert.Len(t, lockedMessages, 0)
+	})
+
 	t.Run("Ack", func(t *testing.T) {
 		defer clearDatabase(t, ctx, pool)
 
@@ -178,6 +491,67 @@ func TestMessage(t *testing.T) {
 		assert.Nil(t, err)
 	})
 
+	t.Run("UpdateProgress", func(t *testing.T) {
+		defer clearDatabase(t, ctx, pool)
+
+		now := time.Now().UTC()
+		queue := makeQueue("my-queue")
+		message := makeMessage(queue.ID)
+		message.Enqueue(queue, now)
+		message.DeliverySetup(queue, 0, now)
+		queueRepo := NewQueue(pool)
+		messageRepo := NewMessage(pool)
+
+		err := queueRepo.Create(ctx, queue)
+		assert.Nil(t, err)
+
+		err = messageRepo.Create(ctx, message)
+		assert.Nil(t, err)
+
+		err = message.SetProgress(&domain.MessageProgress{Percent: 50, Note: pointString("half way")}, now)
+		assert.Nil(t, err)
+		err = messageRepo.UpdateProgress(ctx, message)
+		assert.Nil(t, err)
+
+		messageFromDB, err := messageRepo.Get(ctx, message.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, uint(50), *messageFromDB.ProgressPercent)
+		assert.Equal(t, "half way", *messageFromDB.ProgressNote)
+		assert.Equal(t, domain.MessageStateInFlight, messageFromDB.State)
+	})
+
+	t.Run("UpdateProgress after ack", func(t *testing.T) {
+		defer clearDatabase(t, ctx, pool)
+
+		now := time.Now().UTC()
+		queue := makeQueue("my-queue")
+		message := makeMessage(queue.ID)
+		message.Enqueue(queue, now)
+		message.DeliverySetup(queue, 0, now)
+		queueRepo := NewQueue(pool)
+		messageRepo := NewMessage(pool)
+
+		err := queueRepo.Create(ctx, queue)
+		assert.Nil(t, err)
+
+		err = messageRepo.Create(ctx, message)
+		assert.Nil(t, err)
+
+		// the progress is read before the ack and stored after it.
+		err = message.SetProgress(&domain.MessageProgress{Percent: 50}, now)
+		assert.Nil(t, err)
+		err = messageRepo.Ack(ctx, message.ID)
+		assert.Nil(t, err)
+		err = messageRepo.UpdateProgress(ctx, message)
+		assert.ErrorIs(t, err, domain.ErrMessageNotInFlight)
+
+		messageFromDB, err := messageRepo.Get(ctx, message.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, domain.MessageStateAcked, messageFromDB.State)
+		assert.NotNil(t, messageFromDB.AckedAt)
+		assert.Nil(t, messageFromDB.ProgressPercent)
+	})
+
 	t.Run("Nack", func(t *testing.T) {
 		defer clearDatabase(t, ctx, pool)
 
@@ -194,7 +568,242 @@ func TestMessage(t *testing.T) {
 		err = messageRepo.Create(ctx, message)
 		assert.Nil(t, err)
 
-		err = messageRepo.Nack(ctx, message.ID, uint(0))
+		messages, err := messageRepo.List(ctx, queue, nil, 1, 0, domain.MessageAckModeManual, nil)
+		assert.Nil(t, err)
+		assert.Len(t, messages, 1)
+
+		err = messageRepo.Nack(ctx, message.ID, uint(0), nil)
+		assert.Nil(t, err)
+
+		messageFromDB, err := messageRepo.Get(ctx, message.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, domain.MessageStateReady, messageFromDB.State)
+		assert.Nil(t, messageFromDB.LeasedAt)
+	})
+
+	t.Run("Nack after ack", func(t *testing.T) {
+		defer clearDatabase(t, ctx, pool)
+
+		now := time.Now().UTC()
+		queue := makeQueue("my-queue")
+		message := makeMessage(queue.ID)
+		message.UniqueKey = pointString("order-42")
+		message.Enqueue(queue, now)
+		queueRepo := NewQueue(pool)
+		messageRepo := NewMessage(pool)
+
+		err := queueRepo.Create(ctx, queue)
+		assert.Nil(t, err)
+
+		err = messageRepo.Create(ctx, message)
+		assert.Nil(t, err)
+
+		messages, err := messageRepo.List(ctx, queue, nil, 1, 0, domain.MessageAckModeManual, nil)
+		assert.Nil(t, err)
+		assert.Len(t, messages, 1)
+
+		// another consumer acks the message and a new message takes the unique key before the late nack.
+		err = messageRepo.Ack(ctx, message.ID)
+		assert.Nil(t, err)
+		pendingMessage := makeMessage(queue.ID)
+		pendingMessage.UniqueKey = pointString("order-42")
+		pendingMessage.Enqueue(queue, now)
+		err = messageRepo.Create(ctx, pendingMessage)
+		assert.Nil(t, err)
+
+		err = messageRepo.Nack(ctx, message.ID, uint(0), &domain.MessageError{Message: "connection refused"})
+		assert.ErrorIs(t, err, domain.ErrMessageNotInFlight)
+
+		messageFromDB, err := messageRepo.Get(ctx, message.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, domain.MessageStateAcked, messageFromDB.State)
+		assert.NotNil(t, messageFromDB.AckedAt)
+		assert.Len(t, messageFromDB.Errors, 0)
+	})
+
+	t.Run("UpdateWithEvent with result", func(t *testing.T) {
+		defer clearDatabase(t, ctx, pool)
+
+		now := time.Now().UTC()
+		queue := makeQueue("my-queue")
+		queue.ResultRetentionSeconds = 60
+		message := makeMessage(queue.ID)
+		message.Enqueue(queue, now)
+		message.DeliverySetup(queue, 0, now)
+		queueRepo := NewQueue(pool)
+		messageRepo := NewMessage(pool)
+
+		err := queueRepo.Create(ctx, queue)
+		assert.Nil(t, err)
+
+		err = messageRepo.Create(ctx, message)
+		assert.Nil(t, err)
+
+		message.AckWithResult(&domain.MessageResult{Status: domain.MessageResultStatusSucceeded, Body: pointString("done")}, queue, now)
+		err = messageRepo.UpdateWithEvent(ctx, message, domain.MessageEventTypeAck)
+		assert.Nil(t, err)
+
+		messageFromDB, err := messageRepo.Get(ctx, message.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, domain.MessageResultStatusSucceeded, *messageFromDB.ResultStatus)
+		assert.Equal(t, "done", *messageFromDB.Result)
+		assert.NotNil(t, messageFromDB.ResultExpiredAt)
+	})
+
+	t.Run("Nack with error", func(t *testing.T) {
+		defer clearDatabase(t, ctx, pool)
+
+		now := time.Now().UTC()
+		queue := makeQueue("my-queue")
+		message := makeMessage(queue.ID)
+		message.Enqueue(queue, now)
+		message.DeliverySetup(queue, 0, now)
+		queueRepo := NewQueue(pool)
+		messageRepo := NewMessage(pool)
+
+		err := queueRepo.Create(ctx, queue)
+		assert.Nil(t, err)
+
+		err = messageRepo.Create(ctx, message)
+		assert.Nil(t, err)
+
+		err = messageRepo.Nack(ctx, message.ID, 0, &domain.MessageError{Message: "connection refused", Code: pointString("ECONNREFUSED")})
+		assert.Nil(t, err)
+
+		messageFromDB, err := messageRepo.Get(ctx, message.ID)
+		assert.Nil(t, err)
+		assert.Len(t, messageFromDB.Errors, 1)
+		assert.Equal(t, "connection refused", messageFromDB.Errors[0].Message)
+		assert.Equal(t, "ECONNREFUSED", *messageFromDB.Errors[0].Code)
+		assert.Equal(t, uint(1), messageFromDB.Errors[0].DeliveryAttempt)
+	})
+
+	t.Run("ListByPublish", func(t *testing.T) {
+		defer clearDatabase(t, ctx, pool)
+
+		now := time.Now().UTC()
+		topic := makeTopic("my-topic")
+		queue := makeQueue("my-queue")
+		subscription := &domain.Subscription{ID: "my-subscription", TopicID: topic.ID, QueueID: &queue.ID}
+		message1 := makeMessage(queue.ID)
+		message1.Enqueue(queue, now)
+		message1.SetSource("my-publish", topic.ID, subscription)
+		message2 := makeMessage(queue.ID)
+		message2.Enqueue(queue, now)
+		queueRepo := NewQueue(pool)
+		messageRepo := NewMessage(pool)
+
+		err := queueRepo.Create(ctx, queue)
+		assert.Nil(t, err)
+
+		err = messageRepo.CreateMany(ctx, []*domain.Message{message1, message2})
+		assert.Nil(t, err)
+
+		messages, err := messageRepo.ListByPublish(ctx, topic.ID, "my-publish", 0, 10)
+		assert.Nil(t, err)
+		assert.Len(t, messages, 1)
+		assert.Equal(t, message1.ID, messages[0].ID)
+		assert.Equal(t, subscription.ID, *messages[0].SourceSubscriptionID)
+
+		messages, err = messageRepo.ListByPublish(ctx, "my-other-topic", "my-publish", 0, 10)
+		assert.Nil(t, err)
+		assert.Len(t, messages, 0)
+	})
+
+	t.Run("ReceiveReply", func(t *testing.T) {
+		defer clearDatabase(t, ctx, pool)
+
+		now := time.Now().UTC()
+		queue := makeQueue("my-reply-queue")
+		message1 := makeMessage(queue.ID)
+		message1.SetReply(queue.ID)
+		message1.Enqueue(queue, now)
+		message2 := makeMessage(queue.ID)
+		message2.Enqueue(queue, now)
+		queueRepo := NewQueue(pool)
+		messageRepo := NewMessage(pool)
+
+		err := queueRepo.Create(ctx, queue)
+		assert.Nil(t, err)
+
+		err = messageRepo.CreateMany(ctx, []*domain.Message{message1, message2})
+		assert.Nil(t, err)
+
+		reply, err := messageRepo.ReceiveReply(ctx, queue.ID, *message1.CorrelationID)
+		assert.Nil(t, err)
+		assert.Equal(t, message1.ID, reply.ID)
+		assert.Equal(t, uint(1), reply.DeliveryAttempts)
+
+		_, err = messageRepo.ReceiveReply(ctx, queue.ID, *message1.CorrelationID)
+		assert.ErrorIs(t, err, domain.ErrMessageNotFound)
+	})
+
+	t.Run("ListInFlight", func(t *testing.T) {
+		defer clearDatabase(t, ctx, pool)
+
+		now := time.Now().UTC()
+		queue := makeQueue("my-queue")
+		message1 := makeMessage(queue.ID)
+		message1.Enqueue(queue, now)
+		message2 := makeMessage(queue.ID)
+		message2.Enqueue(queue, now)
+		queueRepo := NewQueue(pool)
+		messageRepo := NewMessage(pool)
+
+		err := queueRepo.Create(ctx, queue)
+		assert.Nil(t, err)
+
+		err = messageRepo.CreateMany(ctx, []*domain.Message{message1, message2})
+		assert.Nil(t, err)
+
+		messages, err := messageRepo.List(ctx, queue, nil, 1, 0, domain.MessageAckModeManual, pointString("worker-1"))
+		assert.Nil(t, err)
+		assert.Len(t, messages, 1)
+
+		messages, err = messageRepo.ListInFlight(ctx, queue.ID, 0, 10)
 		assert.Nil(t, err)
+		assert.Len(t, messages, 1)
+		assert.Equal(t, "worker-1", *messages[0].ConsumerID)
+		assert.NotNil(t, messages[0].LeasedAt)
+	})
+
+	t.Run("ReleaseConsumer", func(t *testing.T) {
+		defer clearDatabase(t, ctx, pool)
+
+		now := time.Now().UTC()
+		queue := makeQueue("my-queue")
+		message1 := makeMessage(queue.ID)
+		message1.Enqueue(queue, now)
+		message2 := makeMessage(queue.ID)
+		message2.Enqueue(queue, now)
+		queueRepo := NewQueue(pool)
+		messageRepo := NewMessage(pool)
+
+		err := queueRepo.Create(ctx, queue)
+		assert.Nil(t, err)
+
+		err = messageRepo.CreateMany(ctx, []*domain.Message{message1, message2})
+		assert.Nil(t, err)
+
+		messages, err := messageRepo.List(ctx, queue, nil, 1, 0, domain.MessageAckModeManual, pointString("worker-1"))
+		assert.Nil(t, err)
+		assert.Len(t, messages, 1)
+
+		messages, err = messageRepo.List(ctx, queue, nil, 1, 0, domain.MessageAckModeManual, pointString("worker-2"))
+		assert.Nil(t, err)
+		assert.Len(t, messages, 1)
+
+		messages, err = messageRepo.ReleaseConsumer(ctx, queue.ID, "worker-1")
+		assert.Nil(t, err)
+		assert.Len(t, messages, 1)
+
+		messages, err = messageRepo.ListInFlight(ctx, queue.ID, 0, 10)
+		assert.Nil(t, err)
+		assert.Len(t, messages, 1)
+		assert.Equal(t, "worker-2", *messages[0].ConsumerID)
+
+		messages, err = messageRepo.List(ctx, queue, nil, 10, 0, domain.MessageAckModeManual, nil)
+		assert.Nil(t, err)
+		assert.Len(t, messages, 1)
 	})
 }
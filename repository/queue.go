@@ -47,9 +47,29 @@ func (q *Queue) Delete(ctx context.Context, id string) error {
 func (q *Queue) Stats(ctx context.Context, id string) (*domain.QueueStats, error) {
 	stats := &domain.QueueStats{}
 	now := time.Now().UTC()
+
+	sqlQuery := `SELECT num_coalesced_messages, num_expired_messages FROM queue_stats WHERE queue_id = $1`
+	if err := q.pool.QueryRow(ctx, sqlQuery, id).Scan(&stats.NumCoalescedMessages, &stats.NumExpiredMessages); err != nil && err != pgx.ErrNoRows {
+		return stats, err
+	}
+
+	// the coalesced publishes are kept on the messages until they are removed.
+	var numCoalescedMessages uint
+	sqlQuery = `SELECT COALESCE(SUM(num_coalesced), 0) FROM messages WHERE queue_id = $1 AND num_coalesced > 0`
+	if err := q.pool.QueryRow(ctx, sqlQuery, id).Scan(&numCoalescedMessages); err != nil {
+		return stats, err
+	}
+	stats.NumCoalescedMessages += numCoalescedMessages
+
+	sqlQuery = `SELECT COUNT(1) FROM messages WHERE queue_id = $1 AND state = 'acked'`
+	if err := q.pool.QueryRow(ctx, sqlQuery, id).Scan(&stats.NumAckedMessages); err != nil {
+		return stats, err
+	}
+
 	options := pgxutil.NewFindAllOptions().
 		WithFields([]string{"COUNT(1)"}).
 		WithFilter("queue_id", id).
+		WithFilter("state.in", deliverableStates).
 		WithFilter("expired_at.gte", now).
 		WithFilter("scheduled_at.lte", now).
 		WithLimit(1)
@@ -81,15 +101,146 @@ func (q *Queue) Stats(ctx context.Context, id string) (*domain.QueueStats, error
 }
 
 func (q *Queue) Purge(ctx context.Context, id string) error {
-	sqlQuery := `DELETE FROM messages WHERE queue_id = $1`
+	sqlQuery := withCoalescedMessages(`DELETE FROM messages WHERE queue_id = $1 RETURNING num_coalesced`)
 	_, err := q.pool.Exec(ctx, sqlQuery, id)
 	return err
 }
 
 func (q *Queue) Cleanup(ctx context.Context, id string) error {
+	tx, err := q.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	// the messages that reached the retention period without being acked are expired before the removal.
+	now := time.Now().UTC()
+	sqlQuery := `UPDATE messages SET state = 'expired', updated_at = $2 WHERE queue_id = $1 AND state IN ('ready', 'in_flight') AND expired_at <= $2`
+	tag, err := tx.Exec(ctx, sqlQuery, id, now)
+	if err != nil {
+		executeRollback(ctx, tx)
+		return err
+	}
+
+	if numExpiredMessages := tag.RowsAffected(); numExpiredMessages > 0 {
+		sqlQuery = `
+		INSERT INTO queue_stats (queue_id, num_expired_messages) VALUES ($1, $2)
+		ON CONFLICT (queue_id) DO UPDATE SET num_expired_messages = queue_stats.num_expired_messages + $2
+		`
+		if _, err := tx.Exec(ctx, sqlQuery, id, numExpiredMessages); err != nil {
+			executeRollback(ctx, tx)
+			return err
+		}
+
+		sqlQuery = `
+		INSERT INTO message_events (message_id, queue_id, type, created_at, expired_at)
+		SELECT messages.id, messages.queue_id, $3::VARCHAR, $2::TIMESTAMPTZ, $2::TIMESTAMPTZ + make_interval(secs => queues.delivery_log_retention_seconds)
+		FROM messages
+		JOIN queues ON queues.id = messages.queue_id
+		WHERE messages.queue_id = $1 AND queues.delivery_log AND messages.state = 'expired'
+		`
+		if _, err := tx.Exec(ctx, sqlQuery, id, now, domain.MessageEventTypeExpire); err != nil {
+			executeRollback(ctx, tx)
+			return err
+		}
+	}
+
+	// the acked messages are kept for the acked retention and the result retention of the queue.
+	sqlQuery = withCoalescedMessages(`
+	DELETE FROM messages USING queues
+	WHERE messages.queue_id = $1 AND queues.id = messages.queue_id AND (
+		(messages.state = 'acked' AND messages.acked_at + make_interval(secs => queues.acked_retention_seconds) <= $2
+			AND (messages.result_expired_at IS NULL OR messages.result_expired_at <= $2))
+		OR (messages.state <> 'acked' AND messages.expired_at <= $2)
+	)
+	RETURNING messages.num_coalesced
+	`)
+	if _, err := tx.Exec(ctx, sqlQuery, id, now); err != nil {
+		executeRollback(ctx, tx)
+		return err
+	}
+
+	sqlQuery = `DELETE FROM message_events WHERE queue_id = $1 AND expired_at <= $2`
+	if _, err := tx.Exec(ctx, sqlQuery, id, now); err != nil {
+		executeRollback(ctx, tx)
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (q *Queue) DeleteExpired(ctx context.Context) error {
+	sqlQuery := `DELETE FROM queues WHERE expired_at <= $1`
+	_, err := q.pool.Exec(ctx, sqlQuery, time.Now().UTC())
+	return err
+}
+
+func (q *Queue) Seek(ctx context.Context, queue *domain.Queue, timestamp time.Time) error {
+	// the messages are delivered again as new messages, keeping the delivery attempts and the errors. A unique key is held
+	// by one ready or in flight message, so only the last acked message of each key is delivered again and the keys held by
+	// a pending message are skipped.
+	now := time.Now().UTC()
+	expiredAt := now.Add(time.Duration(queue.MessageRetentionSeconds) * time.Second)
+	sqlQuery, args := withMessageEvents(`
+	UPDATE messages SET state = 'ready', acked_at = NULL, progress_percent = NULL, progress_note = NULL, result_status = NULL,
+		result = NULL, result_expired_at = NULL, scheduled_at = $3, expired_at = $4, updated_at = $3
+	WHERE id IN (
+		SELECT DISTINCT ON (COALESCE(acked.unique_key, acked.id)) acked.id FROM messages acked
+		WHERE acked.queue_id = $1 AND acked.state = 'acked' AND acked.acked_at >= $2 AND NOT EXISTS (
+			SELECT 1 FROM messages pending
+			WHERE pending.queue_id = $1 AND pending.unique_key = acked.unique_key AND pending.state IN ('ready', 'in_flight')
+		)
+		ORDER BY COALESCE(acked.unique_key, acked.id), acked.acked_at DESC
+	)
+	`, []interface{}{queue.ID, timestamp, now, expiredAt}, nil, now, domain.MessageEventTypeSeek)
+	_, err := q.pool.Exec(ctx, sqlQuery, args...)
+	// a message with the same unique key can be published concurrently.
+	return parseError(err, domain.ErrQueueNotFound, domain.ErrMessageDuplicated)
+}
+
+func (q *Queue) TakeDeliveries(ctx context.Context, queue *domain.Queue, n uint) (uint, time.Duration, error) {
+	tx, err := q.pool.Begin(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+
 	now := time.Now().UTC()
-	options := pgxutil.NewDeleteOptions().WithFilter("queue_id", id).WithFilter("expired_at.lte", now)
-	return pgxutil.DeleteWithOptions(ctx, q.pool, "messages", options)
+	rateLimit := domain.NewQueueRateLimit(queue, now)
+	sqlQuery := `INSERT INTO queue_rate_limits (queue_id, tokens, updated_at) VALUES ($1, $2, $3) ON CONFLICT (queue_id) DO NOTHING`
+	if _, err := tx.Exec(ctx, sqlQuery, rateLimit.QueueID, rateLimit.Tokens, rateLimit.UpdatedAt); err != nil {
+		executeRollback(ctx, tx)
+		return 0, 0, err
+	}
+
+	sqlQuery = `SELECT tokens, updated_at FROM queue_rate_limits WHERE queue_id = $1 FOR UPDATE`
+	if err := tx.QueryRow(ctx, sqlQuery, queue.ID).Scan(&rateLimit.Tokens, &rateLimit.UpdatedAt); err != nil {
+		executeRollback(ctx, tx)
+		return 0, 0, err
+	}
+
+	taken := rateLimit.Take(queue, n, now)
+	sqlQuery = `UPDATE queue_rate_limits SET tokens = $2, updated_at = $3 WHERE queue_id = $1`
+	if _, err := tx.Exec(ctx, sqlQuery, rateLimit.QueueID, rateLimit.Tokens, rateLimit.UpdatedAt); err != nil {
+		executeRollback(ctx, tx)
+		return 0, 0, err
+	}
+
+	return taken, rateLimit.RetryAfter(queue), tx.Commit(ctx)
+}
+
+func (q *Queue) ReturnDeliveries(ctx context.Context, queue *domain.Queue, n uint) error {
+	sqlQuery := `UPDATE queue_rate_limits SET tokens = LEAST(tokens + $2, $3) WHERE queue_id = $1`
+	_, err := q.pool.Exec(ctx, sqlQuery, queue.ID, float64(n), float64(queue.DeliveryBurst))
+	return err
+}
+
+// withCoalescedMessages wraps a delete of the messages of the queue $1 returning their num_coalesced, the coalesced publishes of the removed messages are added to the queue stats.
+func withCoalescedMessages(deleteQuery string) string {
+	return `
+	WITH deleted AS (` + deleteQuery + `)
+	INSERT INTO queue_stats (queue_id, num_coalesced_messages)
+	SELECT $1, SUM(num_coalesced) FROM deleted HAVING SUM(num_coalesced) > 0
+	ON CONFLICT (queue_id) DO UPDATE SET num_coalesced_messages = queue_stats.num_coalesced_messages + EXCLUDED.num_coalesced_messages
+	`
 }
 
 // NewQueue returns an implementation of domain.QueueRepository.
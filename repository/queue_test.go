@@ -190,4 +190,236 @@ func TestQueue(t *testing.T) {
 		err = queueRepo.Cleanup(ctx, queue.ID)
 		assert.Nil(t, err)
 	})
+
+	t.Run("DeleteExpired", func(t *testing.T) {
+		defer clearDatabase(t, ctx, pool)
+
+		now := time.Now().UTC()
+		queue := makeQueue("my-queue")
+		replyQueue := domain.NewReplyQueue(time.Second, now.Add(-time.Minute))
+		queueRepo := NewQueue(pool)
+
+		err := queueRepo.Create(ctx, queue)
+		assert.Nil(t, err)
+
+		err = queueRepo.Create(ctx, replyQueue)
+		assert.Nil(t, err)
+
+		err = queueRepo.Cleanup(ctx, queue.ID)
+		assert.Nil(t, err)
+
+		_, err = queueRepo.Get(ctx, replyQueue.ID)
+		assert.Nil(t, err)
+
+		err = queueRepo.DeleteExpired(ctx)
+		assert.Nil(t, err)
+
+		_, err = queueRepo.Get(ctx, replyQueue.ID)
+		assert.ErrorIs(t, err, domain.ErrQueueNotFound)
+		_, err = queueRepo.Get(ctx, queue.ID)
+		assert.Nil(t, err)
+	})
+
+	t.Run("Cleanup with delivery log", func(t *testing.T) {
+		defer clearDatabase(t, ctx, pool)
+
+		now := time.Now().UTC()
+		queue := makeQueue("my-queue")
+		queue.DeliveryLog = true
+		queue.SetDefaults()
+		queueRepo := NewQueue(pool)
+		messageRepo := NewMessage(pool)
+		messageEventRepo := NewMessageEvent(pool)
+		message := makeMessage(queue.ID)
+		message.Enqueue(queue, now)
+		message.ExpiredAt = now
+
+		err := queueRepo.Create(ctx, queue)
+		assert.Nil(t, err)
+
+		err = messageRepo.Create(ctx, message)
+		assert.Nil(t, err)
+
+		err = queueRepo.Cleanup(ctx, queue.ID)
+		assert.Nil(t, err)
+
+		events, err := messageEventRepo.ListByMessage(ctx, queue.ID, message.ID, 0, 10)
+		assert.Nil(t, err)
+		assert.Len(t, events, 2)
+		assert.Equal(t, domain.MessageEventTypeEnqueue, events[0].Type)
+		assert.Equal(t, domain.MessageEventTypeExpire, events[1].Type)
+	})
+
+	t.Run("Cleanup with result retention", func(t *testing.T) {
+		defer clearDatabase(t, ctx, pool)
+
+		now := time.Now().UTC()
+		queue := makeQueue("my-queue")
+		queue.ResultRetentionSeconds = 60
+		queueRepo := NewQueue(pool)
+		messageRepo := NewMessage(pool)
+		message := makeMessage(queue.ID)
+		message.Enqueue(queue, now)
+		message.AckWithResult(&domain.MessageResult{Status: domain.MessageResultStatusSucceeded}, queue, now)
+
+		err := queueRepo.Create(ctx, queue)
+		assert.Nil(t, err)
+
+		err = messageRepo.Create(ctx, message)
+		assert.Nil(t, err)
+
+		err = queueRepo.Cleanup(ctx, queue.ID)
+		assert.Nil(t, err)
+
+		_, err = messageRepo.Get(ctx, message.ID)
+		assert.Nil(t, err)
+	})
+
+	t.Run("Cleanup with acked retention", func(t *testing.T) {
+		defer clearDatabase(t, ctx, pool)
+
+		now := time.Now().UTC()
+		queue := makeQueue("my-queue")
+		queue.AckedRetentionSeconds = 60
+		queueRepo := NewQueue(pool)
+		messageRepo := NewMessage(pool)
+		ackedMessage := makeMessage(queue.ID)
+		ackedMessage.Enqueue(queue, now)
+		ackedMessage.DeliverySetup(queue, 0, now)
+		ackedMessage.Ack(now)
+		expiredMessage := makeMessage(queue.ID)
+		expiredMessage.Enqueue(queue, now)
+		expiredMessage.ExpiredAt = now
+
+		err := queueRepo.Create(ctx, queue)
+		assert.Nil(t, err)
+
+		err = messageRepo.CreateMany(ctx, []*domain.Message{ackedMessage, expiredMessage})
+		assert.Nil(t, err)
+
+		err = queueRepo.Cleanup(ctx, queue.ID)
+		assert.Nil(t, err)
+
+		_, err = messageRepo.Get(ctx, ackedMessage.ID)
+		assert.Nil(t, err)
+		_, err = messageRepo.Get(ctx, expiredMessage.ID)
+		assert.ErrorIs(t, err, domain.ErrMessageNotFound)
+
+		stats, err := queueRepo.Stats(ctx, queue.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, uint(1), stats.NumAckedMessages)
+		assert.Equal(t, uint(1), stats.NumExpiredMessages)
+	})
+
+	t.Run("Seek", func(t *testing.T) {
+		defer clearDatabase(t, ctx, pool)
+
+		now := time.Now().UTC()
+		queue := makeQueue("my-queue")
+		queue.AckedRetentionSeconds = 3600
+		queueRepo := NewQueue(pool)
+		messageRepo := NewMessage(pool)
+		oldMessage := makeMessage(queue.ID)
+		oldMessage.Enqueue(queue, now.Add(-time.Hour))
+		oldMessage.DeliverySetup(queue, 0, now.Add(-time.Hour))
+		oldMessage.Ack(now.Add(-time.Hour))
+		newMessage := makeMessage(queue.ID)
+		newMessage.Enqueue(queue, now.Add(-time.Minute))
+		newMessage.DeliverySetup(queue, 0, now.Add(-time.Minute))
+		newMessage.Ack(now.Add(-time.Minute))
+
+		err := queueRepo.Create(ctx, queue)
+		assert.Nil(t, err)
+
+		err = messageRepo.CreateMany(ctx, []*domain.Message{oldMessage, newMessage})
+		assert.Nil(t, err)
+
+		err = queueRepo.Seek(ctx, queue, now.Add(-10*time.Minute))
+		assert.Nil(t, err)
+
+		messages, err := messageRepo.List(ctx, queue, nil, 10, 0, domain.MessageAckModeManual, nil)
+		assert.Nil(t, err)
+		assert.Len(t, messages, 1)
+		assert.Equal(t, newMessage.ID, messages[0].ID)
+		assert.Equal(t, uint(2), messages[0].DeliveryAttempts)
+	})
+
+	t.Run("Seek with unique key", func(t *testing.T) {
+		defer clearDatabase(t, ctx, pool)
+
+		now := time.Now().UTC()
+		queue := makeQueue("my-queue")
+		queue.AckedRetentionSeconds = 3600
+		queueRepo := NewQueue(pool)
+		messageRepo := NewMessage(pool)
+		ackedMessages := []*domain.Message{}
+		for _, uniqueKey := range []string{"order-1", "order-1", "order-2"} {
+			message := makeMessage(queue.ID)
+			message.UniqueKey = pointString(uniqueKey)
+			message.Enqueue(queue, now.Add(-time.Minute))
+			message.DeliverySetup(queue, 0, now.Add(-time.Minute))
+			message.Ack(now.Add(-time.Minute))
+			ackedMessages = append(ackedMessages, message)
+		}
+		pendingMessage := makeMessage(queue.ID)
+		pendingMessage.UniqueKey = pointString("order-2")
+		pendingMessage.Enqueue(queue, now)
+
+		err := queueRepo.Create(ctx, queue)
+		assert.Nil(t, err)
+
+		err = messageRepo.CreateMany(ctx, append(ackedMessages, pendingMessage))
+		assert.Nil(t, err)
+
+		err = queueRepo.Seek(ctx, queue, now.Add(-10*time.Minute))
+		assert.Nil(t, err)
+
+		messages, err := messageRepo.List(ctx, queue, nil, 10, 0, domain.MessageAckModeManual, nil)
+		assert.Nil(t, err)
+		assert.Len(t, messages, 2)
+	})
+
+	t.Run("TakeDeliveries", func(t *testing.T) {
+		defer clearDatabase(t, ctx, pool)
+
+		queue := makeQueue("my-queue")
+		queue.MaxDeliveriesPerSecond = 1
+		queue.DeliveryBurst = 2
+		queueRepo := NewQueue(pool)
+
+		err := queueRepo.Create(ctx, queue)
+		assert.Nil(t, err)
+
+		taken, retryAfter, err := queueRepo.TakeDeliveries(ctx, queue, 10)
+		assert.Nil(t, err)
+		assert.Equal(t, uint(2), taken)
+		assert.Greater(t, retryAfter, time.Duration(0))
+
+		taken, _, err = queueRepo.TakeDeliveries(ctx, queue, 10)
+		assert.Nil(t, err)
+		assert.Equal(t, uint(0), taken)
+	})
+
+	t.Run("ReturnDeliveries", func(t *testing.T) {
+		defer clearDatabase(t, ctx, pool)
+
+		queue := makeQueue("my-queue")
+		queue.MaxDeliveriesPerSecond = 1
+		queue.DeliveryBurst = 2
+		queueRepo := NewQueue(pool)
+
+		err := queueRepo.Create(ctx, queue)
+		assert.Nil(t, err)
+
+		taken, _, err := queueRepo.TakeDeliveries(ctx, queue, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, uint(2), taken)
+
+		err = queueRepo.ReturnDeliveries(ctx, queue, 1)
+		assert.Nil(t, err)
+
+		taken, _, err = queueRepo.TakeDeliveries(ctx, queue, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, uint(1), taken)
+	})
 }
@@ -19,6 +19,10 @@ func (s *Subscription) Create(ctx context.Context, subscription *domain.Subscrip
 	return parseError(pgxutil.Insert(ctx, s.pool, "", s.tableName, subscription), domain.ErrSubscriptionNotFound, domain.ErrSubscriptionAlreadyExists)
 }
 
+func (s *Subscription) Update(ctx context.Context, subscription *domain.Subscription) error {
+	return parseError(pgxutil.Update(ctx, s.pool, "", s.tableName, subscription.ID, subscription), domain.ErrSubscriptionNotFound, domain.ErrSubscriptionAlreadyExists)
+}
+
 func (s *Subscription) Get(ctx context.Context, id string) (*domain.Subscription, error) {
 	subscription := domain.Subscription{}
 	options := pgxutil.NewFindOptions().WithFilter("id", id)
@@ -40,6 +44,13 @@ func (s *Subscription) ListByTopic(ctx context.Context, topicID string, offset,
 	return subscriptions, parseError(err, domain.ErrSubscriptionNotFound, domain.ErrSubscriptionAlreadyExists)
 }
 
+func (s *Subscription) ListByQueue(ctx context.Context, queueID string, offset, limit uint) ([]*domain.Subscription, error) {
+	subscriptions := []*domain.Subscription{}
+	options := pgxutil.NewFindAllOptions().WithFilter("queue_id", queueID).WithOffset(int(offset)).WithLimit(int(limit)).WithOrderBy("id asc")
+	err := pgxutil.Select(ctx, s.pool, s.tableName, options, &subscriptions)
+	return subscriptions, parseError(err, domain.ErrSubscriptionNotFound, domain.ErrSubscriptionAlreadyExists)
+}
+
 func (s *Subscription) Delete(ctx context.Context, id string) error {
 	return parseError(pgxutil.Delete(ctx, s.pool, s.tableName, id), domain.ErrSubscriptionNotFound, domain.ErrSubscriptionAlreadyExists)
 }
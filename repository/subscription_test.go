@@ -15,9 +15,10 @@ func makeSubscription(id, topicID, queueID string, messageFilters map[string][]s
 	return &domain.Subscription{
 		ID:             id,
 		TopicID:        topicID,
-		QueueID:        queueID,
+		QueueID:        &queueID,
 		MessageFilters: messageFilters,
 		CreatedAt:      time.Now().UTC(),
+		UpdatedAt:      time.Now().UTC(),
 	}
 }
 
@@ -54,6 +55,34 @@ func TestSubscription(t *testing.T) {
 		assert.ErrorIs(t, err, domain.ErrSubscriptionAlreadyExists)
 	})
 
+	t.Run("Update", func(t *testing.T) {
+		defer clearDatabase(t, ctx, pool)
+
+		topic := makeTopic("my-topic")
+		topicRepo := NewTopic(pool)
+		queue := makeQueue("my-queue")
+		queueRepo := NewQueue(pool)
+		subscription := makeSubscription("my-subscription", topic.ID, queue.ID, nil)
+		subscriptionRepo := NewSubscription(pool)
+
+		err := topicRepo.Create(ctx, topic)
+		assert.Nil(t, err)
+
+		err = queueRepo.Create(ctx, queue)
+		assert.Nil(t, err)
+
+		err = subscriptionRepo.Create(ctx, subscription)
+		assert.Nil(t, err)
+
+		subscription.MessageFilters = map[string][]string{"status": {"processed"}}
+		err = subscriptionRepo.Update(ctx, subscription)
+		assert.Nil(t, err)
+
+		subscriptionFromDB, err := subscriptionRepo.Get(ctx, subscription.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, map[string][]string{"status": {"processed"}}, subscriptionFromDB.MessageFilters)
+	})
+
 	t.Run("Get", func(t *testing.T) {
 		defer clearDatabase(t, ctx, pool)
 
@@ -80,6 +109,60 @@ func TestSubscription(t *testing.T) {
 		assert.ErrorIs(t, err, domain.ErrSubscriptionNotFound)
 	})
 
+	t.Run("Create with target topic", func(t *testing.T) {
+		defer clearDatabase(t, ctx, pool)
+
+		topic := makeTopic("my-topic")
+		targetTopic := makeTopic("my-target-topic")
+		topicRepo := NewTopic(pool)
+		subscriptionRepo := NewSubscription(pool)
+
+		err := topicRepo.Create(ctx, topic)
+		assert.Nil(t, err)
+
+		err = topicRepo.Create(ctx, targetTopic)
+		assert.Nil(t, err)
+
+		subscription := &domain.Subscription{ID: "my-subscription", TopicID: topic.ID, TargetTopicID: &targetTopic.ID, CreatedAt: time.Now().UTC(), UpdatedAt: time.Now().UTC()}
+		err = subscriptionRepo.Create(ctx, subscription)
+		assert.Nil(t, err)
+
+		subscription.ID = "another-subscription-with-same-topic-id-and-target-topic-id"
+		err = subscriptionRepo.Create(ctx, subscription)
+		assert.ErrorIs(t, err, domain.ErrSubscriptionAlreadyExists)
+
+		subscription, err = subscriptionRepo.Get(ctx, "my-subscription")
+		assert.Nil(t, err)
+		assert.Nil(t, subscription.QueueID)
+		assert.Equal(t, &targetTopic.ID, subscription.TargetTopicID)
+	})
+
+	t.Run("Get with transformation", func(t *testing.T) {
+		defer clearDatabase(t, ctx, pool)
+
+		topic := makeTopic("my-topic")
+		topicRepo := NewTopic(pool)
+		queue := makeQueue("my-queue")
+		queueRepo := NewQueue(pool)
+		subscriptionRepo := NewSubscription(pool)
+		transformation := &domain.SubscriptionTransformation{SetAttributes: map[string]string{"source": "orders"}, Envelope: true}
+
+		err := topicRepo.Create(ctx, topic)
+		assert.Nil(t, err)
+
+		err = queueRepo.Create(ctx, queue)
+		assert.Nil(t, err)
+
+		subscription := makeSubscription("my-subscription", topic.ID, queue.ID, nil)
+		subscription.Transformation = transformation
+		err = subscriptionRepo.Create(ctx, subscription)
+		assert.Nil(t, err)
+
+		subscription, err = subscriptionRepo.Get(ctx, "my-subscription")
+		assert.Nil(t, err)
+		assert.Equal(t, transformation, subscription.Transformation)
+	})
+
 	t.Run("List", func(t *testing.T) {
 		defer clearDatabase(t, ctx, pool)
 
@@ -150,6 +233,46 @@ func TestSubscription(t *testing.T) {
 		assert.Equal(t, "my-subscription-2", subscriptions[0].ID)
 	})
 
+	t.Run("ListByQueue", func(t *testing.T) {
+		defer clearDatabase(t, ctx, pool)
+
+		topic1 := makeTopic("my-topic-1")
+		topic2 := makeTopic("my-topic-2")
+		topicRepo := NewTopic(pool)
+		queue1 := makeQueue("my-queue-1")
+		queue2 := makeQueue("my-queue-2")
+		queueRepo := NewQueue(pool)
+		subscriptionRepo := NewSubscription(pool)
+
+		err := topicRepo.Create(ctx, topic1)
+		assert.Nil(t, err)
+		err = topicRepo.Create(ctx, topic2)
+		assert.Nil(t, err)
+
+		err = queueRepo.Create(ctx, queue1)
+		assert.Nil(t, err)
+		err = queueRepo.Create(ctx, queue2)
+		assert.Nil(t, err)
+
+		err = subscriptionRepo.Create(ctx, makeSubscription("my-subscription-1", topic1.ID, queue1.ID, nil))
+		assert.Nil(t, err)
+		err = subscriptionRepo.Create(ctx, makeSubscription("my-subscription-2", topic2.ID, queue1.ID, nil))
+		assert.Nil(t, err)
+		err = subscriptionRepo.Create(ctx, makeSubscription("my-subscription-3", topic2.ID, queue2.ID, nil))
+		assert.Nil(t, err)
+
+		subscriptions, err := subscriptionRepo.ListByQueue(ctx, queue1.ID, uint(0), uint(10))
+		assert.Nil(t, err)
+		assert.Len(t, subscriptions, 2)
+		assert.Equal(t, "my-subscription-1", subscriptions[0].ID)
+		assert.Equal(t, "my-subscription-2", subscriptions[1].ID)
+
+		subscriptions, err = subscriptionRepo.ListByQueue(ctx, queue2.ID, uint(0), uint(10))
+		assert.Nil(t, err)
+		assert.Len(t, subscriptions, 1)
+		assert.Equal(t, "my-subscription-3", subscriptions[0].ID)
+	})
+
 	t.Run("Delete", func(t *testing.T) {
 		defer clearDatabase(t, ctx, pool)
 
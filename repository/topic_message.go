@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/allisson/pgxutil/v2"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/allisson/psqlqueue/domain"
+)
+
+// TopicMessage is an implementation of domain.TopicMessageRepository.
+type TopicMessage struct {
+	pool      *pgxpool.Pool
+	tableName string
+}
+
+func (t *TopicMessage) Publish(ctx context.Context, batch *domain.TopicPublishBatch) error {
+	tx, err := t.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i := range batch.TopicMessages {
+		topicMessage := batch.TopicMessages[i]
+
+		if err := pgxutil.Insert(ctx, tx, "", t.tableName, topicMessage); err != nil {
+			executeRollback(ctx, tx)
+			return parseError(err, domain.ErrTopicMessageNotFound, domain.ErrTopicMessageAlreadyExists)
+		}
+	}
+
+	for i := range batch.Messages {
+		message := batch.Messages[i]
+
+		if err := insertMessage(ctx, tx, message); err != nil {
+			executeRollback(ctx, tx)
+			return parseError(err, domain.ErrMessageNotFound, domain.ErrMessageAlreadyExists)
+		}
+	}
+
+	if err := insertTopicStats(ctx, tx, batch.TopicCounters, batch.SubscriptionCounters); err != nil {
+		executeRollback(ctx, tx)
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (t *TopicMessage) ListByTopic(ctx context.Context, topicID string, from, to time.Time, offset, limit uint) ([]*domain.TopicMessage, error) {
+	topicMessages := []*domain.TopicMessage{}
+	now := time.Now().UTC()
+	options := pgxutil.NewFindAllOptions().
+		WithFilter("topic_id", topicID).
+		WithFilter("created_at.gte", from).
+		WithFilter("created_at.lte", to).
+		WithFilter("expired_at.gte", now).
+		WithOffset(int(offset)).
+		WithLimit(int(limit)).
+		WithOrderBy("created_at asc, id asc")
+	err := pgxutil.Select(ctx, t.pool, t.tableName, options, &topicMessages)
+	return topicMessages, parseError(err, domain.ErrTopicMessageNotFound, domain.ErrTopicMessageAlreadyExists)
+}
+
+func (t *TopicMessage) Cleanup(ctx context.Context, topicID string, statsBefore time.Time) error {
+	tx, err := t.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	options := pgxutil.NewDeleteOptions().WithFilter("topic_id", topicID).WithFilter("expired_at.lte", now)
+	if err := pgxutil.DeleteWithOptions(ctx, tx, t.tableName, options); err != nil {
+		executeRollback(ctx, tx)
+		return err
+	}
+
+	if err := deleteTopicStats(ctx, tx, topicID, statsBefore); err != nil {
+		executeRollback(ctx, tx)
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// NewTopicMessage returns an implementation of domain.TopicMessageRepository.
+func NewTopicMessage(pool *pgxpool.Pool) *TopicMessage {
+	return &TopicMessage{pool: pool, tableName: "topic_messages"}
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/allisson/psqlqueue/domain"
+)
+
+func makeTopicMessage(id, topicID string, createdAt time.Time, retentionSeconds uint) *domain.TopicMessage {
+	return &domain.TopicMessage{
+		ID:         id,
+		TopicID:    topicID,
+		Body:       `{"data": true}`,
+		Attributes: map[string]string{"attribute1": "attribute1"},
+		ExpiredAt:  createdAt.Add(time.Duration(retentionSeconds) * time.Second),
+		CreatedAt:  createdAt,
+	}
+}
+
+func TestTopicMessage(t *testing.T) {
+	cfg := domain.NewConfig()
+	ctx := context.Background()
+	pool, _ := pgxpool.New(ctx, cfg.TestDatabaseURL)
+	defer pool.Close()
+
+	t.Run("Publish", func(t *testing.T) {
+		defer clearDatabase(t, ctx, pool)
+
+		now := time.Now().UTC()
+		topic := makeTopic("my-topic")
+		queue := makeQueue("my-queue")
+		message := makeMessage(queue.ID)
+		message.Enqueue(queue, now)
+		topicMessage := makeTopicMessage("my-topic-message", topic.ID, now, 3600)
+		topicRepo := NewTopic(pool)
+		queueRepo := NewQueue(pool)
+		messageRepo := NewMessage(pool)
+		topicMessageRepo := NewTopicMessage(pool)
+
+		err := topicRepo.Create(ctx, topic)
+		assert.Nil(t, err)
+		err = queueRepo.Create(ctx, queue)
+		assert.Nil(t, err)
+
+		err = topicMessageRepo.Publish(ctx, &domain.TopicPublishBatch{TopicMessages: []*domain.TopicMessage{topicMessage}, Messages: []*domain.Message{message}})
+		assert.Nil(t, err)
+
+		_, err = messageRepo.Get(ctx, message.ID)
+		assert.Nil(t, err)
+
+		err = topicMessageRepo.Publish(ctx, &domain.TopicPublishBatch{TopicMessages: []*domain.TopicMessage{topicMessage}})
+		assert.ErrorIs(t, err, domain.ErrTopicMessageAlreadyExists)
+	})
+
+	t.Run("ListByTopic", func(t *testing.T) {
+		defer clearDatabase(t, ctx, pool)
+
+		now := time.Now().UTC()
+		topic := makeTopic("my-topic")
+		topicRepo := NewTopic(pool)
+		topicMessageRepo := NewTopicMessage(pool)
+
+		err := topicRepo.Create(ctx, topic)
+		assert.Nil(t, err)
+		err = topicMessageRepo.Publish(ctx, &domain.TopicPublishBatch{TopicMessages: []*domain.TopicMessage{makeTopicMessage("my-topic-message-1", topic.ID, now.Add(-2*time.Hour), 86400)}})
+		assert.Nil(t, err)
+		err = topicMessageRepo.Publish(ctx, &domain.TopicPublishBatch{TopicMessages: []*domain.TopicMessage{makeTopicMessage("my-topic-message-2", topic.ID, now.Add(-time.Hour), 86400)}})
+		assert.Nil(t, err)
+		err = topicMessageRepo.Publish(ctx, &domain.TopicPublishBatch{TopicMessages: []*domain.TopicMessage{makeTopicMessage("my-topic-message-3", topic.ID, now.Add(-time.Hour), 1)}})
+		assert.Nil(t, err)
+
+		topicMessages, err := topicMessageRepo.ListByTopic(ctx, topic.ID, now.Add(-90*time.Minute), now, uint(0), uint(10))
+		assert.Nil(t, err)
+		assert.Len(t, topicMessages, 1)
+		assert.Equal(t, "my-topic-message-2", topicMessages[0].ID)
+	})
+
+	t.Run("Cleanup", func(t *testing.T) {
+		defer clearDatabase(t, ctx, pool)
+
+		now := time.Now().UTC()
+		topic := makeTopic("my-topic")
+		topicRepo := NewTopic(pool)
+		topicMessageRepo := NewTopicMessage(pool)
+
+		err := topicRepo.Create(ctx, topic)
+		assert.Nil(t, err)
+		err = topicMessageRepo.Publish(ctx, &domain.TopicPublishBatch{TopicMessages: []*domain.TopicMessage{makeTopicMessage("my-topic-message-1", topic.ID, now.Add(-time.Hour), 86400)}})
+		assert.Nil(t, err)
+		err = topicMessageRepo.Publish(ctx, &domain.TopicPublishBatch{TopicMessages: []*domain.TopicMessage{makeTopicMessage("my-topic-message-2", topic.ID, now.Add(-time.Hour), 1)}})
+		assert.Nil(t, err)
+
+		err = topicMessageRepo.Cleanup(ctx, topic.ID, now.Add(-24*time.Hour))
+		assert.Nil(t, err)
+
+		topicMessages, err := topicMessageRepo.ListByTopic(ctx, topic.ID, now.Add(-2*time.Hour), now, uint(0), uint(10))
+		assert.Nil(t, err)
+		assert.Len(t, topicMessages, 1)
+		assert.Equal(t, "my-topic-message-1", topicMessages[0].ID)
+	})
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/allisson/psqlqueue/domain"
+)
+
+// TopicStats is an implementation of domain.TopicStatsRepository.
+type TopicStats struct {
+	pool *pgxpool.Pool
+}
+
+func (t *TopicStats) Stats(ctx context.Context, topicID string, since time.Time) (*domain.TopicStatsWindow, error) {
+	window := &domain.TopicStatsWindow{Subscriptions: []*domain.SubscriptionStats{}}
+
+	sqlQuery := `SELECT COALESCE(SUM(num_published_messages), 0) FROM topic_stats WHERE topic_id = $1 AND bucket >= $2`
+	if err := t.pool.QueryRow(ctx, sqlQuery, topicID, since).Scan(&window.NumPublishedMessages); err != nil {
+		return nil, err
+	}
+
+	sqlQuery = `
+	SELECT subscription_id, SUM(num_delivered_messages) AS num_delivered_messages, SUM(num_filtered_messages) AS num_filtered_messages
+	FROM subscription_stats
+	WHERE topic_id = $1 AND bucket >= $2
+	GROUP BY subscription_id
+	ORDER BY subscription_id ASC
+	`
+	rows, err := t.pool.Query(ctx, sqlQuery, topicID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	subscriptions, err := pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[domain.SubscriptionStats])
+	if err != nil {
+		return nil, err
+	}
+	window.Subscriptions = append(window.Subscriptions, subscriptions...)
+
+	return window, nil
+}
+
+// insertTopicStats appends the counters of a publish, the rows of a bucket are summed when reading the stats so concurrent publishes do not wait on each other.
+func insertTopicStats(ctx context.Context, tx pgx.Tx, topicCounters []*domain.TopicCounter, subscriptionCounters []*domain.SubscriptionCounter) error {
+	sqlQuery := `INSERT INTO topic_stats (topic_id, bucket, num_published_messages) VALUES ($1, $2, $3)`
+	for i := range topicCounters {
+		counter := topicCounters[i]
+
+		if _, err := tx.Exec(ctx, sqlQuery, counter.TopicID, counter.Bucket, counter.NumPublishedMessages); err != nil {
+			return err
+		}
+	}
+
+	sqlQuery = `INSERT INTO subscription_stats (subscription_id, topic_id, bucket, num_delivered_messages, num_filtered_messages) VALUES ($1, $2, $3, $4, $5)`
+	for i := range subscriptionCounters {
+		counter := subscriptionCounters[i]
+
+		if _, err := tx.Exec(ctx, sqlQuery, counter.SubscriptionID, counter.TopicID, counter.Bucket, counter.NumDeliveredMessages, counter.NumFilteredMessages); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deleteTopicStats removes the stats of the topic older than before.
+func deleteTopicStats(ctx context.Context, tx pgx.Tx, topicID string, before time.Time) error {
+	sqlQuery := `DELETE FROM topic_stats WHERE topic_id = $1 AND bucket < $2`
+	if _, err := tx.Exec(ctx, sqlQuery, topicID, before); err != nil {
+		return err
+	}
+
+	sqlQuery = `DELETE FROM subscription_stats WHERE topic_id = $1 AND bucket < $2`
+	_, err := tx.Exec(ctx, sqlQuery, topicID, before)
+	return err
+}
+
+// NewTopicStats returns an implementation of domain.TopicStatsRepository.
+func NewTopicStats(pool *pgxpool.Pool) *TopicStats {
+	return &TopicStats{pool: pool}
+}
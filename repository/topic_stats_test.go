@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/allisson/psqlqueue/domain"
+)
+
+func TestTopicStats(t *testing.T) {
+	cfg := domain.NewConfig()
+	ctx := context.Background()
+	pool, _ := pgxpool.New(ctx, cfg.TestDatabaseURL)
+	defer pool.Close()
+
+	setup := func(t *testing.T) (*domain.Topic, *domain.Subscription) {
+		topic := makeTopic("my-topic")
+		queue := makeQueue("my-queue")
+		subscription := makeSubscription("my-subscription", topic.ID, queue.ID, nil)
+
+		err := NewTopic(pool).Create(ctx, topic)
+		assert.Nil(t, err)
+		err = NewQueue(pool).Create(ctx, queue)
+		assert.Nil(t, err)
+		err = NewSubscription(pool).Create(ctx, subscription)
+		assert.Nil(t, err)
+
+		return topic, subscription
+	}
+
+	t.Run("Publish", func(t *testing.T) {
+		defer clearDatabase(t, ctx, pool)
+
+		topic, subscription := setup(t)
+		bucket := time.Now().UTC().Truncate(time.Minute)
+		topicMessageRepo := NewTopicMessage(pool)
+		topicStatsRepo := NewTopicStats(pool)
+
+		for i := 0; i < 2; i++ {
+			err := topicMessageRepo.Publish(ctx, &domain.TopicPublishBatch{
+				TopicCounters:        []*domain.TopicCounter{{TopicID: topic.ID, Bucket: bucket, NumPublishedMessages: 1}},
+				SubscriptionCounters: []*domain.SubscriptionCounter{{SubscriptionID: subscription.ID, TopicID: topic.ID, Bucket: bucket, NumDeliveredMessages: 1}},
+			})
+			assert.Nil(t, err)
+		}
+
+		window, err := topicStatsRepo.Stats(ctx, topic.ID, bucket)
+		assert.Nil(t, err)
+		assert.Equal(t, uint(2), window.NumPublishedMessages)
+		assert.Len(t, window.Subscriptions, 1)
+		assert.Equal(t, subscription.ID, window.Subscriptions[0].SubscriptionID)
+		assert.Equal(t, uint(2), window.Subscriptions[0].NumDeliveredMessages)
+		assert.Equal(t, uint(0), window.Subscriptions[0].NumFilteredMessages)
+	})
+
+	t.Run("Stats", func(t *testing.T) {
+		defer clearDatabase(t, ctx, pool)
+
+		topic, subscription := setup(t)
+		bucket := time.Now().UTC().Truncate(time.Minute)
+		oldBucket := bucket.Add(-time.Hour)
+		topicMessageRepo := NewTopicMessage(pool)
+		topicStatsRepo := NewTopicStats(pool)
+
+		err := topicMessageRepo.Publish(ctx, &domain.TopicPublishBatch{
+			TopicCounters:        []*domain.TopicCounter{{TopicID: topic.ID, Bucket: bucket, NumPublishedMessages: 1}, {TopicID: topic.ID, Bucket: oldBucket, NumPublishedMessages: 1}},
+			SubscriptionCounters: []*domain.SubscriptionCounter{{SubscriptionID: subscription.ID, TopicID: topic.ID, Bucket: bucket, NumFilteredMessages: 1}, {SubscriptionID: subscription.ID, TopicID: topic.ID, Bucket: oldBucket, NumFilteredMessages: 1}},
+		})
+		assert.Nil(t, err)
+
+		window, err := topicStatsRepo.Stats(ctx, topic.ID, bucket.Add(-5*time.Minute))
+		assert.Nil(t, err)
+		assert.Equal(t, uint(1), window.NumPublishedMessages)
+		assert.Equal(t, uint(1), window.Subscriptions[0].NumFilteredMessages)
+
+		window, err = topicStatsRepo.Stats(ctx, topic.ID, bucket.Add(-24*time.Hour))
+		assert.Nil(t, err)
+		assert.Equal(t, uint(2), window.NumPublishedMessages)
+		assert.Equal(t, uint(2), window.Subscriptions[0].NumFilteredMessages)
+	})
+
+	t.Run("Cleanup", func(t *testing.T) {
+		defer clearDatabase(t, ctx, pool)
+
+		topic, subscription := setup(t)
+		bucket := time.Now().UTC().Truncate(time.Minute)
+		oldBucket := bucket.Add(-48 * time.Hour)
+		topicMessageRepo := NewTopicMessage(pool)
+		topicStatsRepo := NewTopicStats(pool)
+
+		err := topicMessageRepo.Publish(ctx, &domain.TopicPublishBatch{
+			TopicCounters:        []*domain.TopicCounter{{TopicID: topic.ID, Bucket: bucket, NumPublishedMessages: 1}, {TopicID: topic.ID, Bucket: oldBucket, NumPublishedMessages: 1}},
+			SubscriptionCounters: []*domain.SubscriptionCounter{{SubscriptionID: subscription.ID, TopicID: topic.ID, Bucket: oldBucket, NumDeliveredMessages: 1}},
+		})
+		assert.Nil(t, err)
+
+		err = topicMessageRepo.Cleanup(ctx, topic.ID, bucket.Add(-24*time.Hour))
+		assert.Nil(t, err)
+
+		window, err := topicStatsRepo.Stats(ctx, topic.ID, oldBucket)
+		assert.Nil(t, err)
+		assert.Equal(t, uint(1), window.NumPublishedMessages)
+		assert.Len(t, window.Subscriptions, 0)
+	})
+}
@@ -50,6 +50,28 @@ func TestTopic(t *testing.T) {
 		assert.ErrorIs(t, err, domain.ErrTopicNotFound)
 	})
 
+	t.Run("Get with ingestion", func(t *testing.T) {
+		defer clearDatabase(t, ctx, pool)
+
+		signatureHeader := "X-Signature"
+		signatureSecret := "my-secret"
+		topic := makeTopic("my-topic")
+		topic.Ingestion = &domain.TopicIngestion{
+			HeaderAttributes: map[string]string{"X-Event": "event"},
+			SignatureHeader:  &signatureHeader,
+			SignaturePrefix:  "sha256=",
+			SignatureSecret:  &signatureSecret,
+		}
+		topicRepo := NewTopic(pool)
+
+		err := topicRepo.Create(ctx, topic)
+		assert.Nil(t, err)
+
+		topicFromDB, err := topicRepo.Get(ctx, topic.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, topic.Ingestion, topicFromDB.Ingestion)
+	})
+
 	t.Run("List", func(t *testing.T) {
 		defer clearDatabase(t, ctx, pool)
 
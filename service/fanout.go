@@ -0,0 +1,255 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/allisson/psqlqueue/domain"
+)
+
+// fanout routes the messages published to a topic through its subscriptions.
+type fanout struct {
+	topicRepository        domain.TopicRepository
+	subscriptionRepository domain.SubscriptionRepository
+	queueRepository        domain.QueueRepository
+	maxHops                uint
+}
+
+// fanoutPublish holds the data shared by all the messages created by a fan-out, the topicID is the topic the message was published to and stays the source topic of the messages on every hop.
+type fanoutPublish struct {
+	id            string
+	topicID       string
+	publishedAt   time.Time
+	now           time.Time
+	counters      *fanoutCounters
+	topicMessages []*domain.TopicMessage
+	// a topic or a queue reached by more than one path (A->B, A->C, B->Q, C->Q) receives the publish only once.
+	routedTopics    map[string]bool
+	deliveredQueues map[string]bool
+}
+
+func newFanoutPublish(id, topicID string, publishedAt, now time.Time, counters *fanoutCounters) *fanoutPublish {
+	return &fanoutPublish{
+		id:              id,
+		topicID:         topicID,
+		publishedAt:     publishedAt,
+		now:             now,
+		counters:        counters,
+		topicMessages:   []*domain.TopicMessage{},
+		routedTopics:    map[string]bool{},
+		deliveredQueues: map[string]bool{},
+	}
+}
+
+// fanoutCounters accumulates the number of published, delivered and filtered out messages of a fan-out.
+type fanoutCounters struct {
+	bucket        time.Time
+	topics        map[string]*domain.TopicCounter
+	subscriptions map[string]*domain.SubscriptionCounter
+}
+
+func (f *fanoutCounters) published(topicID string) {
+	if f == nil {
+		return
+	}
+
+	if _, ok := f.topics[topicID]; !ok {
+		f.topics[topicID] = &domain.TopicCounter{TopicID: topicID, Bucket: f.bucket}
+	}
+	f.topics[topicID].NumPublishedMessages++
+}
+
+func (f *fanoutCounters) subscription(subscription *domain.Subscription) *domain.SubscriptionCounter {
+	if _, ok := f.subscriptions[subscription.ID]; !ok {
+		f.subscriptions[subscription.ID] = &domain.SubscriptionCounter{SubscriptionID: subscription.ID, TopicID: subscription.TopicID, Bucket: f.bucket}
+	}
+	return f.subscriptions[subscription.ID]
+}
+
+func (f *fanoutCounters) delivered(subscription *domain.Subscription) {
+	if f == nil {
+		return
+	}
+
+	f.subscription(subscription).NumDeliveredMessages++
+}
+
+func (f *fanoutCounters) filtered(subscription *domain.Subscription) {
+	if f == nil {
+		return
+	}
+
+	f.subscription(subscription).NumFilteredMessages++
+}
+
+// counters returns the accumulated counters sorted by id.
+func (f *fanoutCounters) counters() ([]*domain.TopicCounter, []*domain.SubscriptionCounter) {
+	topicCounters := []*domain.TopicCounter{}
+	for _, counter := range f.topics {
+		topicCounters = append(topicCounters, counter)
+	}
+	sort.Slice(topicCounters, func(i, j int) bool { return topicCounters[i].TopicID < topicCounters[j].TopicID })
+
+	subscriptionCounters := []*domain.SubscriptionCounter{}
+	for _, counter := range f.subscriptions {
+		subscriptionCounters = append(subscriptionCounters, counter)
+	}
+	sort.Slice(subscriptionCounters, func(i, j int) bool {
+		return subscriptionCounters[i].SubscriptionID < subscriptionCounters[j].SubscriptionID
+	})
+
+	return topicCounters, subscriptionCounters
+}
+
+func newFanoutCounters(now time.Time) *fanoutCounters {
+	return &fanoutCounters{
+		bucket:        now.Truncate(time.Minute),
+		topics:        map[string]*domain.TopicCounter{},
+		subscriptions: map[string]*domain.SubscriptionCounter{},
+	}
+}
+
+func (f *fanout) listSubscriptions(ctx context.Context, topicID string) ([]*domain.Subscription, error) {
+	subscriptions := []*domain.Subscription{}
+	offset := 0
+	limit := 50
+
+	for {
+		page, err := f.subscriptionRepository.ListByTopic(ctx, topicID, uint(offset), uint(limit))
+		if err != nil {
+			return nil, err
+		}
+
+		if len(page) == 0 {
+			break
+		}
+
+		subscriptions = append(subscriptions, page...)
+		offset += limit
+	}
+
+	return subscriptions, nil
+}
+
+// route returns the number of matched subscriptions of the topic and the messages that must be enqueued for them, the
+// message is kept on the topic message log when the topic has a message retention.
+func (f *fanout) route(ctx context.Context, publish *fanoutPublish, topic *domain.Topic, message *domain.Message, hops uint) (uint, []*domain.Message, error) {
+	if publish.routedTopics[topic.ID] {
+		return 0, []*domain.Message{}, nil
+	}
+	publish.routedTopics[topic.ID] = true
+
+	subscriptions, err := f.listSubscriptions(ctx, topic.ID)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	publish.counters.published(topic.ID)
+	if topic.MessageRetentionSeconds > 0 {
+		publish.topicMessages = append(publish.topicMessages, domain.NewTopicMessage(topic, message, publish.now))
+	}
+
+	numMatchedSubscriptions := uint(0)
+	messages := []*domain.Message{}
+
+	for i := range subscriptions {
+		subscription := subscriptions[i]
+		if !subscription.ShouldCreateMessage(message) {
+			publish.counters.filtered(subscription)
+			continue
+		}
+
+		newMessages, err := f.deliver(ctx, publish, subscription, message, hops)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		if subscription.QueueID == nil || len(newMessages) > 0 {
+			publish.counters.delivered(subscription)
+		}
+
+		numMatchedSubscriptions++
+		messages = append(messages, newMessages...)
+	}
+
+	return numMatchedSubscriptions, messages, nil
+}
+
+// deliver returns the messages that must be enqueued for the subscription, following the target topic when the subscription targets a topic.
+func (f *fanout) deliver(ctx context.Context, publish *fanoutPublish, subscription *domain.Subscription, message *domain.Message, hops uint) ([]*domain.Message, error) {
+	newMessage, err := subscription.Transform(message, publish.publishedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if subscription.TargetTopicID != nil {
+		if hops >= f.maxHops {
+			return nil, domain.ErrTopicMaxHopsExceeded
+		}
+
+		targetTopic, err := f.topicRepository.Get(ctx, *subscription.TargetTopicID)
+		if err != nil {
+			return nil, err
+		}
+
+		_, messages, err := f.route(ctx, publish, targetTopic, newMessage, hops+1)
+		return messages, err
+	}
+
+	if publish.deliveredQueues[*subscription.QueueID] {
+		return []*domain.Message{}, nil
+	}
+	publish.deliveredQueues[*subscription.QueueID] = true
+
+	queue, err := f.queueRepository.Get(ctx, *subscription.QueueID)
+	if err != nil {
+		return nil, err
+	}
+
+	newMessage.Enqueue(queue, publish.now)
+	newMessage.SetSource(publish.id, publish.topicID, subscription)
+
+	return []*domain.Message{newMessage}, nil
+}
+
+// reaches returns true when the messages published to the fromTopicID are routed to the toTopicID.
+func (f *fanout) reaches(ctx context.Context, fromTopicID, toTopicID string) (bool, error) {
+	visited := map[string]bool{fromTopicID: true}
+	pending := []string{fromTopicID}
+
+	for len(pending) > 0 {
+		topicID := pending[0]
+		pending = pending[1:]
+
+		if topicID == toTopicID {
+			return true, nil
+		}
+
+		subscriptions, err := f.listSubscriptions(ctx, topicID)
+		if err != nil {
+			return false, err
+		}
+
+		for i := range subscriptions {
+			targetTopicID := subscriptions[i].TargetTopicID
+			if targetTopicID == nil || visited[*targetTopicID] {
+				continue
+			}
+
+			visited[*targetTopicID] = true
+			pending = append(pending, *targetTopicID)
+		}
+	}
+
+	return false, nil
+}
+
+func newFanout(topicRepository domain.TopicRepository, subscriptionRepository domain.SubscriptionRepository, queueRepository domain.QueueRepository, maxHops uint) *fanout {
+	return &fanout{
+		topicRepository:        topicRepository,
+		subscriptionRepository: subscriptionRepository,
+		queueRepository:        queueRepository,
+		maxHops:                maxHops,
+	}
+}
@@ -2,15 +2,29 @@ package service
 
 import (
 	"context"
+	"errors"
+	"log/slog"
+	"slices"
 	"time"
 
+	"github.com/jellydator/validation"
+
 	"github.com/allisson/psqlqueue/domain"
 )
 
+// The interval between the reply queue checks of a request starts at replyPollInterval and doubles up to replyMaxPollInterval.
+var (
+	replyPollInterval    = 100 * time.Millisecond
+	replyMaxPollInterval = time.Second
+)
+
 // Message is an implementation of domain.MessageService
 type Message struct {
-	messageRepository domain.MessageRepository
-	queueRepository   domain.QueueRepository
+	messageRepository      domain.MessageRepository
+	queueRepository        domain.QueueRepository
+	messageEventRepository domain.MessageEventRepository
+	// requests limits the number of requests waiting for a reply, each one polls the database.
+	requests chan struct{}
 }
 
 func (m *Message) Create(ctx context.Context, message *domain.Message) error {
@@ -25,30 +39,319 @@ func (m *Message) Create(ctx context.Context, message *domain.Message) error {
 
 	message.Enqueue(queue, time.Now().UTC())
 
-	return m.messageRepository.Create(ctx, message)
+	if queue.Coalesce && message.CoalesceKey != nil {
+		err = m.messageRepository.CreateOrCoalesce(ctx, message)
+	} else {
+		err = m.messageRepository.Create(ctx, message)
+	}
+	if errors.Is(err, domain.ErrMessageDuplicated) && queue.UniqueKeyPolicy == domain.QueueUniqueKeyPolicyIgnore {
+		return nil
+	}
+
+	return err
 }
 
-func (m *Message) List(ctx context.Context, queueID string, label *string, limit uint) ([]*domain.Message, error) {
+func (m *Message) List(ctx context.Context, queueID string, label *string, limit, visibilityTimeoutSeconds uint, ackMode string, consumerID *string) ([]*domain.Message, time.Duration, error) {
+	if err := validateAckMode(ackMode); err != nil {
+		return nil, 0, err
+	}
+
 	queue, err := m.queueRepository.Get(ctx, queueID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return m.lease(ctx, queue, label, limit, visibilityTimeoutSeconds, ackMode, consumerID)
+}
+
+func (m *Message) ListFromQueues(ctx context.Context, queueIDs []string, label *string, limit, visibilityTimeoutSeconds uint, ackMode string, consumerID *string) ([]*domain.Message, time.Duration, error) {
+	if err := validation.Validate(queueIDs, validation.Required); err != nil {
+		return nil, 0, validation.Errors{"queue_id": err}
+	}
+	if err := validateAckMode(ackMode); err != nil {
+		return nil, 0, err
+	}
+
+	queues := make([]*domain.Queue, 0, len(queueIDs))
+	for _, queueID := range queueIDs {
+		if slices.ContainsFunc(queues, func(queue *domain.Queue) bool { return queue.ID == queueID }) {
+			continue
+		}
+		queue, err := m.queueRepository.Get(ctx, queueID)
+		if err != nil {
+			return nil, 0, err
+		}
+		queues = append(queues, queue)
+	}
+
+	// the queues are polled in the given order until the limit is reached.
+	messages := make([]*domain.Message, 0, limit)
+	var retryAfter time.Duration
+	for i, queue := range queues {
+		if uint(len(messages)) >= limit {
+			break
+		}
+
+		queueMessages, queueRetryAfter, err := m.lease(ctx, queue, label, limit-uint(len(messages)), visibilityTimeoutSeconds, ackMode, consumerID)
+		if err != nil {
+			if len(messages) == 0 {
+				return nil, 0, err
+			}
+			// the messages of the previous queues are already leased, they are returned instead of waiting for the visibility timeout.
+			slog.Error("messageService", "method", "ListFromQueues", "queue_id", queue.ID, "error", err.Error())
+			return messages, 0, nil
+		}
+		messages = append(messages, queueMessages...)
+
+		// only ask the client to wait when all the polled queues are rate limited.
+		if i == 0 || queueRetryAfter < retryAfter {
+			retryAfter = queueRetryAfter
+		}
+	}
+
+	return messages, retryAfter, nil
+}
+
+// lease delivers the ready messages of the queue following the queue delivery rate limit.
+func (m *Message) lease(ctx context.Context, queue *domain.Queue, label *string, limit, visibilityTimeoutSeconds uint, ackMode string, consumerID *string) ([]*domain.Message, time.Duration, error) {
+	if queue.MaxDeliveriesPerSecond == 0 {
+		messages, err := m.messageRepository.List(ctx, queue, label, limit, visibilityTimeoutSeconds, ackMode, consumerID)
+		return messages, 0, err
+	}
+
+	taken, retryAfter, err := m.queueRepository.TakeDeliveries(ctx, queue, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	if taken == 0 {
+		return []*domain.Message{}, retryAfter, nil
+	}
+
+	messages, err := m.messageRepository.List(ctx, queue, label, taken, visibilityTimeoutSeconds, ackMode, consumerID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// give back the tokens of the messages that were not delivered, the leased messages are returned even when it fails.
+	if unused := taken - uint(len(messages)); unused > 0 {
+		if err := m.queueRepository.ReturnDeliveries(ctx, queue, unused); err != nil {
+			slog.Error("queueRepository", "method", "ReturnDeliveries", "error", err.Error())
+		}
+		retryAfter = 0
+	}
+
+	return messages, retryAfter, nil
+}
+
+func validateAckMode(ackMode string) error {
+	if err := validation.Validate(ackMode, validation.In(domain.MessageAckModeManual, domain.MessageAckModeAuto)); err != nil {
+		return validation.Errors{"ack_mode": err}
+	}
+	return nil
+}
+
+func (m *Message) Request(ctx context.Context, message *domain.Message, timeout time.Duration) (*domain.Message, error) {
+	if err := message.Validate(); err != nil {
+		return nil, err
+	}
+
+	select {
+	case m.requests <- struct{}{}:
+		defer func() { <-m.requests }()
+	default:
+		return nil, domain.ErrMessageTooManyRequests
+	}
+
+	queue, err := m.queueRepository.Get(ctx, message.QueueID)
 	if err != nil {
 		return nil, err
 	}
 
-	return m.messageRepository.List(ctx, queue, label, limit)
+	now := time.Now().UTC()
+
+	if message.ReplyTo == nil {
+		m.deleteExpiredReplyQueues(ctx)
+		replyQueue := domain.NewReplyQueue(timeout, now)
+		if err := m.queueRepository.Create(ctx, replyQueue); err != nil {
+			return nil, err
+		}
+		defer m.deleteReplyQueue(context.WithoutCancel(ctx), replyQueue.ID)
+		message.SetReply(replyQueue.ID)
+	} else {
+		if _, err := m.queueRepository.Get(ctx, *message.ReplyTo); err != nil {
+			return nil, err
+		}
+		message.SetReply(*message.ReplyTo)
+	}
+
+	message.Enqueue(queue, now)
+	if err := m.messageRepository.Create(ctx, message); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	pollInterval := replyPollInterval
+	timer := time.NewTimer(pollInterval)
+	defer timer.Stop()
+
+	for {
+		reply, err := m.messageRepository.ReceiveReply(ctx, *message.ReplyTo, *message.CorrelationID)
+		if err == nil {
+			return reply, nil
+		}
+		if !errors.Is(err, domain.ErrMessageNotFound) && ctx.Err() == nil {
+			return nil, err
+		}
+
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return nil, domain.ErrMessageReplyTimeout
+			}
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+
+		pollInterval = min(2*pollInterval, replyMaxPollInterval)
+		timer.Reset(pollInterval)
+	}
 }
 
-func (m *Message) Ack(ctx context.Context, id string) error {
-	return m.messageRepository.Ack(ctx, id)
+// deleteReplyQueue removes a temporary reply queue, a failure is logged without failing the request.
+func (m *Message) deleteReplyQueue(ctx context.Context, id string) {
+	if err := m.queueRepository.Delete(ctx, id); err != nil {
+		slog.Error("queueRepository", "method", "Delete", "error", err.Error())
+	}
+}
+
+// deleteExpiredReplyQueues removes the temporary reply queues left behind by the requests that could not delete them, a
+// failure is logged without failing the request.
+func (m *Message) deleteExpiredReplyQueues(ctx context.Context) {
+	if err := m.queueRepository.DeleteExpired(ctx); err != nil {
+		slog.Error("queueRepository", "method", "DeleteExpired", "error", err.Error())
+	}
 }
 
-func (m *Message) Nack(ctx context.Context, id string, visibilityTimeoutSeconds uint) error {
-	return m.messageRepository.Nack(ctx, id, visibilityTimeoutSeconds)
+func (m *Message) GetJob(ctx context.Context, queueID, id string) (*domain.MessageJob, error) {
+	message, err := m.get(ctx, queueID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	if message.ResultExpiredAt != nil && !message.ResultExpiredAt.After(now) {
+		return nil, domain.ErrMessageNotFound
+	}
+
+	return message.Job(now), nil
+}
+
+func (m *Message) Progress(ctx context.Context, queueID, id string, progress *domain.MessageProgress) error {
+	if err := progress.Validate(); err != nil {
+		return err
+	}
+
+	message, err := m.get(ctx, queueID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := message.SetProgress(progress, time.Now().UTC()); err != nil {
+		return err
+	}
+
+	return m.messageRepository.UpdateProgress(ctx, message)
+}
+
+// get returns the message of the queue, a message of another queue is not found.
+func (m *Message) get(ctx context.Context, queueID, id string) (*domain.Message, error) {
+	message, err := m.messageRepository.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if message.QueueID != queueID {
+		return nil, domain.ErrMessageNotFound
+	}
+
+	return message, nil
+}
+
+func (m *Message) Ack(ctx context.Context, id string, result *domain.MessageResult) error {
+	if result == nil {
+		return m.messageRepository.Ack(ctx, id)
+	}
+
+	if err := result.Validate(); err != nil {
+		return err
+	}
+
+	message, err := m.messageRepository.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	queue, err := m.queueRepository.Get(ctx, message.QueueID)
+	if err != nil {
+		return err
+	}
+
+	message.AckWithResult(result, queue, time.Now().UTC())
+
+	return m.messageRepository.UpdateWithEvent(ctx, message, domain.MessageEventTypeAck)
+}
+
+func (m *Message) Nack(ctx context.Context, id string, visibilityTimeoutSeconds uint, messageError *domain.MessageError) error {
+	if messageError != nil {
+		if err := messageError.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return m.messageRepository.Nack(ctx, id, visibilityTimeoutSeconds, messageError)
+}
+
+func (m *Message) ListEvents(ctx context.Context, queueID, id string, offset, limit uint) ([]*domain.MessageEvent, error) {
+	return m.messageEventRepository.ListByMessage(ctx, queueID, id, offset, limit)
+}
+
+func (m *Message) ListInFlight(ctx context.Context, queueID string, offset, limit uint) ([]*domain.MessageLease, error) {
+	queue, err := m.queueRepository.Get(ctx, queueID)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := m.messageRepository.ListInFlight(ctx, queue.ID, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	leases := make([]*domain.MessageLease, 0, len(messages))
+	for _, message := range messages {
+		leases = append(leases, message.Lease())
+	}
+
+	return leases, nil
+}
+
+func (m *Message) ReleaseConsumer(ctx context.Context, queueID, consumerID string) error {
+	queue, err := m.queueRepository.Get(ctx, queueID)
+	if err != nil {
+		return err
+	}
+
+	_, err = m.messageRepository.ReleaseConsumer(ctx, queue.ID, consumerID)
+	return err
 }
 
 // NewMessage returns an implementation of domain.MessageService.
-func NewMessage(messageRepository domain.MessageRepository, queueRepository domain.QueueRepository) *Message {
+func NewMessage(messageRepository domain.MessageRepository, queueRepository domain.QueueRepository, messageEventRepository domain.MessageEventRepository, maxConcurrentRequests uint) *Message {
 	return &Message{
-		messageRepository: messageRepository,
-		queueRepository:   queueRepository,
+		messageRepository:      messageRepository,
+		queueRepository:        queueRepository,
+		messageEventRepository: messageEventRepository,
+		requests:               make(chan struct{}, maxConcurrentRequests),
 	}
 }
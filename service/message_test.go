@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -17,13 +18,18 @@ func nilString() *string {
 	return s
 }
 
+func pointString(x string) *string {
+	return &x
+}
+
 func TestMessage(t *testing.T) {
 	ctx := context.Background()
 
 	t.Run("Create", func(t *testing.T) {
 		messageRepository := mocks.NewMessageRepository(t)
 		queueRepository := mocks.NewQueueRepository(t)
-		messageService := NewMessage(messageRepository, queueRepository)
+		messageEventRepository := mocks.NewMessageEventRepository(t)
+		messageService := NewMessage(messageRepository, queueRepository, messageEventRepository, 10)
 		queue := makeQueue("my-queue")
 		message := domain.Message{Body: `{"data": true}`, QueueID: queue.ID}
 
@@ -34,10 +40,78 @@ func TestMessage(t *testing.T) {
 		assert.Nil(t, err)
 	})
 
+	t.Run("Create with duplicated unique key", func(t *testing.T) {
+		messageRepository := mocks.NewMessageRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		messageEventRepository := mocks.NewMessageEventRepository(t)
+		messageService := NewMessage(messageRepository, queueRepository, messageEventRepository, 10)
+		queue := makeQueue("my-queue")
+		queue.UniqueKeyPolicy = domain.QueueUniqueKeyPolicyReject
+		uniqueKey := "reindex-customer-42"
+		message := domain.Message{Body: `{"data": true}`, QueueID: queue.ID, UniqueKey: &uniqueKey}
+
+		queueRepository.On("Get", ctx, queue.ID).Return(queue, nil)
+		messageRepository.On("Create", ctx, mock.Anything).Return(domain.ErrMessageDuplicated)
+
+		err := messageService.Create(ctx, &message)
+		assert.ErrorIs(t, err, domain.ErrMessageDuplicated)
+	})
+
+	t.Run("Create with duplicated unique key and ignore policy", func(t *testing.T) {
+		messageRepository := mocks.NewMessageRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		messageEventRepository := mocks.NewMessageEventRepository(t)
+		messageService := NewMessage(messageRepository, queueRepository, messageEventRepository, 10)
+		queue := makeQueue("my-queue")
+		queue.UniqueKeyPolicy = domain.QueueUniqueKeyPolicyIgnore
+		uniqueKey := "reindex-customer-42"
+		message := domain.Message{Body: `{"data": true}`, QueueID: queue.ID, UniqueKey: &uniqueKey}
+
+		queueRepository.On("Get", ctx, queue.ID).Return(queue, nil)
+		messageRepository.On("Create", ctx, mock.Anything).Return(domain.ErrMessageDuplicated)
+
+		err := messageService.Create(ctx, &message)
+		assert.Nil(t, err)
+	})
+
+	t.Run("Create with coalesce key", func(t *testing.T) {
+		messageRepository := mocks.NewMessageRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		messageEventRepository := mocks.NewMessageEventRepository(t)
+		messageService := NewMessage(messageRepository, queueRepository, messageEventRepository, 10)
+		queue := makeQueue("my-queue")
+		queue.Coalesce = true
+		coalesceKey := "customer-42"
+		message := domain.Message{Body: `{"data": true}`, QueueID: queue.ID, CoalesceKey: &coalesceKey}
+
+		queueRepository.On("Get", ctx, queue.ID).Return(queue, nil)
+		messageRepository.On("CreateOrCoalesce", ctx, mock.Anything).Return(nil)
+
+		err := messageService.Create(ctx, &message)
+		assert.Nil(t, err)
+	})
+
+	t.Run("Create with coalesce key and coalesce disabled", func(t *testing.T) {
+		messageRepository := mocks.NewMessageRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		messageEventRepository := mocks.NewMessageEventRepository(t)
+		messageService := NewMessage(messageRepository, queueRepository, messageEventRepository, 10)
+		queue := makeQueue("my-queue")
+		coalesceKey := "customer-42"
+		message := domain.Message{Body: `{"data": true}`, QueueID: queue.ID, CoalesceKey: &coalesceKey}
+
+		queueRepository.On("Get", ctx, queue.ID).Return(queue, nil)
+		messageRepository.On("Create", ctx, mock.Anything).Return(nil)
+
+		err := messageService.Create(ctx, &message)
+		assert.Nil(t, err)
+	})
+
 	t.Run("List", func(t *testing.T) {
 		messageRepository := mocks.NewMessageRepository(t)
 		queueRepository := mocks.NewQueueRepository(t)
-		messageService := NewMessage(messageRepository, queueRepository)
+		messageEventRepository := mocks.NewMessageEventRepository(t)
+		messageService := NewMessage(messageRepository, queueRepository, messageEventRepository, 10)
 		queue := makeQueue("my-queue")
 		message1 := domain.Message{Body: `{"data": true}`}
 		message1.Enqueue(queue, time.Now().UTC())
@@ -45,38 +119,493 @@ func TestMessage(t *testing.T) {
 		message2.Enqueue(queue, time.Now().UTC())
 
 		queueRepository.On("Get", ctx, queue.ID).Return(queue, nil)
-		messageRepository.On("List", ctx, queue, nilString(), uint(10)).Return([]*domain.Message{&message1, &message2}, nil)
+		messageRepository.On("List", ctx, queue, nilString(), uint(10), uint(0), domain.MessageAckModeManual, nilString()).Return([]*domain.Message{&message1, &message2}, nil)
 
-		messages, err := messageService.List(ctx, queue.ID, nilString(), 10)
+		messages, retryAfter, err := messageService.List(ctx, queue.ID, nilString(), 10, 0, domain.MessageAckModeManual, nilString())
 		assert.Nil(t, err)
 		assert.Len(t, messages, 2)
+		assert.Equal(t, time.Duration(0), retryAfter)
+	})
+
+	t.Run("ListFromQueues", func(t *testing.T) {
+		messageRepository := mocks.NewMessageRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		messageEventRepository := mocks.NewMessageEventRepository(t)
+		messageService := NewMessage(messageRepository, queueRepository, messageEventRepository, 10)
+		queue1 := makeQueue("queue-a")
+		queue2 := makeQueue("queue-b")
+		queue3 := makeQueue("queue-c")
+		message1 := domain.Message{Body: `{"data": true}`}
+		message1.Enqueue(queue1, time.Now().UTC())
+		message2 := domain.Message{Body: `{"data": true}`}
+		message2.Enqueue(queue2, time.Now().UTC())
+		message3 := domain.Message{Body: `{"data": true}`}
+		message3.Enqueue(queue2, time.Now().UTC())
+
+		queueRepository.On("Get", ctx, queue1.ID).Return(queue1, nil)
+		queueRepository.On("Get", ctx, queue2.ID).Return(queue2, nil)
+		queueRepository.On("Get", ctx, queue3.ID).Return(queue3, nil)
+		messageRepository.On("List", ctx, queue1, nilString(), uint(3), uint(0), domain.MessageAckModeManual, nilString()).Return([]*domain.Message{&message1}, nil)
+		messageRepository.On("List", ctx, queue2, nilString(), uint(2), uint(0), domain.MessageAckModeManual, nilString()).Return([]*domain.Message{&message2, &message3}, nil)
+
+		messages, retryAfter, err := messageService.ListFromQueues(ctx, []string{queue1.ID, queue2.ID, queue1.ID, queue3.ID}, nilString(), 3, 0, domain.MessageAckModeManual, nilString())
+		assert.Nil(t, err)
+		assert.Equal(t, []*domain.Message{&message1, &message2, &message3}, messages)
+		assert.Equal(t, time.Duration(0), retryAfter)
+	})
+
+	t.Run("ListFromQueues with error after leased messages", func(t *testing.T) {
+		messageRepository := mocks.NewMessageRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		messageEventRepository := mocks.NewMessageEventRepository(t)
+		messageService := NewMessage(messageRepository, queueRepository, messageEventRepository, 10)
+		queue1 := makeQueue("queue-a")
+		queue2 := makeQueue("queue-b")
+		message1 := domain.Message{Body: `{"data": true}`}
+		message1.Enqueue(queue1, time.Now().UTC())
+
+		queueRepository.On("Get", ctx, queue1.ID).Return(queue1, nil)
+		queueRepository.On("Get", ctx, queue2.ID).Return(queue2, nil)
+		messageRepository.On("List", ctx, queue1, nilString(), uint(3), uint(0), domain.MessageAckModeManual, nilString()).Return([]*domain.Message{&message1}, nil)
+		messageRepository.On("List", ctx, queue2, nilString(), uint(2), uint(0), domain.MessageAckModeManual, nilString()).Return(nil, errors.New("connection reset"))
+
+		messages, _, err := messageService.ListFromQueues(ctx, []string{queue1.ID, queue2.ID}, nilString(), 3, 0, domain.MessageAckModeManual, nilString())
+		assert.Nil(t, err)
+		assert.Equal(t, []*domain.Message{&message1}, messages)
+	})
+
+	t.Run("ListFromQueues with rate limit exhausted", func(t *testing.T) {
+		messageRepository := mocks.NewMessageRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		messageEventRepository := mocks.NewMessageEventRepository(t)
+		messageService := NewMessage(messageRepository, queueRepository, messageEventRepository, 10)
+		queue1 := makeQueue("queue-a")
+		queue1.MaxDeliveriesPerSecond = 5
+		queue1.DeliveryBurst = 5
+		queue2 := makeQueue("queue-b")
+		queue2.MaxDeliveriesPerSecond = 5
+		queue2.DeliveryBurst = 5
+
+		queueRepository.On("Get", ctx, queue1.ID).Return(queue1, nil)
+		queueRepository.On("Get", ctx, queue2.ID).Return(queue2, nil)
+		queueRepository.On("TakeDeliveries", ctx, queue1, uint(10)).Return(uint(0), 200*time.Millisecond, nil)
+		queueRepository.On("TakeDeliveries", ctx, queue2, uint(10)).Return(uint(0), 100*time.Millisecond, nil)
+
+		messages, retryAfter, err := messageService.ListFromQueues(ctx, []string{queue1.ID, queue2.ID}, nilString(), 10, 0, domain.MessageAckModeManual, nilString())
+		assert.Nil(t, err)
+		assert.Len(t, messages, 0)
+		assert.Equal(t, 100*time.Millisecond, retryAfter)
+	})
+
+	t.Run("ListFromQueues without queues", func(t *testing.T) {
+		messageRepository := mocks.NewMessageRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		messageEventRepository := mocks.NewMessageEventRepository(t)
+		messageService := NewMessage(messageRepository, queueRepository, messageEventRepository, 10)
+
+		_, _, err := messageService.ListFromQueues(ctx, nil, nilString(), 10, 0, domain.MessageAckModeManual, nilString())
+		assert.Equal(t, "queue_id: cannot be blank.", err.Error())
+	})
+
+	t.Run("List with visibility timeout", func(t *testing.T) {
+		messageRepository := mocks.NewMessageRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		messageEventRepository := mocks.NewMessageEventRepository(t)
+		messageService := NewMessage(messageRepository, queueRepository, messageEventRepository, 10)
+		queue := makeQueue("my-queue")
+		message1 := domain.Message{Body: `{"data": true}`}
+		message1.Enqueue(queue, time.Now().UTC())
+
+		queueRepository.On("Get", ctx, queue.ID).Return(queue, nil)
+		messageRepository.On("List", ctx, queue, nilString(), uint(10), uint(300), domain.MessageAckModeManual, nilString()).Return([]*domain.Message{&message1}, nil)
+
+		messages, _, err := messageService.List(ctx, queue.ID, nilString(), 10, 300, domain.MessageAckModeManual, nilString())
+		assert.Nil(t, err)
+		assert.Len(t, messages, 1)
+	})
+
+	t.Run("List with auto ack", func(t *testing.T) {
+		messageRepository := mocks.NewMessageRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		messageEventRepository := mocks.NewMessageEventRepository(t)
+		messageService := NewMessage(messageRepository, queueRepository, messageEventRepository, 10)
+		queue := makeQueue("my-queue")
+		message1 := domain.Message{Body: `{"data": true}`}
+		message1.Enqueue(queue, time.Now().UTC())
+
+		queueRepository.On("Get", ctx, queue.ID).Return(queue, nil)
+		messageRepository.On("List", ctx, queue, nilString(), uint(10), uint(0), domain.MessageAckModeAuto, nilString()).Return([]*domain.Message{&message1}, nil)
+
+		messages, _, err := messageService.List(ctx, queue.ID, nilString(), 10, 0, domain.MessageAckModeAuto, nilString())
+		assert.Nil(t, err)
+		assert.Len(t, messages, 1)
+	})
+
+	t.Run("List with invalid ack mode", func(t *testing.T) {
+		messageRepository := mocks.NewMessageRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		messageEventRepository := mocks.NewMessageEventRepository(t)
+		messageService := NewMessage(messageRepository, queueRepository, messageEventRepository, 10)
+
+		_, _, err := messageService.List(ctx, "my-queue", nilString(), 10, 0, "never", nilString())
+		assert.Equal(t, "ack_mode: must be a valid value.", err.Error())
+	})
+
+	t.Run("List with rate limit", func(t *testing.T) {
+		messageRepository := mocks.NewMessageRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		messageEventRepository := mocks.NewMessageEventRepository(t)
+		messageService := NewMessage(messageRepository, queueRepository, messageEventRepository, 10)
+		queue := makeQueue("my-queue")
+		queue.MaxDeliveriesPerSecond = 5
+		queue.DeliveryBurst = 5
+		message1 := domain.Message{Body: `{"data": true}`}
+		message1.Enqueue(queue, time.Now().UTC())
+
+		queueRepository.On("Get", ctx, queue.ID).Return(queue, nil)
+		queueRepository.On("TakeDeliveries", ctx, queue, uint(10)).Return(uint(3), time.Duration(0), nil)
+		messageRepository.On("List", ctx, queue, nilString(), uint(3), uint(0), domain.MessageAckModeManual, nilString()).Return([]*domain.Message{&message1}, nil)
+		queueRepository.On("ReturnDeliveries", ctx, queue, uint(2)).Return(nil)
+
+		messages, retryAfter, err := messageService.List(ctx, queue.ID, nilString(), 10, 0, domain.MessageAckModeManual, nilString())
+		assert.Nil(t, err)
+		assert.Len(t, messages, 1)
+		assert.Equal(t, time.Duration(0), retryAfter)
+	})
+
+	t.Run("List with rate limit and return deliveries error", func(t *testing.T) {
+		messageRepository := mocks.NewMessageRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		messageEventRepository := mocks.NewMessageEventRepository(t)
+		messageService := NewMessage(messageRepository, queueRepository, messageEventRepository, 10)
+		queue := makeQueue("my-queue")
+		queue.MaxDeliveriesPerSecond = 5
+		queue.DeliveryBurst = 5
+		message1 := domain.Message{Body: `{"data": true}`}
+		message1.Enqueue(queue, time.Now().UTC())
+
+		queueRepository.On("Get", ctx, queue.ID).Return(queue, nil)
+		queueRepository.On("TakeDeliveries", ctx, queue, uint(10)).Return(uint(3), time.Duration(0), nil)
+		messageRepository.On("List", ctx, queue, nilString(), uint(3), uint(0), domain.MessageAckModeManual, nilString()).Return([]*domain.Message{&message1}, nil)
+		queueRepository.On("ReturnDeliveries", ctx, queue, uint(2)).Return(errors.New("connection reset"))
+
+		messages, _, err := messageService.List(ctx, queue.ID, nilString(), 10, 0, domain.MessageAckModeManual, nilString())
+		assert.Nil(t, err)
+		assert.Len(t, messages, 1)
+	})
+
+	t.Run("List with rate limit exhausted", func(t *testing.T) {
+		messageRepository := mocks.NewMessageRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		messageEventRepository := mocks.NewMessageEventRepository(t)
+		messageService := NewMessage(messageRepository, queueRepository, messageEventRepository, 10)
+		queue := makeQueue("my-queue")
+		queue.MaxDeliveriesPerSecond = 5
+		queue.DeliveryBurst = 5
+
+		queueRepository.On("Get", ctx, queue.ID).Return(queue, nil)
+		queueRepository.On("TakeDeliveries", ctx, queue, uint(10)).Return(uint(0), 200*time.Millisecond, nil)
+
+		messages, retryAfter, err := messageService.List(ctx, queue.ID, nilString(), 10, 0, domain.MessageAckModeManual, nilString())
+		assert.Nil(t, err)
+		assert.Len(t, messages, 0)
+		assert.Equal(t, 200*time.Millisecond, retryAfter)
+	})
+
+	t.Run("Request", func(t *testing.T) {
+		messageRepository := mocks.NewMessageRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		messageEventRepository := mocks.NewMessageEventRepository(t)
+		messageService := NewMessage(messageRepository, queueRepository, messageEventRepository, 10)
+		queue := makeQueue("my-queue")
+		message := domain.Message{Body: `{"data": true}`, QueueID: queue.ID}
+		reply := domain.Message{Body: `{"reply": true}`}
+		var replyQueueID string
+
+		queueRepository.On("Get", ctx, queue.ID).Return(queue, nil)
+		queueRepository.On("DeleteExpired", ctx).Return(nil)
+		queueRepository.On("Create", ctx, mock.Anything).Return(func(ctx context.Context, replyQueue *domain.Queue) error {
+			replyQueueID = replyQueue.ID
+			return nil
+		})
+		queueRepository.On("Delete", mock.Anything, mock.Anything).Return(nil)
+		messageRepository.On("Create", ctx, mock.Anything).Return(nil)
+		messageRepository.On("ReceiveReply", mock.Anything, mock.Anything, mock.Anything).Return(nil, domain.ErrMessageNotFound).Once()
+		messageRepository.On("ReceiveReply", mock.Anything, mock.Anything, mock.Anything).Return(&reply, nil).Once()
+
+		replyFromService, err := messageService.Request(ctx, &message, time.Second)
+		assert.Nil(t, err)
+		assert.Equal(t, &reply, replyFromService)
+		assert.Equal(t, replyQueueID, *message.ReplyTo)
+		assert.NotNil(t, message.CorrelationID)
+		messageRepository.AssertCalled(t, "ReceiveReply", mock.Anything, replyQueueID, *message.CorrelationID)
+		queueRepository.AssertCalled(t, "Delete", mock.Anything, replyQueueID)
+	})
+
+	t.Run("Request with timeout", func(t *testing.T) {
+		messageRepository := mocks.NewMessageRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		messageEventRepository := mocks.NewMessageEventRepository(t)
+		messageService := NewMessage(messageRepository, queueRepository, messageEventRepository, 10)
+		queue := makeQueue("my-queue")
+		replyQueue := makeQueue("my-reply-queue")
+		message := domain.Message{Body: `{"data": true}`, QueueID: queue.ID, ReplyTo: &replyQueue.ID}
+
+		queueRepository.On("Get", ctx, queue.ID).Return(queue, nil)
+		queueRepository.On("Get", ctx, replyQueue.ID).Return(replyQueue, nil)
+		messageRepository.On("Create", ctx, mock.Anything).Return(nil)
+		messageRepository.On("ReceiveReply", mock.Anything, replyQueue.ID, mock.Anything).Return(nil, domain.ErrMessageNotFound)
+
+		_, err := messageService.Request(ctx, &message, 200*time.Millisecond)
+		assert.ErrorIs(t, err, domain.ErrMessageReplyTimeout)
+	})
+
+	t.Run("Request with too many requests", func(t *testing.T) {
+		messageRepository := mocks.NewMessageRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		messageEventRepository := mocks.NewMessageEventRepository(t)
+		messageService := NewMessage(messageRepository, queueRepository, messageEventRepository, 0)
+		message := domain.Message{Body: `{"data": true}`, QueueID: "my-queue"}
+
+		_, err := messageService.Request(ctx, &message, time.Second)
+		assert.ErrorIs(t, err, domain.ErrMessageTooManyRequests)
 	})
 
 	t.Run("Ack", func(t *testing.T) {
 		messageRepository := mocks.NewMessageRepository(t)
 		queueRepository := mocks.NewQueueRepository(t)
-		messageService := NewMessage(messageRepository, queueRepository)
+		messageEventRepository := mocks.NewMessageEventRepository(t)
+		messageService := NewMessage(messageRepository, queueRepository, messageEventRepository, 10)
 		queue := makeQueue("my-queue")
 		message := domain.Message{Body: `{"data": true}`}
 		message.Enqueue(queue, time.Now().UTC())
 
 		messageRepository.On("Ack", ctx, message.ID).Return(nil)
 
-		err := messageService.Ack(ctx, message.ID)
+		err := messageService.Ack(ctx, message.ID, nil)
+		assert.Nil(t, err)
+	})
+
+	t.Run("Ack with result", func(t *testing.T) {
+		messageRepository := mocks.NewMessageRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		messageEventRepository := mocks.NewMessageEventRepository(t)
+		messageService := NewMessage(messageRepository, queueRepository, messageEventRepository, 10)
+		queue := makeQueue("my-queue")
+		queue.ResultRetentionSeconds = 60
+		message := domain.Message{Body: `{"data": true}`}
+		message.Enqueue(queue, time.Now().UTC())
+		body := `{"rows": 10}`
+		result := domain.MessageResult{Status: domain.MessageResultStatusSucceeded, Body: &body}
+
+		messageRepository.On("Get", ctx, message.ID).Return(&message, nil)
+		queueRepository.On("Get", ctx, queue.ID).Return(queue, nil)
+		messageRepository.On("UpdateWithEvent", ctx, &message, domain.MessageEventTypeAck).Return(nil)
+
+		err := messageService.Ack(ctx, message.ID, &result)
+		assert.Nil(t, err)
+		assert.Equal(t, domain.MessageResultStatusSucceeded, *message.ResultStatus)
+		assert.Equal(t, body, *message.Result)
+		assert.NotNil(t, message.ResultExpiredAt)
+	})
+
+	t.Run("Ack with invalid result", func(t *testing.T) {
+		messageRepository := mocks.NewMessageRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		messageEventRepository := mocks.NewMessageEventRepository(t)
+		messageService := NewMessage(messageRepository, queueRepository, messageEventRepository, 10)
+
+		err := messageService.Ack(ctx, "message-id", &domain.MessageResult{Status: "done"})
+		assert.ErrorContains(t, err, "status: must be a valid value.")
+	})
+
+	t.Run("Progress", func(t *testing.T) {
+		messageRepository := mocks.NewMessageRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		messageEventRepository := mocks.NewMessageEventRepository(t)
+		messageService := NewMessage(messageRepository, queueRepository, messageEventRepository, 10)
+		queue := makeQueue("my-queue")
+		message := domain.Message{Body: `{"data": true}`}
+		message.Enqueue(queue, time.Now().UTC())
+		message.DeliverySetup(queue, 0, time.Now().UTC())
+		progress := domain.MessageProgress{Percent: 50}
+
+		messageRepository.On("Get", ctx, message.ID).Return(&message, nil)
+		messageRepository.On("UpdateProgress", ctx, &message).Return(nil)
+
+		err := messageService.Progress(ctx, queue.ID, message.ID, &progress)
+		assert.Nil(t, err)
+		assert.Equal(t, uint(50), *message.ProgressPercent)
+	})
+
+	t.Run("Progress with message of another queue", func(t *testing.T) {
+		messageRepository := mocks.NewMessageRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		messageEventRepository := mocks.NewMessageEventRepository(t)
+		messageService := NewMessage(messageRepository, queueRepository, messageEventRepository, 10)
+		queue := makeQueue("my-queue")
+		message := domain.Message{Body: `{"data": true}`}
+		message.Enqueue(queue, time.Now().UTC())
+		message.DeliverySetup(queue, 0, time.Now().UTC())
+
+		messageRepository.On("Get", ctx, message.ID).Return(&message, nil)
+
+		err := messageService.Progress(ctx, "other-queue", message.ID, &domain.MessageProgress{Percent: 50})
+		assert.ErrorIs(t, err, domain.ErrMessageNotFound)
+	})
+
+	t.Run("Progress with message not in flight", func(t *testing.T) {
+		messageRepository := mocks.NewMessageRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		messageEventRepository := mocks.NewMessageEventRepository(t)
+		messageService := NewMessage(messageRepository, queueRepository, messageEventRepository, 10)
+		queue := makeQueue("my-queue")
+		message := domain.Message{Body: `{"data": true}`}
+		message.Enqueue(queue, time.Now().UTC())
+
+		messageRepository.On("Get", ctx, message.ID).Return(&message, nil)
+
+		err := messageService.Progress(ctx, queue.ID, message.ID, &domain.MessageProgress{Percent: 50})
+		assert.ErrorIs(t, err, domain.ErrMessageNotInFlight)
+	})
+
+	t.Run("GetJob", func(t *testing.T) {
+		messageRepository := mocks.NewMessageRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		messageEventRepository := mocks.NewMessageEventRepository(t)
+		messageService := NewMessage(messageRepository, queueRepository, messageEventRepository, 10)
+		queue := makeQueue("my-queue")
+		message := domain.Message{Body: `{"data": true}`}
+		message.Enqueue(queue, time.Now().UTC())
+
+		messageRepository.On("Get", ctx, message.ID).Return(&message, nil)
+
+		job, err := messageService.GetJob(ctx, queue.ID, message.ID)
 		assert.Nil(t, err)
+		assert.Equal(t, message.ID, job.ID)
+		assert.Equal(t, domain.MessageStatusAvailable, job.Status)
+
+		_, err = messageService.GetJob(ctx, "other-queue", message.ID)
+		assert.ErrorIs(t, err, domain.ErrMessageNotFound)
+	})
+
+	t.Run("GetJob with expired result", func(t *testing.T) {
+		messageRepository := mocks.NewMessageRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		messageEventRepository := mocks.NewMessageEventRepository(t)
+		messageService := NewMessage(messageRepository, queueRepository, messageEventRepository, 10)
+		queue := makeQueue("my-queue")
+		message := domain.Message{Body: `{"data": true}`}
+		message.Enqueue(queue, time.Now().UTC())
+		resultExpiredAt := time.Now().UTC().Add(-time.Second)
+		message.ResultExpiredAt = &resultExpiredAt
+
+		messageRepository.On("Get", ctx, message.ID).Return(&message, nil)
+
+		_, err := messageService.GetJob(ctx, queue.ID, message.ID)
+		assert.ErrorIs(t, err, domain.ErrMessageNotFound)
 	})
 
 	t.Run("Nack", func(t *testing.T) {
 		messageRepository := mocks.NewMessageRepository(t)
 		queueRepository := mocks.NewQueueRepository(t)
-		messageService := NewMessage(messageRepository, queueRepository)
+		messageEventRepository := mocks.NewMessageEventRepository(t)
+		messageService := NewMessage(messageRepository, queueRepository, messageEventRepository, 10)
 		queue := makeQueue("my-queue")
 		message := domain.Message{Body: `{"data": true}`}
 		message.Enqueue(queue, time.Now().UTC())
 
-		messageRepository.On("Nack", ctx, message.ID, uint(0)).Return(nil)
+		messageRepository.On("Nack", ctx, message.ID, uint(0), (*domain.MessageError)(nil)).Return(nil)
+
+		err := messageService.Nack(ctx, message.ID, uint(0), nil)
+		assert.Nil(t, err)
+	})
+
+	t.Run("Nack with error", func(t *testing.T) {
+		messageRepository := mocks.NewMessageRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		messageEventRepository := mocks.NewMessageEventRepository(t)
+		messageService := NewMessage(messageRepository, queueRepository, messageEventRepository, 10)
+		queue := makeQueue("my-queue")
+		message := domain.Message{Body: `{"data": true}`}
+		message.Enqueue(queue, time.Now().UTC())
+		message.DeliverySetup(queue, 0, time.Now().UTC())
+		messageError := domain.MessageError{Message: "connection refused", Code: pointString("ECONNREFUSED")}
+
+		messageRepository.On("Nack", ctx, message.ID, uint(30), &messageError).Return(nil)
+
+		err := messageService.Nack(ctx, message.ID, uint(30), &messageError)
+		assert.Nil(t, err)
+	})
+
+	t.Run("Nack with message not in flight", func(t *testing.T) {
+		messageRepository := mocks.NewMessageRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		messageEventRepository := mocks.NewMessageEventRepository(t)
+		messageService := NewMessage(messageRepository, queueRepository, messageEventRepository, 10)
+
+		messageRepository.On("Nack", ctx, "message-id", uint(0), (*domain.MessageError)(nil)).Return(domain.ErrMessageNotInFlight)
+
+		err := messageService.Nack(ctx, "message-id", uint(0), nil)
+		assert.ErrorIs(t, err, domain.ErrMessageNotInFlight)
+	})
+
+	t.Run("Nack with invalid error", func(t *testing.T) {
+		messageRepository := mocks.NewMessageRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		messageEventRepository := mocks.NewMessageEventRepository(t)
+		messageService := NewMessage(messageRepository, queueRepository, messageEventRepository, 10)
+
+		err := messageService.Nack(ctx, "message-id", uint(30), &domain.MessageError{Code: pointString("ECONNREFUSED")})
+		assert.Equal(t, "error: cannot be blank.", err.Error())
+	})
+
+	t.Run("ListEvents", func(t *testing.T) {
+		messageRepository := mocks.NewMessageRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		messageEventRepository := mocks.NewMessageEventRepository(t)
+		messageService := NewMessage(messageRepository, queueRepository, messageEventRepository, 10)
+		events := []*domain.MessageEvent{{ID: 1, MessageID: "message-id", QueueID: "my-queue", Type: domain.MessageEventTypeEnqueue, CreatedAt: time.Now().UTC()}}
+
+		messageEventRepository.On("ListByMessage", ctx, "my-queue", "message-id", uint(0), uint(10)).Return(events, nil)
+
+		eventsFromService, err := messageService.ListEvents(ctx, "my-queue", "message-id", 0, 10)
+		assert.Nil(t, err)
+		assert.Equal(t, events, eventsFromService)
+	})
+
+	t.Run("ListInFlight", func(t *testing.T) {
+		messageRepository := mocks.NewMessageRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		messageEventRepository := mocks.NewMessageEventRepository(t)
+		messageService := NewMessage(messageRepository, queueRepository, messageEventRepository, 10)
+		queue := makeQueue("my-queue")
+		message1 := domain.Message{Body: `{"data": true}`}
+		message1.Enqueue(queue, time.Now().UTC())
+		message1.DeliverySetup(queue, 0, time.Now().UTC())
+		message1.SetConsumer(pointString("worker-1"))
+
+		queueRepository.On("Get", ctx, queue.ID).Return(queue, nil)
+		messageRepository.On("ListInFlight", ctx, queue.ID, uint(0), uint(10)).Return([]*domain.Message{&message1}, nil)
+
+		leases, err := messageService.ListInFlight(ctx, queue.ID, 0, 10)
+		assert.Nil(t, err)
+		assert.Equal(t, []*domain.MessageLease{message1.Lease()}, leases)
+	})
+
+	t.Run("ReleaseConsumer", func(t *testing.T) {
+		messageRepository := mocks.NewMessageRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		messageEventRepository := mocks.NewMessageEventRepository(t)
+		messageService := NewMessage(messageRepository, queueRepository, messageEventRepository, 10)
+		queue := makeQueue("my-queue")
+		message1 := domain.Message{Body: `{"data": true}`}
+		message1.Enqueue(queue, time.Now().UTC())
+
+		queueRepository.On("Get", ctx, queue.ID).Return(queue, nil)
+		messageRepository.On("ReleaseConsumer", ctx, queue.ID, "worker-1").Return([]*domain.Message{&message1}, nil)
 
-		err := messageService.Nack(ctx, message.ID, uint(0))
+		err := messageService.ReleaseConsumer(ctx, queue.ID, "worker-1")
 		assert.Nil(t, err)
 	})
 }
@@ -0,0 +1,21 @@
+package service
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	topicPublishedMessagesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "psqlqueue_topic_published_messages_total",
+		Help: "The total number of messages published to a topic.",
+	}, []string{"topic_id"})
+	subscriptionDeliveredMessagesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "psqlqueue_subscription_delivered_messages_total",
+		Help: "The total number of messages delivered by a subscription.",
+	}, []string{"topic_id", "subscription_id"})
+	subscriptionFilteredMessagesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "psqlqueue_subscription_filtered_messages_total",
+		Help: "The total number of messages filtered out by a subscription.",
+	}, []string{"topic_id", "subscription_id"})
+)
@@ -17,6 +17,7 @@ func (q *Queue) Create(ctx context.Context, queue *domain.Queue) error {
 		return err
 	}
 
+	queue.SetDefaults()
 	now := time.Now().UTC()
 	queue.CreatedAt = now
 	queue.UpdatedAt = now
@@ -34,6 +35,7 @@ func (q *Queue) Update(ctx context.Context, queue *domain.Queue) error {
 		return err
 	}
 
+	queue.SetDefaults()
 	queue.CreatedAt = queueFromDB.CreatedAt
 	queue.UpdatedAt = time.Now().UTC()
 
@@ -86,6 +88,19 @@ func (q *Queue) Cleanup(ctx context.Context, id string) error {
 	return q.queueRepository.Cleanup(ctx, queue.ID)
 }
 
+func (q *Queue) Seek(ctx context.Context, id string, seek *domain.QueueSeek) error {
+	if err := seek.Validate(); err != nil {
+		return err
+	}
+
+	queue, err := q.queueRepository.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return q.queueRepository.Seek(ctx, queue, seek.Timestamp)
+}
+
 // NewQueue returns an implementation of domain.QueueService.
 func NewQueue(queueRepository domain.QueueRepository) *Queue {
 	return &Queue{queueRepository: queueRepository}
@@ -121,4 +121,25 @@ func TestQueue(t *testing.T) {
 		err := queueService.Purge(ctx, queue.ID)
 		assert.Nil(t, err)
 	})
+
+	t.Run("Seek", func(t *testing.T) {
+		queueRepository := mocks.NewQueueRepository(t)
+		queueService := NewQueue(queueRepository)
+		queue := makeQueue("my-queue")
+		seek := domain.QueueSeek{Timestamp: time.Now().UTC().Add(-time.Hour)}
+
+		queueRepository.On("Get", ctx, queue.ID).Return(queue, nil)
+		queueRepository.On("Seek", ctx, queue, seek.Timestamp).Return(nil)
+
+		err := queueService.Seek(ctx, queue.ID, &seek)
+		assert.Nil(t, err)
+	})
+
+	t.Run("Seek without timestamp", func(t *testing.T) {
+		queueRepository := mocks.NewQueueRepository(t)
+		queueService := NewQueue(queueRepository)
+
+		err := queueService.Seek(ctx, "my-queue", &domain.QueueSeek{})
+		assert.Equal(t, "timestamp: cannot be blank.", err.Error())
+	})
 }
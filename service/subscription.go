@@ -4,12 +4,23 @@ import (
 	"context"
 	"time"
 
+	"github.com/oklog/ulid/v2"
+
 	"github.com/allisson/psqlqueue/domain"
 )
 
+// replayMaxMessages is the maximum number of messages enqueued by a replay, the replay is kept in memory and stored in a
+// single transaction.
+var replayMaxMessages = 10000
+
 // Subscription is an implementation of domain.SubscriptionService.
 type Subscription struct {
 	subscriptionRepository domain.SubscriptionRepository
+	topicRepository        domain.TopicRepository
+	queueRepository        domain.QueueRepository
+	topicMessageRepository domain.TopicMessageRepository
+	messageRepository      domain.MessageRepository
+	fanout                 *fanout
 }
 
 func (s *Subscription) Create(ctx context.Context, subscription *domain.Subscription) error {
@@ -17,11 +28,49 @@ func (s *Subscription) Create(ctx context.Context, subscription *domain.Subscrip
 		return err
 	}
 
-	subscription.CreatedAt = time.Now().UTC()
+	if subscription.TargetTopicID != nil {
+		targetTopic, err := s.topicRepository.Get(ctx, *subscription.TargetTopicID)
+		if err != nil {
+			return err
+		}
+
+		cycle, err := s.fanout.reaches(ctx, targetTopic.ID, subscription.TopicID)
+		if err != nil {
+			return err
+		}
+
+		if cycle {
+			return domain.ErrSubscriptionCycle
+		}
+	}
+
+	now := time.Now().UTC()
+	subscription.CreatedAt = now
+	subscription.UpdatedAt = now
 
 	return s.subscriptionRepository.Create(ctx, subscription)
 }
 
+func (s *Subscription) Update(ctx context.Context, subscription *domain.Subscription) error {
+	subscriptionFromDB, err := s.subscriptionRepository.Get(ctx, subscription.ID)
+	if err != nil {
+		return err
+	}
+
+	subscription.TopicID = subscriptionFromDB.TopicID
+	subscription.QueueID = subscriptionFromDB.QueueID
+	subscription.TargetTopicID = subscriptionFromDB.TargetTopicID
+
+	if err := subscription.Validate(); err != nil {
+		return err
+	}
+
+	subscription.CreatedAt = subscriptionFromDB.CreatedAt
+	subscription.UpdatedAt = time.Now().UTC()
+
+	return s.subscriptionRepository.Update(ctx, subscription)
+}
+
 func (s *Subscription) Get(ctx context.Context, id string) (*domain.Subscription, error) {
 	return s.subscriptionRepository.Get(ctx, id)
 }
@@ -30,6 +79,24 @@ func (s *Subscription) List(ctx context.Context, offset, limit uint) ([]*domain.
 	return s.subscriptionRepository.List(ctx, offset, limit)
 }
 
+func (s *Subscription) ListByTopic(ctx context.Context, topicID string, offset, limit uint) ([]*domain.Subscription, error) {
+	topic, err := s.topicRepository.Get(ctx, topicID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.subscriptionRepository.ListByTopic(ctx, topic.ID, offset, limit)
+}
+
+func (s *Subscription) ListByQueue(ctx context.Context, queueID string, offset, limit uint) ([]*domain.Subscription, error) {
+	queue, err := s.queueRepository.Get(ctx, queueID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.subscriptionRepository.ListByQueue(ctx, queue.ID, offset, limit)
+}
+
 func (s *Subscription) Delete(ctx context.Context, id string) error {
 	subscription, err := s.subscriptionRepository.Get(ctx, id)
 	if err != nil {
@@ -39,7 +106,81 @@ func (s *Subscription) Delete(ctx context.Context, id string) error {
 	return s.subscriptionRepository.Delete(ctx, subscription.ID)
 }
 
+func (s *Subscription) Replay(ctx context.Context, replay *domain.SubscriptionReplay) error {
+	if replay.To.IsZero() {
+		replay.To = time.Now().UTC()
+	}
+
+	if err := replay.Validate(); err != nil {
+		return err
+	}
+
+	subscription, err := s.subscriptionRepository.Get(ctx, replay.SubscriptionID)
+	if err != nil {
+		return err
+	}
+
+	replay.QueueID = subscription.QueueID
+	replay.TargetTopicID = subscription.TargetTopicID
+	replay.NumMessages = 0
+	messages := []*domain.Message{}
+	// the messages of a replay share a new publish id, so they can be told apart from the original publishes in the lineage.
+	replay.PublishID = ulid.Make().String()
+	now := time.Now().UTC()
+	offset := 0
+	limit := 50
+
+	for {
+		page, err := s.topicMessageRepository.ListByTopic(ctx, subscription.TopicID, replay.From, replay.To, uint(offset), uint(limit))
+		if err != nil {
+			return err
+		}
+
+		if len(page) == 0 {
+			break
+		}
+
+		for i := range page {
+			topicMessage := page[i]
+			message := topicMessage.Message()
+			if !subscription.ShouldCreateMessage(message) {
+				continue
+			}
+
+			// a subscription that targets a topic delivers the replay to the subscriptions of the target topic, the replayed
+			// messages are not added to the message log of the target topic again, otherwise a later replay would deliver them twice.
+			publish := newFanoutPublish(replay.PublishID, subscription.TopicID, topicMessage.CreatedAt, now, nil)
+			newMessages, err := s.fanout.deliver(ctx, publish, subscription, message, 0)
+			if err != nil {
+				return err
+			}
+			messages = append(messages, newMessages...)
+			if len(messages) > replayMaxMessages {
+				return domain.ErrSubscriptionReplayTooLarge
+			}
+		}
+
+		offset += limit
+	}
+
+	// the replay is stored in a single transaction, a failed replay can be retried without duplicating the messages.
+	if err := s.topicMessageRepository.Publish(ctx, &domain.TopicPublishBatch{Messages: messages}); err != nil {
+		return err
+	}
+
+	replay.NumMessages = uint(len(messages))
+
+	return nil
+}
+
 // NewSubscription returns an implementation of domain.SubscriptionService.
-func NewSubscription(subscriptionRepository domain.SubscriptionRepository) *Subscription {
-	return &Subscription{subscriptionRepository: subscriptionRepository}
+func NewSubscription(subscriptionRepository domain.SubscriptionRepository, topicRepository domain.TopicRepository, queueRepository domain.QueueRepository, topicMessageRepository domain.TopicMessageRepository, messageRepository domain.MessageRepository, maxHops uint) *Subscription {
+	return &Subscription{
+		subscriptionRepository: subscriptionRepository,
+		topicRepository:        topicRepository,
+		queueRepository:        queueRepository,
+		topicMessageRepository: topicMessageRepository,
+		messageRepository:      messageRepository,
+		fanout:                 newFanout(topicRepository, subscriptionRepository, queueRepository, maxHops),
+	}
 }
@@ -3,10 +3,12 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 
 	"github.com/allisson/psqlqueue/domain"
 	"github.com/allisson/psqlqueue/mocks"
@@ -16,8 +18,9 @@ func makeSubscription(id, topicID, queueID string) *domain.Subscription {
 	return &domain.Subscription{
 		ID:        id,
 		TopicID:   topicID,
-		QueueID:   queueID,
+		QueueID:   &queueID,
 		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
 	}
 }
 
@@ -26,7 +29,11 @@ func TestSubscription(t *testing.T) {
 
 	t.Run("Create", func(t *testing.T) {
 		subscriptionRepository := mocks.NewSubscriptionRepository(t)
-		subscriptionService := NewSubscription(subscriptionRepository)
+		topicRepository := mocks.NewTopicRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		topicMessageRepository := mocks.NewTopicMessageRepository(t)
+		messageRepository := mocks.NewMessageRepository(t)
+		subscriptionService := NewSubscription(subscriptionRepository, topicRepository, queueRepository, topicMessageRepository, messageRepository, 5)
 		subscription := makeSubscription("my-subscription", "my-topic", "my-queue")
 
 		subscriptionRepository.On("Create", ctx, subscription).Return(nil)
@@ -35,10 +42,55 @@ func TestSubscription(t *testing.T) {
 		assert.Nil(t, err)
 	})
 
+	t.Run("Create with target topic", func(t *testing.T) {
+		subscriptionRepository := mocks.NewSubscriptionRepository(t)
+		topicRepository := mocks.NewTopicRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		topicMessageRepository := mocks.NewTopicMessageRepository(t)
+		messageRepository := mocks.NewMessageRepository(t)
+		subscriptionService := NewSubscription(subscriptionRepository, topicRepository, queueRepository, topicMessageRepository, messageRepository, 5)
+		targetTopicID := "my-global-topic"
+		subscription := &domain.Subscription{ID: "my-subscription", TopicID: "my-regional-topic", TargetTopicID: &targetTopicID}
+
+		topicRepository.On("Get", ctx, targetTopicID).Return(makeTopic(targetTopicID), nil)
+		subscriptionRepository.On("ListByTopic", ctx, targetTopicID, uint(0), uint(50)).Return([]*domain.Subscription{makeSubscription("my-global-subscription", targetTopicID, "my-queue")}, nil)
+		subscriptionRepository.On("ListByTopic", ctx, targetTopicID, uint(50), uint(50)).Return([]*domain.Subscription{}, nil)
+		subscriptionRepository.On("Create", ctx, subscription).Return(nil)
+
+		err := subscriptionService.Create(ctx, subscription)
+		assert.Nil(t, err)
+	})
+
+	t.Run("Create with topic cycle", func(t *testing.T) {
+		subscriptionRepository := mocks.NewSubscriptionRepository(t)
+		topicRepository := mocks.NewTopicRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		topicMessageRepository := mocks.NewTopicMessageRepository(t)
+		messageRepository := mocks.NewMessageRepository(t)
+		subscriptionService := NewSubscription(subscriptionRepository, topicRepository, queueRepository, topicMessageRepository, messageRepository, 5)
+		topicID1 := "my-topic-1"
+		topicID2 := "my-topic-2"
+		topicID3 := "my-topic-3"
+		subscription := &domain.Subscription{ID: "my-subscription", TopicID: topicID1, TargetTopicID: &topicID2}
+
+		topicRepository.On("Get", ctx, topicID2).Return(makeTopic(topicID2), nil)
+		subscriptionRepository.On("ListByTopic", ctx, topicID2, uint(0), uint(50)).Return([]*domain.Subscription{{ID: "my-subscription-2", TopicID: topicID2, TargetTopicID: &topicID3}}, nil)
+		subscriptionRepository.On("ListByTopic", ctx, topicID2, uint(50), uint(50)).Return([]*domain.Subscription{}, nil)
+		subscriptionRepository.On("ListByTopic", ctx, topicID3, uint(0), uint(50)).Return([]*domain.Subscription{{ID: "my-subscription-3", TopicID: topicID3, TargetTopicID: &topicID1}}, nil)
+		subscriptionRepository.On("ListByTopic", ctx, topicID3, uint(50), uint(50)).Return([]*domain.Subscription{}, nil)
+
+		err := subscriptionService.Create(ctx, subscription)
+		assert.ErrorIs(t, err, domain.ErrSubscriptionCycle)
+	})
+
 	t.Run("Create with invalid id", func(t *testing.T) {
 		expectedErrorPayload := `{"id":"must be in a valid format"}`
 		subscriptionRepository := mocks.NewSubscriptionRepository(t)
-		subscriptionService := NewSubscription(subscriptionRepository)
+		topicRepository := mocks.NewTopicRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		topicMessageRepository := mocks.NewTopicMessageRepository(t)
+		messageRepository := mocks.NewMessageRepository(t)
+		subscriptionService := NewSubscription(subscriptionRepository, topicRepository, queueRepository, topicMessageRepository, messageRepository, 5)
 		subscription := makeSubscription("my@subscription", "my-topic", "my-queue")
 
 		err := subscriptionService.Create(ctx, subscription)
@@ -48,9 +100,58 @@ func TestSubscription(t *testing.T) {
 		assert.Equal(t, expectedErrorPayload, string(errorPayload))
 	})
 
+	t.Run("Update", func(t *testing.T) {
+		subscriptionRepository := mocks.NewSubscriptionRepository(t)
+		topicRepository := mocks.NewTopicRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		topicMessageRepository := mocks.NewTopicMessageRepository(t)
+		messageRepository := mocks.NewMessageRepository(t)
+		subscriptionService := NewSubscription(subscriptionRepository, topicRepository, queueRepository, topicMessageRepository, messageRepository, 5)
+		subscriptionFromDB := makeSubscription("my-subscription", "my-topic", "my-queue")
+		subscription := &domain.Subscription{
+			ID:             subscriptionFromDB.ID,
+			TopicID:        "another-topic",
+			MessageFilters: map[string][]string{"status": {"processed"}},
+		}
+
+		subscriptionRepository.On("Get", ctx, subscription.ID).Return(subscriptionFromDB, nil)
+		subscriptionRepository.On("Update", ctx, subscription).Return(nil)
+
+		err := subscriptionService.Update(ctx, subscription)
+		assert.Nil(t, err)
+		assert.Equal(t, subscriptionFromDB.TopicID, subscription.TopicID)
+		assert.Equal(t, subscriptionFromDB.QueueID, subscription.QueueID)
+		assert.Equal(t, subscriptionFromDB.CreatedAt, subscription.CreatedAt)
+		assert.False(t, subscription.UpdatedAt.IsZero())
+	})
+
+	t.Run("Update with invalid message filters", func(t *testing.T) {
+		expectedErrorPayload := `{"message_filters":{"status":"cannot be blank"}}`
+		subscriptionRepository := mocks.NewSubscriptionRepository(t)
+		topicRepository := mocks.NewTopicRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		topicMessageRepository := mocks.NewTopicMessageRepository(t)
+		messageRepository := mocks.NewMessageRepository(t)
+		subscriptionService := NewSubscription(subscriptionRepository, topicRepository, queueRepository, topicMessageRepository, messageRepository, 5)
+		subscriptionFromDB := makeSubscription("my-subscription", "my-topic", "my-queue")
+		subscription := &domain.Subscription{ID: subscriptionFromDB.ID, MessageFilters: map[string][]string{"status": {}}}
+
+		subscriptionRepository.On("Get", ctx, subscription.ID).Return(subscriptionFromDB, nil)
+
+		err := subscriptionService.Update(ctx, subscription)
+		assert.NotNil(t, err)
+		errorPayload, err := json.Marshal(err)
+		assert.Nil(t, err)
+		assert.Equal(t, expectedErrorPayload, string(errorPayload))
+	})
+
 	t.Run("Get", func(t *testing.T) {
 		subscriptionRepository := mocks.NewSubscriptionRepository(t)
-		subscriptionService := NewSubscription(subscriptionRepository)
+		topicRepository := mocks.NewTopicRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		topicMessageRepository := mocks.NewTopicMessageRepository(t)
+		messageRepository := mocks.NewMessageRepository(t)
+		subscriptionService := NewSubscription(subscriptionRepository, topicRepository, queueRepository, topicMessageRepository, messageRepository, 5)
 		subscription := makeSubscription("my-subscription", "my-topic", "my-queue")
 
 		subscriptionRepository.On("Get", ctx, subscription.ID).Return(subscription, nil)
@@ -61,7 +162,11 @@ func TestSubscription(t *testing.T) {
 
 	t.Run("List", func(t *testing.T) {
 		subscriptionRepository := mocks.NewSubscriptionRepository(t)
-		subscriptionService := NewSubscription(subscriptionRepository)
+		topicRepository := mocks.NewTopicRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		topicMessageRepository := mocks.NewTopicMessageRepository(t)
+		messageRepository := mocks.NewMessageRepository(t)
+		subscriptionService := NewSubscription(subscriptionRepository, topicRepository, queueRepository, topicMessageRepository, messageRepository, 5)
 		subscription1 := makeSubscription("my-subscription-1", "my-topic-1", "my-queue-1")
 		subscription2 := makeSubscription("my-subscription-1", "my-topic-1", "my-queue-2")
 
@@ -72,9 +177,79 @@ func TestSubscription(t *testing.T) {
 		assert.Len(t, subscriptions, 2)
 	})
 
+	t.Run("ListByTopic", func(t *testing.T) {
+		subscriptionRepository := mocks.NewSubscriptionRepository(t)
+		topicRepository := mocks.NewTopicRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		topicMessageRepository := mocks.NewTopicMessageRepository(t)
+		messageRepository := mocks.NewMessageRepository(t)
+		subscriptionService := NewSubscription(subscriptionRepository, topicRepository, queueRepository, topicMessageRepository, messageRepository, 5)
+		topic := makeTopic("my-topic")
+		subscription1 := makeSubscription("my-subscription-1", topic.ID, "my-queue-1")
+		subscription2 := makeSubscription("my-subscription-2", topic.ID, "my-queue-2")
+
+		topicRepository.On("Get", ctx, topic.ID).Return(topic, nil)
+		subscriptionRepository.On("ListByTopic", ctx, topic.ID, uint(0), uint(10)).Return([]*domain.Subscription{subscription1, subscription2}, nil)
+
+		subscriptions, err := subscriptionService.ListByTopic(ctx, topic.ID, uint(0), uint(10))
+		assert.Nil(t, err)
+		assert.Len(t, subscriptions, 2)
+	})
+
+	t.Run("ListByTopic with topic not found", func(t *testing.T) {
+		subscriptionRepository := mocks.NewSubscriptionRepository(t)
+		topicRepository := mocks.NewTopicRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		topicMessageRepository := mocks.NewTopicMessageRepository(t)
+		messageRepository := mocks.NewMessageRepository(t)
+		subscriptionService := NewSubscription(subscriptionRepository, topicRepository, queueRepository, topicMessageRepository, messageRepository, 5)
+
+		topicRepository.On("Get", ctx, "my-topic").Return(nil, domain.ErrTopicNotFound)
+
+		_, err := subscriptionService.ListByTopic(ctx, "my-topic", uint(0), uint(10))
+		assert.ErrorIs(t, err, domain.ErrTopicNotFound)
+	})
+
+	t.Run("ListByQueue", func(t *testing.T) {
+		subscriptionRepository := mocks.NewSubscriptionRepository(t)
+		topicRepository := mocks.NewTopicRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		topicMessageRepository := mocks.NewTopicMessageRepository(t)
+		messageRepository := mocks.NewMessageRepository(t)
+		subscriptionService := NewSubscription(subscriptionRepository, topicRepository, queueRepository, topicMessageRepository, messageRepository, 5)
+		queue := makeQueue("my-queue")
+		subscription1 := makeSubscription("my-subscription-1", "my-topic-1", queue.ID)
+		subscription2 := makeSubscription("my-subscription-2", "my-topic-2", queue.ID)
+
+		queueRepository.On("Get", ctx, queue.ID).Return(queue, nil)
+		subscriptionRepository.On("ListByQueue", ctx, queue.ID, uint(0), uint(10)).Return([]*domain.Subscription{subscription1, subscription2}, nil)
+
+		subscriptions, err := subscriptionService.ListByQueue(ctx, queue.ID, uint(0), uint(10))
+		assert.Nil(t, err)
+		assert.Len(t, subscriptions, 2)
+	})
+
+	t.Run("ListByQueue with queue not found", func(t *testing.T) {
+		subscriptionRepository := mocks.NewSubscriptionRepository(t)
+		topicRepository := mocks.NewTopicRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		topicMessageRepository := mocks.NewTopicMessageRepository(t)
+		messageRepository := mocks.NewMessageRepository(t)
+		subscriptionService := NewSubscription(subscriptionRepository, topicRepository, queueRepository, topicMessageRepository, messageRepository, 5)
+
+		queueRepository.On("Get", ctx, "my-queue").Return(nil, domain.ErrQueueNotFound)
+
+		_, err := subscriptionService.ListByQueue(ctx, "my-queue", uint(0), uint(10))
+		assert.ErrorIs(t, err, domain.ErrQueueNotFound)
+	})
+
 	t.Run("Delete", func(t *testing.T) {
 		subscriptionRepository := mocks.NewSubscriptionRepository(t)
-		subscriptionService := NewSubscription(subscriptionRepository)
+		topicRepository := mocks.NewTopicRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		topicMessageRepository := mocks.NewTopicMessageRepository(t)
+		messageRepository := mocks.NewMessageRepository(t)
+		subscriptionService := NewSubscription(subscriptionRepository, topicRepository, queueRepository, topicMessageRepository, messageRepository, 5)
 		subscription := makeSubscription("my-subscription", "my-topic", "my-queue")
 
 		subscriptionRepository.On("Get", ctx, subscription.ID).Return(subscription, nil)
@@ -83,4 +258,152 @@ func TestSubscription(t *testing.T) {
 		err := subscriptionService.Delete(ctx, subscription.ID)
 		assert.Nil(t, err)
 	})
+
+	t.Run("Replay", func(t *testing.T) {
+		subscriptionRepository := mocks.NewSubscriptionRepository(t)
+		topicRepository := mocks.NewTopicRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		topicMessageRepository := mocks.NewTopicMessageRepository(t)
+		messageRepository := mocks.NewMessageRepository(t)
+		subscriptionService := NewSubscription(subscriptionRepository, topicRepository, queueRepository, topicMessageRepository, messageRepository, 5)
+		queue := makeQueue("my-queue")
+		subscription := makeSubscription("my-subscription", "my-topic", queue.ID)
+		subscription.MessageFilters = map[string][]string{"status": {"processed"}}
+		now := time.Now().UTC()
+		replay := &domain.SubscriptionReplay{SubscriptionID: subscription.ID, From: now.Add(-time.Hour), To: now}
+		var messages []*domain.Message
+		topicMessages := []*domain.TopicMessage{
+			{ID: "1", TopicID: "my-topic", Body: "body-1", Attributes: map[string]string{"status": "processed"}},
+			{ID: "2", TopicID: "my-topic", Body: "body-2", Attributes: map[string]string{"status": "created"}},
+		}
+
+		subscriptionRepository.On("Get", ctx, subscription.ID).Return(subscription, nil)
+		queueRepository.On("Get", ctx, queue.ID).Return(queue, nil)
+		topicMessageRepository.On("ListByTopic", ctx, "my-topic", replay.From, replay.To, uint(0), uint(50)).Return(topicMessages, nil)
+		topicMessageRepository.On("ListByTopic", ctx, "my-topic", replay.From, replay.To, uint(50), uint(50)).Return([]*domain.TopicMessage{}, nil)
+		topicMessageRepository.On("Publish", ctx, mock.Anything).Return(func(ctx context.Context, batch *domain.TopicPublishBatch) error {
+			messages = batch.Messages
+			return nil
+		})
+
+		err := subscriptionService.Replay(ctx, replay)
+		assert.Nil(t, err)
+		assert.Equal(t, &queue.ID, replay.QueueID)
+		assert.Equal(t, uint(1), replay.NumMessages)
+		assert.NotEmpty(t, replay.PublishID)
+		assert.Len(t, messages, 1)
+		assert.Equal(t, "body-1", messages[0].Body)
+		assert.Equal(t, queue.ID, messages[0].QueueID)
+	})
+
+	t.Run("Replay with multiple pages", func(t *testing.T) {
+		subscriptionRepository := mocks.NewSubscriptionRepository(t)
+		topicRepository := mocks.NewTopicRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		topicMessageRepository := mocks.NewTopicMessageRepository(t)
+		messageRepository := mocks.NewMessageRepository(t)
+		subscriptionService := NewSubscription(subscriptionRepository, topicRepository, queueRepository, topicMessageRepository, messageRepository, 5)
+		queue := makeQueue("my-queue")
+		subscription := makeSubscription("my-subscription", "my-topic", queue.ID)
+		now := time.Now().UTC()
+		replay := &domain.SubscriptionReplay{SubscriptionID: subscription.ID, From: now.Add(-time.Hour), To: now}
+		var messages []*domain.Message
+
+		subscriptionRepository.On("Get", ctx, subscription.ID).Return(subscription, nil)
+		queueRepository.On("Get", ctx, queue.ID).Return(queue, nil)
+		topicMessageRepository.On("ListByTopic", ctx, "my-topic", replay.From, replay.To, uint(0), uint(50)).Return([]*domain.TopicMessage{{ID: "1", TopicID: "my-topic", Body: "body-1"}}, nil)
+		topicMessageRepository.On("ListByTopic", ctx, "my-topic", replay.From, replay.To, uint(50), uint(50)).Return([]*domain.TopicMessage{{ID: "2", TopicID: "my-topic", Body: "body-2"}}, nil)
+		topicMessageRepository.On("ListByTopic", ctx, "my-topic", replay.From, replay.To, uint(100), uint(50)).Return([]*domain.TopicMessage{}, nil)
+		topicMessageRepository.On("Publish", ctx, mock.Anything).Return(func(ctx context.Context, batch *domain.TopicPublishBatch) error {
+			messages = batch.Messages
+			return nil
+		}).Once()
+
+		err := subscriptionService.Replay(ctx, replay)
+		assert.Nil(t, err)
+		assert.Equal(t, uint(2), replay.NumMessages)
+		assert.Len(t, messages, 2)
+		assert.Equal(t, replay.PublishID, *messages[0].PublishID)
+		assert.Equal(t, replay.PublishID, *messages[1].PublishID)
+	})
+
+	t.Run("Replay with target topic", func(t *testing.T) {
+		subscriptionRepository := mocks.NewSubscriptionRepository(t)
+		topicRepository := mocks.NewTopicRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		topicMessageRepository := mocks.NewTopicMessageRepository(t)
+		messageRepository := mocks.NewMessageRepository(t)
+		subscriptionService := NewSubscription(subscriptionRepository, topicRepository, queueRepository, topicMessageRepository, messageRepository, 5)
+		queue := makeQueue("my-queue")
+		targetTopic := &domain.Topic{ID: "my-global-topic", MessageRetentionSeconds: 3600}
+		subscription := &domain.Subscription{ID: "my-subscription", TopicID: "my-topic", TargetTopicID: &targetTopic.ID}
+		targetSubscription := makeSubscription("my-global-subscription", targetTopic.ID, queue.ID)
+		now := time.Now().UTC()
+		replay := &domain.SubscriptionReplay{SubscriptionID: subscription.ID, From: now.Add(-time.Hour), To: now}
+		var batch *domain.TopicPublishBatch
+
+		subscriptionRepository.On("Get", ctx, subscription.ID).Return(subscription, nil)
+		subscriptionRepository.On("ListByTopic", ctx, targetTopic.ID, uint(0), uint(50)).Return([]*domain.Subscription{targetSubscription}, nil)
+		subscriptionRepository.On("ListByTopic", ctx, targetTopic.ID, uint(50), uint(50)).Return([]*domain.Subscription{}, nil)
+		topicRepository.On("Get", ctx, targetTopic.ID).Return(targetTopic, nil)
+		queueRepository.On("Get", ctx, queue.ID).Return(queue, nil)
+		topicMessageRepository.On("ListByTopic", ctx, "my-topic", replay.From, replay.To, uint(0), uint(50)).Return([]*domain.TopicMessage{{ID: "1", TopicID: "my-topic", Body: "body-1"}}, nil)
+		topicMessageRepository.On("ListByTopic", ctx, "my-topic", replay.From, replay.To, uint(50), uint(50)).Return([]*domain.TopicMessage{}, nil)
+		topicMessageRepository.On("Publish", ctx, mock.Anything).Return(func(ctx context.Context, publishBatch *domain.TopicPublishBatch) error {
+			batch = publishBatch
+			return nil
+		})
+
+		err := subscriptionService.Replay(ctx, replay)
+		assert.Nil(t, err)
+		assert.Equal(t, uint(1), replay.NumMessages)
+		assert.Len(t, batch.Messages, 1)
+		assert.Equal(t, queue.ID, batch.Messages[0].QueueID)
+		assert.Empty(t, batch.TopicMessages)
+	})
+
+	t.Run("Replay with too many messages", func(t *testing.T) {
+		defer func(maxMessages int) { replayMaxMessages = maxMessages }(replayMaxMessages)
+		replayMaxMessages = 1
+		subscriptionRepository := mocks.NewSubscriptionRepository(t)
+		topicRepository := mocks.NewTopicRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		topicMessageRepository := mocks.NewTopicMessageRepository(t)
+		messageRepository := mocks.NewMessageRepository(t)
+		subscriptionService := NewSubscription(subscriptionRepository, topicRepository, queueRepository, topicMessageRepository, messageRepository, 5)
+		queue := makeQueue("my-queue")
+		subscription := makeSubscription("my-subscription", "my-topic", queue.ID)
+		now := time.Now().UTC()
+		replay := &domain.SubscriptionReplay{SubscriptionID: subscription.ID, From: now.Add(-time.Hour), To: now}
+		topicMessages := []*domain.TopicMessage{
+			{ID: "1", TopicID: "my-topic", Body: "body-1"},
+			{ID: "2", TopicID: "my-topic", Body: "body-2"},
+		}
+
+		subscriptionRepository.On("Get", ctx, subscription.ID).Return(subscription, nil)
+		queueRepository.On("Get", ctx, queue.ID).Return(queue, nil)
+		topicMessageRepository.On("ListByTopic", ctx, "my-topic", replay.From, replay.To, uint(0), uint(50)).Return(topicMessages, nil)
+
+		err := subscriptionService.Replay(ctx, replay)
+		assert.Equal(t, domain.ErrSubscriptionReplayTooLarge, err)
+		topicMessageRepository.AssertNotCalled(t, "Publish", ctx, mock.Anything)
+	})
+
+	t.Run("Replay with invalid range", func(t *testing.T) {
+		expectedErrorPayload := `{"to":"must be no less than %s"}`
+		subscriptionRepository := mocks.NewSubscriptionRepository(t)
+		topicRepository := mocks.NewTopicRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		topicMessageRepository := mocks.NewTopicMessageRepository(t)
+		messageRepository := mocks.NewMessageRepository(t)
+		subscriptionService := NewSubscription(subscriptionRepository, topicRepository, queueRepository, topicMessageRepository, messageRepository, 5)
+		now := time.Now().UTC()
+		replay := &domain.SubscriptionReplay{SubscriptionID: "my-subscription", From: now, To: now.Add(-time.Hour)}
+
+		err := subscriptionService.Replay(ctx, replay)
+		assert.NotNil(t, err)
+		errorPayload, err := json.Marshal(err)
+		assert.Nil(t, err)
+		assert.Equal(t, fmt.Sprintf(expectedErrorPayload, replay.From), string(errorPayload))
+	})
 }
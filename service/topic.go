@@ -2,17 +2,21 @@ package service
 
 import (
 	"context"
+	"slices"
 	"time"
 
+	"github.com/oklog/ulid/v2"
+
 	"github.com/allisson/psqlqueue/domain"
 )
 
 // Topic is an implementation of domain.TopicService.
 type Topic struct {
 	topicRepository        domain.TopicRepository
-	subscriptionRepository domain.SubscriptionRepository
-	queueRepository        domain.QueueRepository
 	messageRepository      domain.MessageRepository
+	topicMessageRepository domain.TopicMessageRepository
+	topicStatsRepository   domain.TopicStatsRepository
+	fanout                 *fanout
 }
 
 func (t *Topic) Create(ctx context.Context, topic *domain.Topic) error {
@@ -42,63 +46,208 @@ func (t *Topic) Delete(ctx context.Context, id string) error {
 	return t.topicRepository.Delete(ctx, topic.ID)
 }
 
-func (t *Topic) CreateMessage(ctx context.Context, topicID string, message *domain.Message) error {
+func (t *Topic) CreateMessage(ctx context.Context, topicID string, message *domain.Message, requireMatch bool) (*domain.TopicPublish, error) {
 	if err := message.Validate(); err != nil {
-		return err
+		return nil, err
+	}
+
+	topic, err := t.topicRepository.Get(ctx, topicID)
+	if err != nil {
+		return nil, err
 	}
 
+	return t.publish(ctx, topic, message, requireMatch)
+}
+
+func (t *Topic) Ingest(ctx context.Context, topicID string, payload []byte, headers map[string]string, requireMatch bool) (*domain.TopicPublish, error) {
 	topic, err := t.topicRepository.Get(ctx, topicID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := topic.Ingestion.VerifySignature(payload, headers); err != nil {
+		return nil, err
+	}
+
+	message := topic.Ingestion.NewMessage(payload, headers)
+	if err := message.Validate(); err != nil {
+		return nil, err
+	}
+
+	return t.publish(ctx, topic, message, requireMatch)
+}
+
+// publish fans out a validated message published to the topic.
+func (t *Topic) publish(ctx context.Context, topic *domain.Topic, message *domain.Message, requireMatch bool) (*domain.TopicPublish, error) {
+	now := time.Now().UTC()
+	counters := newFanoutCounters(now)
+	fanoutPublish := newFanoutPublish(ulid.Make().String(), topic.ID, now, now, counters)
+	numMatchedSubscriptions, messages, err := t.fanout.route(ctx, fanoutPublish, topic, message, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	publish := &domain.TopicPublish{
+		ID:                      fanoutPublish.id,
+		TopicID:                 topic.ID,
+		NumMatchedSubscriptions: numMatchedSubscriptions,
+		MessageIDs:              []string{},
+	}
+	for i := range messages {
+		publish.MessageIDs = append(publish.MessageIDs, messages[i].ID)
+	}
+
+	if requireMatch && publish.NumMatchedSubscriptions == 0 {
+		return nil, domain.ErrTopicNoMatchingSubscription
+	}
+
+	topicCounters, subscriptionCounters := counters.counters()
+	batch := &domain.TopicPublishBatch{
+		TopicMessages:        fanoutPublish.topicMessages,
+		Messages:             messages,
+		TopicCounters:        topicCounters,
+		SubscriptionCounters: subscriptionCounters,
+	}
+	if err := t.topicMessageRepository.Publish(ctx, batch); err != nil {
+		return nil, err
+	}
+
+	recordMetrics(topicCounters, subscriptionCounters)
+
+	return publish, nil
+}
+
+// recordMetrics updates the prometheus metrics with the counters of a committed publish.
+func recordMetrics(topicCounters []*domain.TopicCounter, subscriptionCounters []*domain.SubscriptionCounter) {
+	for _, counter := range topicCounters {
+		topicPublishedMessagesTotal.WithLabelValues(counter.TopicID).Add(float64(counter.NumPublishedMessages))
+	}
+	for _, counter := range subscriptionCounters {
+		subscriptionDeliveredMessagesTotal.WithLabelValues(counter.TopicID, counter.SubscriptionID).Add(float64(counter.NumDeliveredMessages))
+		subscriptionFilteredMessagesTotal.WithLabelValues(counter.TopicID, counter.SubscriptionID).Add(float64(counter.NumFilteredMessages))
+	}
+}
+
+// Simulate matches the message against the subscriptions of the topic. The subscriptions of the target topics and the
+// transformations are not simulated.
+func (t *Topic) Simulate(ctx context.Context, topicID string, message *domain.Message) (*domain.TopicSimulation, error) {
+	if err := message.Validate(); err != nil {
+		return nil, err
+	}
+
+	topic, err := t.topicRepository.Get(ctx, topicID)
+	if err != nil {
+		return nil, err
+	}
+
+	subscriptions, err := t.fanout.listSubscriptions(ctx, topic.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	simulation := &domain.TopicSimulation{
+		TopicID:       topic.ID,
+		Subscriptions: []*domain.SubscriptionMatch{},
+	}
+
+	for i := range subscriptions {
+		match := subscriptions[i].Match(message)
+		if match.Matched {
+			simulation.NumMatchedSubscriptions++
+		}
+		simulation.Subscriptions = append(simulation.Subscriptions, match)
+	}
+
+	return simulation, nil
+}
+
+func (t *Topic) Cleanup(ctx context.Context, id string) error {
+	topic, err := t.topicRepository.Get(ctx, id)
 	if err != nil {
 		return err
 	}
 
-	messages := []*domain.Message{}
+	windowSeconds := slices.Max(domain.TopicStatsWindowsSeconds)
+	statsBefore := time.Now().UTC().Add(-time.Duration(windowSeconds) * time.Second)
+
+	return t.topicMessageRepository.Cleanup(ctx, topic.ID, statsBefore)
+}
+
+func (t *Topic) Stats(ctx context.Context, id string) (*domain.TopicStats, error) {
+	topic, err := t.topicRepository.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &domain.TopicStats{TopicID: topic.ID, Windows: []*domain.TopicStatsWindow{}}
+	now := time.Now().UTC()
+
+	for _, windowSeconds := range domain.TopicStatsWindowsSeconds {
+		since := now.Add(-time.Duration(windowSeconds) * time.Second).Truncate(time.Minute)
+		window, err := t.topicStatsRepository.Stats(ctx, topic.ID, since)
+		if err != nil {
+			return nil, err
+		}
+
+		window.WindowSeconds = windowSeconds
+		stats.Windows = append(stats.Windows, window)
+	}
+
+	return stats, nil
+}
+
+func (t *Topic) GetPublish(ctx context.Context, topicID, publishID string) (*domain.TopicPublishLineage, error) {
+	topic, err := t.topicRepository.Get(ctx, topicID)
+	if err != nil {
+		return nil, err
+	}
+
+	lineage := &domain.TopicPublishLineage{ID: publishID, TopicID: topic.ID, Messages: []*domain.TopicPublishMessage{}}
+	now := time.Now().UTC()
 	offset := 0
 	limit := 50
-	now := time.Now().UTC()
 
 	for {
-		subscriptions, err := t.subscriptionRepository.ListByTopic(ctx, topic.ID, uint(offset), uint(limit))
+		messages, err := t.messageRepository.ListByPublish(ctx, topic.ID, publishID, uint(offset), uint(limit))
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		if len(subscriptions) == 0 {
+		if len(messages) == 0 {
 			break
 		}
 
-		for i := range subscriptions {
-			subscription := subscriptions[i]
-			if !subscription.ShouldCreateMessage(message) {
-				continue
-			}
-
-			queue, err := t.queueRepository.Get(ctx, subscription.QueueID)
-			if err != nil {
-				return err
-			}
-
-			newMessage := &domain.Message{
-				Label:      message.Label,
-				Body:       message.Body,
-				Attributes: message.Attributes,
-			}
-			newMessage.Enqueue(queue, now)
-			messages = append(messages, newMessage)
+		for i := range messages {
+			message := messages[i]
+			lineage.Messages = append(lineage.Messages, &domain.TopicPublishMessage{
+				ID:                   message.ID,
+				QueueID:              message.QueueID,
+				SourceTopicID:        message.SourceTopicID,
+				SourceSubscriptionID: message.SourceSubscriptionID,
+				DeliveryAttempts:     message.DeliveryAttempts,
+				Status:               message.Status(now),
+				CreatedAt:            message.CreatedAt,
+				UpdatedAt:            message.UpdatedAt,
+			})
 		}
 
 		offset += limit
 	}
 
-	return t.messageRepository.CreateMany(ctx, messages)
+	if len(lineage.Messages) == 0 {
+		return nil, domain.ErrTopicPublishNotFound
+	}
+
+	return lineage, nil
 }
 
 // NewTopic returns an implementation of domain.TopicService.
-func NewTopic(topicRepository domain.TopicRepository, subscriptionRepository domain.SubscriptionRepository, queueRepository domain.QueueRepository, messageRepository domain.MessageRepository) *Topic {
+func NewTopic(topicRepository domain.TopicRepository, subscriptionRepository domain.SubscriptionRepository, queueRepository domain.QueueRepository, messageRepository domain.MessageRepository, topicMessageRepository domain.TopicMessageRepository, topicStatsRepository domain.TopicStatsRepository, maxHops uint) *Topic {
 	return &Topic{
 		topicRepository:        topicRepository,
-		subscriptionRepository: subscriptionRepository,
-		queueRepository:        queueRepository,
 		messageRepository:      messageRepository,
+		topicMessageRepository: topicMessageRepository,
+		topicStatsRepository:   topicStatsRepository,
+		fanout:                 newFanout(topicRepository, subscriptionRepository, queueRepository, maxHops),
 	}
 }
@@ -28,7 +28,9 @@ func TestTopic(t *testing.T) {
 		subscriptionRepository := mocks.NewSubscriptionRepository(t)
 		queueRepository := mocks.NewQueueRepository(t)
 		messageRepository := mocks.NewMessageRepository(t)
-		topicService := NewTopic(topicRepository, subscriptionRepository, queueRepository, messageRepository)
+		topicMessageRepository := mocks.NewTopicMessageRepository(t)
+		topicStatsRepository := mocks.NewTopicStatsRepository(t)
+		topicService := NewTopic(topicRepository, subscriptionRepository, queueRepository, messageRepository, topicMessageRepository, topicStatsRepository, 5)
 		topic := makeTopic("my-topic")
 
 		topicRepository.On("Create", ctx, topic).Return(nil)
@@ -43,7 +45,9 @@ func TestTopic(t *testing.T) {
 		subscriptionRepository := mocks.NewSubscriptionRepository(t)
 		queueRepository := mocks.NewQueueRepository(t)
 		messageRepository := mocks.NewMessageRepository(t)
-		topicService := NewTopic(topicRepository, subscriptionRepository, queueRepository, messageRepository)
+		topicMessageRepository := mocks.NewTopicMessageRepository(t)
+		topicStatsRepository := mocks.NewTopicStatsRepository(t)
+		topicService := NewTopic(topicRepository, subscriptionRepository, queueRepository, messageRepository, topicMessageRepository, topicStatsRepository, 5)
 		topic := makeTopic("my@topic")
 
 		err := topicService.Create(ctx, topic)
@@ -58,7 +62,9 @@ func TestTopic(t *testing.T) {
 		subscriptionRepository := mocks.NewSubscriptionRepository(t)
 		queueRepository := mocks.NewQueueRepository(t)
 		messageRepository := mocks.NewMessageRepository(t)
-		topicService := NewTopic(topicRepository, subscriptionRepository, queueRepository, messageRepository)
+		topicMessageRepository := mocks.NewTopicMessageRepository(t)
+		topicStatsRepository := mocks.NewTopicStatsRepository(t)
+		topicService := NewTopic(topicRepository, subscriptionRepository, queueRepository, messageRepository, topicMessageRepository, topicStatsRepository, 5)
 		topic := makeTopic("my-topic")
 
 		topicRepository.On("Get", ctx, topic.ID).Return(topic, nil)
@@ -72,7 +78,9 @@ func TestTopic(t *testing.T) {
 		subscriptionRepository := mocks.NewSubscriptionRepository(t)
 		queueRepository := mocks.NewQueueRepository(t)
 		messageRepository := mocks.NewMessageRepository(t)
-		topicService := NewTopic(topicRepository, subscriptionRepository, queueRepository, messageRepository)
+		topicMessageRepository := mocks.NewTopicMessageRepository(t)
+		topicStatsRepository := mocks.NewTopicStatsRepository(t)
+		topicService := NewTopic(topicRepository, subscriptionRepository, queueRepository, messageRepository, topicMessageRepository, topicStatsRepository, 5)
 		topic1 := makeTopic("my-topic-1")
 		topic2 := makeTopic("my-topic-2")
 
@@ -88,7 +96,9 @@ func TestTopic(t *testing.T) {
 		subscriptionRepository := mocks.NewSubscriptionRepository(t)
 		queueRepository := mocks.NewQueueRepository(t)
 		messageRepository := mocks.NewMessageRepository(t)
-		topicService := NewTopic(topicRepository, subscriptionRepository, queueRepository, messageRepository)
+		topicMessageRepository := mocks.NewTopicMessageRepository(t)
+		topicStatsRepository := mocks.NewTopicStatsRepository(t)
+		topicService := NewTopic(topicRepository, subscriptionRepository, queueRepository, messageRepository, topicMessageRepository, topicStatsRepository, 5)
 		topic := makeTopic("my-topic")
 
 		topicRepository.On("Get", ctx, topic.ID).Return(topic, nil)
@@ -103,19 +113,463 @@ func TestTopic(t *testing.T) {
 		subscriptionRepository := mocks.NewSubscriptionRepository(t)
 		queueRepository := mocks.NewQueueRepository(t)
 		messageRepository := mocks.NewMessageRepository(t)
-		topicService := NewTopic(topicRepository, subscriptionRepository, queueRepository, messageRepository)
+		topicMessageRepository := mocks.NewTopicMessageRepository(t)
+		topicStatsRepository := mocks.NewTopicStatsRepository(t)
+		topicService := NewTopic(topicRepository, subscriptionRepository, queueRepository, messageRepository, topicMessageRepository, topicStatsRepository, 5)
 		topic := makeTopic("my-topic")
 		queue := makeQueue("my-queue")
 		subscription := makeSubscription("my-subscription", topic.ID, queue.ID)
 		message := &domain.Message{Body: "my-message-body"}
+		var createdMessages []*domain.Message
 
 		topicRepository.On("Get", ctx, topic.ID).Return(topic, nil)
 		subscriptionRepository.On("ListByTopic", ctx, topic.ID, uint(0), uint(50)).Return([]*domain.Subscription{subscription}, nil)
 		subscriptionRepository.On("ListByTopic", ctx, topic.ID, uint(50), uint(50)).Return([]*domain.Subscription{}, nil)
 		queueRepository.On("Get", ctx, queue.ID).Return(queue, nil)
-		messageRepository.On("CreateMany", ctx, mock.Anything).Return(nil)
+		topicMessageRepository.On("Publish", ctx, mock.Anything).Return(func(ctx context.Context, batch *domain.TopicPublishBatch) error {
+			createdMessages = batch.Messages
+			return nil
+		})
 
-		err := topicService.CreateMessage(ctx, topic.ID, message)
+		publish, err := topicService.CreateMessage(ctx, topic.ID, message, false)
 		assert.Nil(t, err)
+		assert.NotEmpty(t, publish.ID)
+		assert.Equal(t, topic.ID, publish.TopicID)
+		assert.Equal(t, uint(1), publish.NumMatchedSubscriptions)
+		assert.Len(t, publish.MessageIDs, 1)
+		assert.Len(t, createdMessages, 1)
+		assert.Equal(t, publish.ID, *createdMessages[0].PublishID)
+		assert.Equal(t, topic.ID, *createdMessages[0].SourceTopicID)
+		assert.Equal(t, subscription.ID, *createdMessages[0].SourceSubscriptionID)
+	})
+
+	t.Run("CreateMessage with transformation", func(t *testing.T) {
+		topicRepository := mocks.NewTopicRepository(t)
+		subscriptionRepository := mocks.NewSubscriptionRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		messageRepository := mocks.NewMessageRepository(t)
+		topicMessageRepository := mocks.NewTopicMessageRepository(t)
+		topicStatsRepository := mocks.NewTopicStatsRepository(t)
+		topicService := NewTopic(topicRepository, subscriptionRepository, queueRepository, messageRepository, topicMessageRepository, topicStatsRepository, 5)
+		topic := makeTopic("my-topic")
+		queue1 := makeQueue("my-queue-1")
+		queue2 := makeQueue("my-queue-2")
+		subscription1 := makeSubscription("my-subscription-1", topic.ID, queue1.ID)
+		subscription1.Transformation = &domain.SubscriptionTransformation{RemoveAttributes: []string{"internal_id"}}
+		subscription2 := makeSubscription("my-subscription-2", topic.ID, queue2.ID)
+		message := &domain.Message{Body: "my-message-body", Attributes: map[string]string{"internal_id": "1"}}
+
+		topicRepository.On("Get", ctx, topic.ID).Return(topic, nil)
+		subscriptionRepository.On("ListByTopic", ctx, topic.ID, uint(0), uint(50)).Return([]*domain.Subscription{subscription1, subscription2}, nil)
+		subscriptionRepository.On("ListByTopic", ctx, topic.ID, uint(50), uint(50)).Return([]*domain.Subscription{}, nil)
+		queueRepository.On("Get", ctx, queue1.ID).Return(queue1, nil)
+		queueRepository.On("Get", ctx, queue2.ID).Return(queue2, nil)
+		topicMessageRepository.On("Publish", ctx, mock.Anything).Return(nil)
+
+		publish, err := topicService.CreateMessage(ctx, topic.ID, message, false)
+		assert.Nil(t, err)
+		assert.Equal(t, uint(2), publish.NumMatchedSubscriptions)
+		messages := topicMessageRepository.Calls[0].Arguments.Get(1).(*domain.TopicPublishBatch).Messages
+		assert.Equal(t, map[string]string{}, messages[0].Attributes)
+		assert.Equal(t, map[string]string{"internal_id": "1"}, messages[1].Attributes)
+	})
+
+	t.Run("CreateMessage with target topic", func(t *testing.T) {
+		topicRepository := mocks.NewTopicRepository(t)
+		subscriptionRepository := mocks.NewSubscriptionRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		messageRepository := mocks.NewMessageRepository(t)
+		topicMessageRepository := mocks.NewTopicMessageRepository(t)
+		topicStatsRepository := mocks.NewTopicStatsRepository(t)
+		topicService := NewTopic(topicRepository, subscriptionRepository, queueRepository, messageRepository, topicMessageRepository, topicStatsRepository, 5)
+		regionalTopic := makeTopic("my-regional-topic")
+		globalTopicID := "my-global-topic"
+		queue := makeQueue("my-queue")
+		regionalSubscription := &domain.Subscription{ID: "my-regional-subscription", TopicID: regionalTopic.ID, TargetTopicID: &globalTopicID}
+		globalSubscription := makeSubscription("my-global-subscription", globalTopicID, queue.ID)
+		message := &domain.Message{Body: "my-message-body"}
+
+		topicRepository.On("Get", ctx, regionalTopic.ID).Return(regionalTopic, nil)
+		topicRepository.On("Get", ctx, globalTopicID).Return(makeTopic(globalTopicID), nil)
+		subscriptionRepository.On("ListByTopic", ctx, regionalTopic.ID, uint(0), uint(50)).Return([]*domain.Subscription{regionalSubscription}, nil)
+		subscriptionRepository.On("ListByTopic", ctx, regionalTopic.ID, uint(50), uint(50)).Return([]*domain.Subscription{}, nil)
+		subscriptionRepository.On("ListByTopic", ctx, globalTopicID, uint(0), uint(50)).Return([]*domain.Subscription{globalSubscription}, nil)
+		subscriptionRepository.On("ListByTopic", ctx, globalTopicID, uint(50), uint(50)).Return([]*domain.Subscription{}, nil)
+		queueRepository.On("Get", ctx, queue.ID).Return(queue, nil)
+		topicMessageRepository.On("Publish", ctx, mock.Anything).Return(nil)
+
+		publish, err := topicService.CreateMessage(ctx, regionalTopic.ID, message, false)
+		assert.Nil(t, err)
+		assert.Equal(t, uint(1), publish.NumMatchedSubscriptions)
+		assert.Len(t, publish.MessageIDs, 1)
+		messages := topicMessageRepository.Calls[0].Arguments.Get(1).(*domain.TopicPublishBatch).Messages
+		assert.Equal(t, queue.ID, messages[0].QueueID)
+	})
+
+	t.Run("CreateMessage with max hops exceeded", func(t *testing.T) {
+		topicRepository := mocks.NewTopicRepository(t)
+		subscriptionRepository := mocks.NewSubscriptionRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		messageRepository := mocks.NewMessageRepository(t)
+		topicMessageRepository := mocks.NewTopicMessageRepository(t)
+		topicStatsRepository := mocks.NewTopicStatsRepository(t)
+		topicService := NewTopic(topicRepository, subscriptionRepository, queueRepository, messageRepository, topicMessageRepository, topicStatsRepository, 1)
+		topicID1 := "my-topic-1"
+		topicID2 := "my-topic-2"
+		topicID3 := "my-topic-3"
+		message := &domain.Message{Body: "my-message-body"}
+
+		topicRepository.On("Get", ctx, topicID1).Return(makeTopic(topicID1), nil)
+		topicRepository.On("Get", ctx, topicID2).Return(makeTopic(topicID2), nil)
+		subscriptionRepository.On("ListByTopic", ctx, topicID1, uint(0), uint(50)).Return([]*domain.Subscription{{ID: "my-subscription-1", TopicID: topicID1, TargetTopicID: &topicID2}}, nil)
+		subscriptionRepository.On("ListByTopic", ctx, topicID1, uint(50), uint(50)).Return([]*domain.Subscription{}, nil)
+		subscriptionRepository.On("ListByTopic", ctx, topicID2, uint(0), uint(50)).Return([]*domain.Subscription{{ID: "my-subscription-2", TopicID: topicID2, TargetTopicID: &topicID3}}, nil)
+		subscriptionRepository.On("ListByTopic", ctx, topicID2, uint(50), uint(50)).Return([]*domain.Subscription{}, nil)
+
+		_, err := topicService.CreateMessage(ctx, topicID1, message, false)
+		assert.ErrorIs(t, err, domain.ErrTopicMaxHopsExceeded)
+	})
+
+	t.Run("CreateMessage with diamond topology", func(t *testing.T) {
+		topicRepository := mocks.NewTopicRepository(t)
+		subscriptionRepository := mocks.NewSubscriptionRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		messageRepository := mocks.NewMessageRepository(t)
+		topicMessageRepository := mocks.NewTopicMessageRepository(t)
+		topicStatsRepository := mocks.NewTopicStatsRepository(t)
+		topicService := NewTopic(topicRepository, subscriptionRepository, queueRepository, messageRepository, topicMessageRepository, topicStatsRepository, 5)
+		topicA := makeTopic("my-topic-a")
+		topicB := makeTopic("my-topic-b")
+		topicC := makeTopic("my-topic-c")
+		queue := makeQueue("my-queue")
+		message := &domain.Message{Body: "my-message-body"}
+
+		topicRepository.On("Get", ctx, topicA.ID).Return(topicA, nil)
+		topicRepository.On("Get", ctx, topicB.ID).Return(topicB, nil)
+		topicRepository.On("Get", ctx, topicC.ID).Return(topicC, nil)
+		subscriptionRepository.On("ListByTopic", ctx, topicA.ID, uint(0), uint(50)).Return([]*domain.Subscription{
+			{ID: "my-subscription-ab", TopicID: topicA.ID, TargetTopicID: &topicB.ID},
+			{ID: "my-subscription-ac", TopicID: topicA.ID, TargetTopicID: &topicC.ID},
+		}, nil)
+		subscriptionRepository.On("ListByTopic", ctx, topicA.ID, uint(50), uint(50)).Return([]*domain.Subscription{}, nil)
+		subscriptionRepository.On("ListByTopic", ctx, topicB.ID, uint(0), uint(50)).Return([]*domain.Subscription{makeSubscription("my-subscription-bq", topicB.ID, queue.ID)}, nil)
+		subscriptionRepository.On("ListByTopic", ctx, topicB.ID, uint(50), uint(50)).Return([]*domain.Subscription{}, nil)
+		subscriptionRepository.On("ListByTopic", ctx, topicC.ID, uint(0), uint(50)).Return([]*domain.Subscription{makeSubscription("my-subscription-cq", topicC.ID, queue.ID)}, nil)
+		subscriptionRepository.On("ListByTopic", ctx, topicC.ID, uint(50), uint(50)).Return([]*domain.Subscription{}, nil)
+		queueRepository.On("Get", ctx, queue.ID).Return(queue, nil).Once()
+		topicMessageRepository.On("Publish", ctx, mock.Anything).Return(nil)
+
+		publish, err := topicService.CreateMessage(ctx, topicA.ID, message, false)
+		assert.Nil(t, err)
+		assert.Len(t, publish.MessageIDs, 1)
+		messages := topicMessageRepository.Calls[0].Arguments.Get(1).(*domain.TopicPublishBatch).Messages
+		assert.Len(t, messages, 1)
+		assert.Equal(t, "my-subscription-bq", *messages[0].SourceSubscriptionID)
+		assert.Equal(t, topicA.ID, *messages[0].SourceTopicID)
+	})
+
+	t.Run("CreateMessage with message retention", func(t *testing.T) {
+		topicRepository := mocks.NewTopicRepository(t)
+		subscriptionRepository := mocks.NewSubscriptionRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		messageRepository := mocks.NewMessageRepository(t)
+		topicMessageRepository := mocks.NewTopicMessageRepository(t)
+		topicStatsRepository := mocks.NewTopicStatsRepository(t)
+		topicService := NewTopic(topicRepository, subscriptionRepository, queueRepository, messageRepository, topicMessageRepository, topicStatsRepository, 5)
+		topic := makeTopic("my-topic")
+		topic.MessageRetentionSeconds = 3600
+		queue := makeQueue("my-queue")
+		subscription := makeSubscription("my-subscription", topic.ID, queue.ID)
+		message := &domain.Message{Body: "my-message-body"}
+
+		topicRepository.On("Get", ctx, topic.ID).Return(topic, nil)
+		subscriptionRepository.On("ListByTopic", ctx, topic.ID, uint(0), uint(50)).Return([]*domain.Subscription{subscription}, nil)
+		subscriptionRepository.On("ListByTopic", ctx, topic.ID, uint(50), uint(50)).Return([]*domain.Subscription{}, nil)
+		queueRepository.On("Get", ctx, queue.ID).Return(queue, nil)
+		topicMessageRepository.On("Publish", ctx, mock.Anything).Return(nil)
+
+		publish, err := topicService.CreateMessage(ctx, topic.ID, message, false)
+		assert.Nil(t, err)
+		assert.Equal(t, uint(1), publish.NumMatchedSubscriptions)
+		topicMessages := topicMessageRepository.Calls[0].Arguments.Get(1).(*domain.TopicPublishBatch).TopicMessages
+		assert.Len(t, topicMessages, 1)
+		topicMessage := topicMessages[0]
+		assert.Equal(t, topic.ID, topicMessage.TopicID)
+		assert.Equal(t, message.Body, topicMessage.Body)
+		assert.Equal(t, topicMessage.CreatedAt.Add(time.Hour), topicMessage.ExpiredAt)
+	})
+
+	t.Run("CreateMessage with target topic message retention", func(t *testing.T) {
+		topicRepository := mocks.NewTopicRepository(t)
+		subscriptionRepository := mocks.NewSubscriptionRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		messageRepository := mocks.NewMessageRepository(t)
+		topicMessageRepository := mocks.NewTopicMessageRepository(t)
+		topicStatsRepository := mocks.NewTopicStatsRepository(t)
+		topicService := NewTopic(topicRepository, subscriptionRepository, queueRepository, messageRepository, topicMessageRepository, topicStatsRepository, 5)
+		regionalTopic := makeTopic("my-regional-topic")
+		globalTopic := makeTopic("my-global-topic")
+		globalTopic.MessageRetentionSeconds = 3600
+		regionalSubscription := &domain.Subscription{ID: "my-regional-subscription", TopicID: regionalTopic.ID, TargetTopicID: &globalTopic.ID}
+		message := &domain.Message{Body: "my-message-body"}
+
+		topicRepository.On("Get", ctx, regionalTopic.ID).Return(regionalTopic, nil)
+		topicRepository.On("Get", ctx, globalTopic.ID).Return(globalTopic, nil)
+		subscriptionRepository.On("ListByTopic", ctx, regionalTopic.ID, uint(0), uint(50)).Return([]*domain.Subscription{regionalSubscription}, nil)
+		subscriptionRepository.On("ListByTopic", ctx, regionalTopic.ID, uint(50), uint(50)).Return([]*domain.Subscription{}, nil)
+		subscriptionRepository.On("ListByTopic", ctx, globalTopic.ID, uint(0), uint(50)).Return([]*domain.Subscription{}, nil)
+		topicMessageRepository.On("Publish", ctx, mock.Anything).Return(nil)
+
+		_, err := topicService.CreateMessage(ctx, regionalTopic.ID, message, false)
+		assert.Nil(t, err)
+		topicMessages := topicMessageRepository.Calls[0].Arguments.Get(1).(*domain.TopicPublishBatch).TopicMessages
+		assert.Len(t, topicMessages, 1)
+		assert.Equal(t, globalTopic.ID, topicMessages[0].TopicID)
+	})
+
+	t.Run("CreateMessage without matching subscriptions", func(t *testing.T) {
+		topicRepository := mocks.NewTopicRepository(t)
+		subscriptionRepository := mocks.NewSubscriptionRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		messageRepository := mocks.NewMessageRepository(t)
+		topicMessageRepository := mocks.NewTopicMessageRepository(t)
+		topicStatsRepository := mocks.NewTopicStatsRepository(t)
+		topicService := NewTopic(topicRepository, subscriptionRepository, queueRepository, messageRepository, topicMessageRepository, topicStatsRepository, 5)
+		topic := makeTopic("my-topic")
+		subscription := makeSubscription("my-subscription", topic.ID, "my-queue")
+		subscription.MessageFilters = map[string][]string{"type": {"order"}}
+		message := &domain.Message{Body: "my-message-body", Attributes: map[string]string{"type": "user"}}
+
+		topicRepository.On("Get", ctx, topic.ID).Return(topic, nil)
+		subscriptionRepository.On("ListByTopic", ctx, topic.ID, uint(0), uint(50)).Return([]*domain.Subscription{subscription}, nil)
+		subscriptionRepository.On("ListByTopic", ctx, topic.ID, uint(50), uint(50)).Return([]*domain.Subscription{}, nil)
+		topicMessageRepository.On("Publish", ctx, mock.Anything).Return(nil)
+
+		publish, err := topicService.CreateMessage(ctx, topic.ID, message, false)
+		assert.Nil(t, err)
+		assert.Equal(t, uint(0), publish.NumMatchedSubscriptions)
+		assert.Len(t, publish.MessageIDs, 0)
+		topicCounters := topicMessageRepository.Calls[0].Arguments.Get(1).(*domain.TopicPublishBatch).TopicCounters
+		subscriptionCounters := topicMessageRepository.Calls[0].Arguments.Get(1).(*domain.TopicPublishBatch).SubscriptionCounters
+		assert.Len(t, topicCounters, 1)
+		assert.Equal(t, uint(1), topicCounters[0].NumPublishedMessages)
+		assert.Len(t, subscriptionCounters, 1)
+		assert.Equal(t, uint(0), subscriptionCounters[0].NumDeliveredMessages)
+		assert.Equal(t, uint(1), subscriptionCounters[0].NumFilteredMessages)
+	})
+
+	t.Run("CreateMessage with require match", func(t *testing.T) {
+		topicRepository := mocks.NewTopicRepository(t)
+		subscriptionRepository := mocks.NewSubscriptionRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		messageRepository := mocks.NewMessageRepository(t)
+		topicMessageRepository := mocks.NewTopicMessageRepository(t)
+		topicStatsRepository := mocks.NewTopicStatsRepository(t)
+		topicService := NewTopic(topicRepository, subscriptionRepository, queueRepository, messageRepository, topicMessageRepository, topicStatsRepository, 5)
+		topic := makeTopic("my-topic")
+		message := &domain.Message{Body: "my-message-body"}
+
+		topicRepository.On("Get", ctx, topic.ID).Return(topic, nil)
+		subscriptionRepository.On("ListByTopic", ctx, topic.ID, uint(0), uint(50)).Return([]*domain.Subscription{}, nil)
+
+		_, err := topicService.CreateMessage(ctx, topic.ID, message, true)
+		assert.ErrorIs(t, err, domain.ErrTopicNoMatchingSubscription)
+	})
+
+	t.Run("Ingest", func(t *testing.T) {
+		topicRepository := mocks.NewTopicRepository(t)
+		subscriptionRepository := mocks.NewSubscriptionRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		messageRepository := mocks.NewMessageRepository(t)
+		topicMessageRepository := mocks.NewTopicMessageRepository(t)
+		topicStatsRepository := mocks.NewTopicStatsRepository(t)
+		topicService := NewTopic(topicRepository, subscriptionRepository, queueRepository, messageRepository, topicMessageRepository, topicStatsRepository, 5)
+		topic := makeTopic("my-topic")
+		eventHeader := "X-Event"
+		signatureHeader := "X-Signature"
+		signatureSecret := "my-secret"
+		topic.Ingestion = &domain.TopicIngestion{
+			HeaderAttributes: map[string]string{eventHeader: "event"},
+			SignatureHeader:  &signatureHeader,
+			SignaturePrefix:  "sha256=",
+			SignatureSecret:  &signatureSecret,
+		}
+		queue := makeQueue("my-queue")
+		subscription := makeSubscription("my-subscription", topic.ID, queue.ID)
+		subscription.MessageFilters = map[string][]string{"event": {"push"}}
+		headers := map[string]string{
+			"x-event":     "push",
+			"x-signature": "sha256=d978f3b056c5f81d6fb61dfd16d84823db0633148f19fdaff5ce461fccad6d44",
+		}
+		var createdMessages []*domain.Message
+
+		topicRepository.On("Get", ctx, topic.ID).Return(topic, nil)
+		subscriptionRepository.On("ListByTopic", ctx, topic.ID, uint(0), uint(50)).Return([]*domain.Subscription{subscription}, nil)
+		subscriptionRepository.On("ListByTopic", ctx, topic.ID, uint(50), uint(50)).Return([]*domain.Subscription{}, nil)
+		queueRepository.On("Get", ctx, queue.ID).Return(queue, nil)
+		topicMessageRepository.On("Publish", ctx, mock.Anything).Return(func(ctx context.Context, batch *domain.TopicPublishBatch) error {
+			createdMessages = batch.Messages
+			return nil
+		})
+
+		publish, err := topicService.Ingest(ctx, topic.ID, []byte("my-payload"), headers, true)
+		assert.Nil(t, err)
+		assert.Equal(t, uint(1), publish.NumMatchedSubscriptions)
+		assert.Len(t, createdMessages, 1)
+		assert.Equal(t, "my-payload", createdMessages[0].Body)
+		assert.Equal(t, map[string]string{"event": "push"}, createdMessages[0].Attributes)
+	})
+
+	t.Run("Ingest with invalid signature", func(t *testing.T) {
+		topicRepository := mocks.NewTopicRepository(t)
+		subscriptionRepository := mocks.NewSubscriptionRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		messageRepository := mocks.NewMessageRepository(t)
+		topicMessageRepository := mocks.NewTopicMessageRepository(t)
+		topicStatsRepository := mocks.NewTopicStatsRepository(t)
+		topicService := NewTopic(topicRepository, subscriptionRepository, queueRepository, messageRepository, topicMessageRepository, topicStatsRepository, 5)
+		topic := makeTopic("my-topic")
+		signatureHeader := "X-Signature"
+		signatureSecret := "my-secret"
+		topic.Ingestion = &domain.TopicIngestion{SignatureHeader: &signatureHeader, SignatureSecret: &signatureSecret}
+
+		topicRepository.On("Get", ctx, topic.ID).Return(topic, nil)
+
+		_, err := topicService.Ingest(ctx, topic.ID, []byte("my-payload"), map[string]string{"x-signature": "invalid"}, false)
+		assert.ErrorIs(t, err, domain.ErrTopicIngestionInvalidSignature)
+	})
+
+	t.Run("Ingest with invalid payload", func(t *testing.T) {
+		topicRepository := mocks.NewTopicRepository(t)
+		subscriptionRepository := mocks.NewSubscriptionRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		messageRepository := mocks.NewMessageRepository(t)
+		topicMessageRepository := mocks.NewTopicMessageRepository(t)
+		topicStatsRepository := mocks.NewTopicStatsRepository(t)
+		topicService := NewTopic(topicRepository, subscriptionRepository, queueRepository, messageRepository, topicMessageRepository, topicStatsRepository, 5)
+		topic := makeTopic("my-topic")
+
+		topicRepository.On("Get", ctx, topic.ID).Return(topic, nil)
+
+		_, err := topicService.Ingest(ctx, topic.ID, []byte{0xff, 0xfe}, map[string]string{}, false)
+		assert.Equal(t, `body: must be valid UTF-8 without null characters.`, err.Error())
+	})
+
+	t.Run("Simulate", func(t *testing.T) {
+		topicRepository := mocks.NewTopicRepository(t)
+		subscriptionRepository := mocks.NewSubscriptionRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		messageRepository := mocks.NewMessageRepository(t)
+		topicMessageRepository := mocks.NewTopicMessageRepository(t)
+		topicStatsRepository := mocks.NewTopicStatsRepository(t)
+		topicService := NewTopic(topicRepository, subscriptionRepository, queueRepository, messageRepository, topicMessageRepository, topicStatsRepository, 5)
+		topic := makeTopic("my-topic")
+		subscription1 := makeSubscription("my-subscription-1", topic.ID, "my-queue-1")
+		subscription2 := makeSubscription("my-subscription-2", topic.ID, "my-queue-2")
+		subscription2.MessageFilters = map[string][]string{"type": {"order"}}
+		message := &domain.Message{Body: "my-message-body", Attributes: map[string]string{"type": "user"}}
+
+		topicRepository.On("Get", ctx, topic.ID).Return(topic, nil)
+		subscriptionRepository.On("ListByTopic", ctx, topic.ID, uint(0), uint(50)).Return([]*domain.Subscription{subscription1, subscription2}, nil)
+		subscriptionRepository.On("ListByTopic", ctx, topic.ID, uint(50), uint(50)).Return([]*domain.Subscription{}, nil)
+
+		simulation, err := topicService.Simulate(ctx, topic.ID, message)
+		assert.Nil(t, err)
+		assert.Equal(t, topic.ID, simulation.TopicID)
+		assert.Equal(t, uint(1), simulation.NumMatchedSubscriptions)
+		assert.Len(t, simulation.Subscriptions, 2)
+		assert.True(t, simulation.Subscriptions[0].Matched)
+		assert.Nil(t, simulation.Subscriptions[0].RejectedFilterKey)
+		assert.False(t, simulation.Subscriptions[1].Matched)
+		assert.Equal(t, "type", *simulation.Subscriptions[1].RejectedFilterKey)
+	})
+
+	t.Run("Cleanup", func(t *testing.T) {
+		topicRepository := mocks.NewTopicRepository(t)
+		subscriptionRepository := mocks.NewSubscriptionRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		messageRepository := mocks.NewMessageRepository(t)
+		topicMessageRepository := mocks.NewTopicMessageRepository(t)
+		topicStatsRepository := mocks.NewTopicStatsRepository(t)
+		topicService := NewTopic(topicRepository, subscriptionRepository, queueRepository, messageRepository, topicMessageRepository, topicStatsRepository, 5)
+		topic := makeTopic("my-topic")
+
+		topicRepository.On("Get", ctx, topic.ID).Return(topic, nil)
+		topicMessageRepository.On("Cleanup", ctx, topic.ID, mock.Anything).Return(nil)
+
+		err := topicService.Cleanup(ctx, topic.ID)
+		assert.Nil(t, err)
+	})
+
+	t.Run("Stats", func(t *testing.T) {
+		topicRepository := mocks.NewTopicRepository(t)
+		subscriptionRepository := mocks.NewSubscriptionRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		messageRepository := mocks.NewMessageRepository(t)
+		topicMessageRepository := mocks.NewTopicMessageRepository(t)
+		topicStatsRepository := mocks.NewTopicStatsRepository(t)
+		topicService := NewTopic(topicRepository, subscriptionRepository, queueRepository, messageRepository, topicMessageRepository, topicStatsRepository, 5)
+		topic := makeTopic("my-topic")
+
+		topicRepository.On("Get", ctx, topic.ID).Return(topic, nil)
+		topicStatsRepository.On("Stats", ctx, topic.ID, mock.Anything).Return(func(ctx context.Context, topicID string, since time.Time) (*domain.TopicStatsWindow, error) {
+			return &domain.TopicStatsWindow{NumPublishedMessages: 1, Subscriptions: []*domain.SubscriptionStats{}}, nil
+		})
+
+		stats, err := topicService.Stats(ctx, topic.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, topic.ID, stats.TopicID)
+		assert.Len(t, stats.Windows, len(domain.TopicStatsWindowsSeconds))
+		for i, windowSeconds := range domain.TopicStatsWindowsSeconds {
+			assert.Equal(t, windowSeconds, stats.Windows[i].WindowSeconds)
+			assert.Equal(t, uint(1), stats.Windows[i].NumPublishedMessages)
+		}
+	})
+
+	t.Run("GetPublish", func(t *testing.T) {
+		topicRepository := mocks.NewTopicRepository(t)
+		subscriptionRepository := mocks.NewSubscriptionRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		messageRepository := mocks.NewMessageRepository(t)
+		topicMessageRepository := mocks.NewTopicMessageRepository(t)
+		topicStatsRepository := mocks.NewTopicStatsRepository(t)
+		topicService := NewTopic(topicRepository, subscriptionRepository, queueRepository, messageRepository, topicMessageRepository, topicStatsRepository, 5)
+		topic := makeTopic("my-topic")
+		queue := makeQueue("my-queue")
+		subscription := makeSubscription("my-subscription", topic.ID, queue.ID)
+		message := &domain.Message{Body: "my-message-body"}
+		message.Enqueue(queue, time.Now().UTC())
+		message.SetSource("my-publish", topic.ID, subscription)
+
+		topicRepository.On("Get", ctx, topic.ID).Return(topic, nil)
+		messageRepository.On("ListByPublish", ctx, topic.ID, "my-publish", uint(0), uint(50)).Return([]*domain.Message{message}, nil)
+		messageRepository.On("ListByPublish", ctx, topic.ID, "my-publish", uint(50), uint(50)).Return([]*domain.Message{}, nil)
+
+		lineage, err := topicService.GetPublish(ctx, topic.ID, "my-publish")
+		assert.Nil(t, err)
+		assert.Equal(t, "my-publish", lineage.ID)
+		assert.Equal(t, topic.ID, lineage.TopicID)
+		assert.Len(t, lineage.Messages, 1)
+		assert.Equal(t, message.ID, lineage.Messages[0].ID)
+		assert.Equal(t, queue.ID, lineage.Messages[0].QueueID)
+		assert.Equal(t, subscription.ID, *lineage.Messages[0].SourceSubscriptionID)
+		assert.Equal(t, domain.MessageStatusAvailable, lineage.Messages[0].Status)
+	})
+
+	t.Run("GetPublish with publish not found", func(t *testing.T) {
+		topicRepository := mocks.NewTopicRepository(t)
+		subscriptionRepository := mocks.NewSubscriptionRepository(t)
+		queueRepository := mocks.NewQueueRepository(t)
+		messageRepository := mocks.NewMessageRepository(t)
+		topicMessageRepository := mocks.NewTopicMessageRepository(t)
+		topicStatsRepository := mocks.NewTopicStatsRepository(t)
+		topicService := NewTopic(topicRepository, subscriptionRepository, queueRepository, messageRepository, topicMessageRepository, topicStatsRepository, 5)
+		topic := makeTopic("my-topic")
+
+		topicRepository.On("Get", ctx, topic.ID).Return(topic, nil)
+		messageRepository.On("ListByPublish", ctx, topic.ID, "my-publish", uint(0), uint(50)).Return([]*domain.Message{}, nil)
+
+		_, err := topicService.GetPublish(ctx, topic.ID, "my-publish")
+		assert.ErrorIs(t, err, domain.ErrTopicPublishNotFound)
 	})
 }